@@ -71,6 +71,33 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// LiveChatAuthMiddleware lets in the agents with their JWT, like
+// AuthMiddleware, and the anonymous reporter of a live chat with the
+// X-Live-Chat-Token header, which is checked against the interaction later on.
+func LiveChatAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if jwt.ExtractToken(c.Request) != "" {
+			AuthMiddleware()(c)
+			return
+		}
+
+		liveChatToken := c.GetHeader("X-Live-Chat-Token")
+		if liveChatToken == "" {
+			errorMessage := map[string]string{
+				"errorStatus":  enum.LIVE_CHAT_SESSION_INVALID_STATUS,
+				"errorMessage": enum.LIVE_CHAT_SESSION_INVALID_MESSAGE,
+			}
+			response.ResponseUnauthorized(c, "", errorMessage)
+			c.Abort()
+			return
+		}
+
+		c.Set("live_chat_token", liveChatToken)
+
+		c.Next()
+	}
+}
+
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		errorMessage := make(map[string]string)
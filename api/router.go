@@ -5,13 +5,13 @@ import (
 	"Omnichannel-CRM/domain/handler"
 	"Omnichannel-CRM/domain/repository"
 	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
 	"Omnichannel-CRM/package/logger"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/gmail/v1"
 	"gorm.io/gorm"
 )
 
@@ -36,21 +36,43 @@ func SetupRouter(dbCRM *gorm.DB, dbOmnichannel *gorm.DB) *gin.Engine {
 	userRepo := repository.NewUserRepository(dbCRM)
 	reporterRepo := repository.NewReporterRepository(dbOmnichannel)
 
-	gmailService := service.NewGmailService()
-	emailRepo := repository.NewEmailRepository(dbOmnichannel, gmailService)
-	threadRepo := repository.NewThreadRepository(dbOmnichannel)
+	channelAccountRepo := repository.NewChannelAccountRepository(dbCRM)
+	agentCapacityRepo := repository.NewAgentCapacityRepository(dbOmnichannel)
+	capacityService := service.NewCapacityService(agentCapacityRepo, interactionRepo, userRepo)
+	routingPolicyRepo := repository.NewRoutingPolicyRepository(dbOmnichannel)
+	chatbotFlowRepo := repository.NewChatbotFlowRepository(dbOmnichannel)
+	routingService := service.NewRoutingService(routingPolicyRepo, channelAccountRepo, interactionRepo, userRepo, chatbotFlowRepo, capacityService)
 
-	emailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo)
+	threadRepo := repository.NewThreadRepository(dbOmnichannel)
 
-	interactionService := service.NewInteractionService(interactionRepo, messageRepo, userRepo, reporterRepo, emailService, threadRepo)
-	interactionHandler := handler.NewInteractionHandler(interactionService)
+	csatSurveyRepo := repository.NewCsatSurveyRepository(dbOmnichannel)
+
+	emailRepo := repository.NewEmailRepository(dbOmnichannel, service.NewGmailService())
+	gmailEmailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, service.NewAttachmentStore())
+	imapEmailService := service.NewImapEmailService(interactionRepo, messageRepo, reporterRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, repository.NewImapMailboxRepository(dbOmnichannel), repository.NewWebhookInboxRepository(dbOmnichannel))
+	emailService := service.NewMailboxEmailService(gmailEmailService, imapEmailService, interactionRepo, channelAccountRepo)
+
+	interactionTransferRepo := repository.NewInteractionTransferRepository(dbOmnichannel)
+	slaPolicyRepo := repository.NewSlaPolicyRepository(dbOmnichannel)
+	slaService := service.NewSlaService(slaPolicyRepo, interactionRepo, messageRepo)
+	channelAdapterRegistry := service.NewDefaultChannelAdapterRegistry(channelAccountRepo, interactionRepo, messageRepo, reporterRepo, emailService)
+	interactionService := service.NewInteractionService(interactionRepo, messageRepo, userRepo, reporterRepo, channelAccountRepo, emailService, threadRepo, routingService, capacityService, interactionTransferRepo, slaService, channelAdapterRegistry, service.NewAttachmentStore())
+	businessCalendarRepo := repository.NewBusinessCalendarRepository(dbOmnichannel)
+	businessHoursService := service.NewBusinessHoursService(businessCalendarRepo, channelAccountRepo, interactionRepo, messageRepo, interactionService)
+	cannedResponseRepo := repository.NewCannedResponseRepository(dbOmnichannel)
+	cannedResponseService := service.NewCannedResponseService(cannedResponseRepo, interactionRepo, reporterRepo, userRepo)
+	chatbotService := service.NewChatbotService(chatbotFlowRepo, channelAccountRepo, interactionRepo, reporterRepo, routingService, interactionService)
+	csatService := service.NewCsatService(csatSurveyRepo, channelAccountRepo, interactionRepo, interactionService)
+	interactionHandler := handler.NewInteractionHandler(interactionService, businessHoursService, cannedResponseService, chatbotService, csatService)
 
 	interactionApi := router.Group("interaction/")
 	{
 		interactionApi.GET("/list", middleware.AuthMiddleware(), interactionHandler.GetDashboardInteractionList)
 		interactionApi.PUT("/claim", middleware.AuthMiddleware(), interactionHandler.ClaimInteractionByAgent)
+		interactionApi.PUT("/transfer", middleware.AuthMiddleware(), interactionHandler.TransferInteraction)
+		interactionApi.GET("/transfers", middleware.AuthMiddleware(), interactionHandler.GetInteractionTransferList)
 		interactionApi.POST("/messenger/send", middleware.AuthMiddleware(), interactionHandler.MessengerSendMessage)
-		interactionApi.POST("/live-chat/send", interactionHandler.LiveChatSendMessage)
+		interactionApi.POST("/live-chat/send", middleware.LiveChatAuthMiddleware(), interactionHandler.LiveChatSendMessage)
 		interactionApi.GET("/messages", interactionHandler.GetInteractionMessages)
 		interactionApi.GET("/my", middleware.AuthMiddleware(), interactionHandler.GetAgentInteractions)
 		interactionApi.PUT("/close", middleware.AuthMiddleware(), interactionHandler.CloseInteractionByAgent)
@@ -69,7 +91,7 @@ func SetupRouter(dbCRM *gorm.DB, dbOmnichannel *gorm.DB) *gin.Engine {
 		reporterApi.PUT("/update", reporterHandler.UpdateReporter)
 	}
 
-	agentService := service.NewAgentService(userRepo, interactionRepo)
+	agentService := service.NewAgentService(userRepo, interactionRepo, csatSurveyRepo, capacityService)
 	agentHandler := handler.NewAgentHandler(agentService)
 
 	agentApi := router.Group("/agent")
@@ -77,22 +99,127 @@ func SetupRouter(dbCRM *gorm.DB, dbOmnichannel *gorm.DB) *gin.Engine {
 		agentApi.GET("/list", agentHandler.GetDashboardAgentList)
 	}
 
-	channelAccountRepo := repository.NewChannelAccountRepository(dbCRM)
 	channelAccountService := service.NewChannelAccountService(channelAccountRepo)
 	channelAccountHandler := handler.NewChannelAccountHandler(channelAccountService)
 	channelAccountApi := router.Group("/channel-account")
 	{
-		channelAccountApi.POST("/create", channelAccountHandler.CreateChannelAccount)
-		channelAccountApi.GET("/list", channelAccountHandler.GetChannelAccountList)
-		channelAccountApi.GET("/get", channelAccountHandler.GetChannelAccountById)
-		channelAccountApi.PUT("/update", channelAccountHandler.UpdateChannelAccount)
-		channelAccountApi.DELETE("/delete", channelAccountHandler.DeleteChannelAccount)
+		channelAccountApi.POST("/create", middleware.AdminAuthMiddleware(), channelAccountHandler.CreateChannelAccount)
+		channelAccountApi.GET("/list", middleware.AdminAuthMiddleware(), channelAccountHandler.GetChannelAccountList)
+		channelAccountApi.GET("/get", middleware.AdminAuthMiddleware(), channelAccountHandler.GetChannelAccountById)
+		channelAccountApi.PUT("/update", middleware.AdminAuthMiddleware(), channelAccountHandler.UpdateChannelAccount)
+		channelAccountApi.DELETE("/delete", middleware.AdminAuthMiddleware(), channelAccountHandler.DeleteChannelAccount)
+	}
+
+	routingPolicyHandler := handler.NewRoutingPolicyHandler(routingService)
+	routingPolicyApi := router.Group("/routing-policy")
+	{
+		routingPolicyApi.POST("/create", middleware.AdminAuthMiddleware(), routingPolicyHandler.CreateRoutingPolicy)
+		routingPolicyApi.GET("/list", middleware.AdminAuthMiddleware(), routingPolicyHandler.GetRoutingPolicyList)
+		routingPolicyApi.GET("/get", middleware.AdminAuthMiddleware(), routingPolicyHandler.GetRoutingPolicyById)
+		routingPolicyApi.PUT("/update", middleware.AdminAuthMiddleware(), routingPolicyHandler.UpdateRoutingPolicy)
+		routingPolicyApi.DELETE("/delete", middleware.AdminAuthMiddleware(), routingPolicyHandler.DeleteRoutingPolicy)
+	}
+
+	agentCapacityHandler := handler.NewAgentCapacityHandler(capacityService)
+	agentCapacityApi := router.Group("/agent-capacity")
+	{
+		agentCapacityApi.POST("/create", middleware.AdminAuthMiddleware(), agentCapacityHandler.CreateAgentCapacity)
+		agentCapacityApi.GET("/list", middleware.AdminAuthMiddleware(), agentCapacityHandler.GetAgentCapacityList)
+		agentCapacityApi.PUT("/update", middleware.AdminAuthMiddleware(), agentCapacityHandler.UpdateAgentCapacity)
+		agentCapacityApi.DELETE("/delete", middleware.AdminAuthMiddleware(), agentCapacityHandler.DeleteAgentCapacity)
+	}
+
+	go slaService.StartSlaScheduler()
+
+	lifecycleService := service.NewLifecycleService(interactionRepo, messageRepo, interactionService, chatbotService)
+	go lifecycleService.StartLifecycleScheduler()
+
+	slaPolicyHandler := handler.NewSlaPolicyHandler(slaService)
+	slaPolicyApi := router.Group("/sla-policy")
+	{
+		slaPolicyApi.POST("/create", middleware.AdminAuthMiddleware(), slaPolicyHandler.CreateSlaPolicy)
+		slaPolicyApi.GET("/list", middleware.AdminAuthMiddleware(), slaPolicyHandler.GetSlaPolicyList)
+		slaPolicyApi.PUT("/update", middleware.AdminAuthMiddleware(), slaPolicyHandler.UpdateSlaPolicy)
+		slaPolicyApi.DELETE("/delete", middleware.AdminAuthMiddleware(), slaPolicyHandler.DeleteSlaPolicy)
+		slaPolicyApi.GET("/escalation/list", middleware.AdminAuthMiddleware(), slaPolicyHandler.GetSlaEscalationList)
+	}
+
+	businessCalendarHandler := handler.NewBusinessCalendarHandler(businessHoursService)
+	businessCalendarApi := router.Group("/business-calendar")
+	{
+		businessCalendarApi.POST("/create", middleware.AdminAuthMiddleware(), businessCalendarHandler.CreateBusinessCalendar)
+		businessCalendarApi.GET("/list", middleware.AdminAuthMiddleware(), businessCalendarHandler.GetBusinessCalendarList)
+		businessCalendarApi.GET("/get", middleware.AdminAuthMiddleware(), businessCalendarHandler.GetBusinessCalendarById)
+		businessCalendarApi.PUT("/update", middleware.AdminAuthMiddleware(), businessCalendarHandler.UpdateBusinessCalendar)
+		businessCalendarApi.DELETE("/delete", middleware.AdminAuthMiddleware(), businessCalendarHandler.DeleteBusinessCalendar)
+	}
+
+	cannedResponseHandler := handler.NewCannedResponseHandler(cannedResponseService)
+	cannedResponseApi := router.Group("/canned-response")
+	{
+		cannedResponseApi.POST("/create", middleware.AuthMiddleware(), cannedResponseHandler.CreateCannedResponse)
+		cannedResponseApi.GET("/list", middleware.AdminAuthMiddleware(), cannedResponseHandler.GetCannedResponseList)
+		cannedResponseApi.GET("/my", middleware.AuthMiddleware(), cannedResponseHandler.GetAvailableCannedResponseList)
+		cannedResponseApi.GET("/get", middleware.AuthMiddleware(), cannedResponseHandler.GetCannedResponseById)
+		cannedResponseApi.PUT("/update", middleware.AuthMiddleware(), cannedResponseHandler.UpdateCannedResponse)
+		cannedResponseApi.DELETE("/delete", middleware.AuthMiddleware(), cannedResponseHandler.DeleteCannedResponse)
+	}
+
+	chatbotFlowHandler := handler.NewChatbotFlowHandler(chatbotService)
+	chatbotFlowApi := router.Group("/chatbot-flow")
+	{
+		chatbotFlowApi.POST("/create", middleware.AdminAuthMiddleware(), chatbotFlowHandler.CreateChatbotFlow)
+		chatbotFlowApi.GET("/list", middleware.AdminAuthMiddleware(), chatbotFlowHandler.GetChatbotFlowList)
+		chatbotFlowApi.GET("/get", middleware.AdminAuthMiddleware(), chatbotFlowHandler.GetChatbotFlowById)
+		chatbotFlowApi.PUT("/update", middleware.AdminAuthMiddleware(), chatbotFlowHandler.UpdateChatbotFlow)
+		chatbotFlowApi.DELETE("/delete", middleware.AdminAuthMiddleware(), chatbotFlowHandler.DeleteChatbotFlow)
+	}
+
+	csatHandler := handler.NewCsatHandler(csatService)
+	csatApi := router.Group("/csat")
+	{
+		csatApi.GET("/list", middleware.AdminAuthMiddleware(), csatHandler.GetCsatSurveyList)
+	}
+
+	rejectedWebhookRepo := repository.NewRejectedWebhookRepository(dbOmnichannel)
+	rejectedWebhookService := service.NewRejectedWebhookService(rejectedWebhookRepo)
+	rejectedWebhookHandler := handler.NewRejectedWebhookHandler(rejectedWebhookService)
+	rejectedWebhookApi := router.Group("/rejected-webhook")
+	{
+		rejectedWebhookApi.GET("/list", middleware.AdminAuthMiddleware(), rejectedWebhookHandler.GetRejectedWebhookList)
+	}
+
+	whatsappTemplateRepo := repository.NewWhatsappTemplateRepository(dbOmnichannel)
+	whatsappTemplateService := service.NewWhatsappTemplateService(whatsappTemplateRepo, channelAccountRepo, interactionService)
+	whatsappTemplateHandler := handler.NewWhatsappTemplateHandler(whatsappTemplateService, interactionService)
+	whatsappTemplateApi := router.Group("/whatsapp-template")
+	{
+		whatsappTemplateApi.POST("/sync", middleware.AdminAuthMiddleware(), whatsappTemplateHandler.SyncWhatsappTemplates)
+		whatsappTemplateApi.GET("/list", middleware.AuthMiddleware(), whatsappTemplateHandler.GetWhatsappTemplateList)
+		whatsappTemplateApi.POST("/send", middleware.AuthMiddleware(), whatsappTemplateHandler.SendWhatsappTemplate)
+	}
+
+	outboundConversationService := service.NewOutboundConversationService(interactionRepo, messageRepo, reporterRepo, channelAccountRepo, capacityService, emailService, interactionService, whatsappTemplateService)
+	outboundConversationHandler := handler.NewOutboundConversationHandler(outboundConversationService, interactionService)
+	outboundConversationApi := router.Group("/outbound-conversation")
+	{
+		outboundConversationApi.POST("/start", middleware.AuthMiddleware(), outboundConversationHandler.StartOutboundConversation)
+	}
+
+	webhookInboxRepo := repository.NewWebhookInboxRepository(dbOmnichannel)
+	webhookInboxService := service.NewWebhookInboxService(webhookInboxRepo)
+	webhookInboxHandler := handler.NewWebhookInboxHandler(webhookInboxService)
+	webhookInboxApi := router.Group("/webhook-inbox")
+	{
+		webhookInboxApi.GET("/list", middleware.AdminAuthMiddleware(), webhookInboxHandler.GetWebhookInboxList)
+		webhookInboxApi.GET("/get", middleware.AdminAuthMiddleware(), webhookInboxHandler.GetWebhookInboxById)
+		webhookInboxApi.PUT("/replay", middleware.AdminAuthMiddleware(), webhookInboxHandler.ReplayWebhookInbox)
 	}
 
 	return router
 }
 
-func SetupWebhookRouter(dbOmnichannel *gorm.DB) *gin.Engine {
+func SetupWebhookRouter(dbCRM *gorm.DB, dbOmnichannel *gorm.DB) *gin.Engine {
 	router := gin.Default()
 
 	corsConfig := cors.DefaultConfig()
@@ -111,28 +238,61 @@ func SetupWebhookRouter(dbOmnichannel *gorm.DB) *gin.Engine {
 	interactionRepo := repository.NewInteractionRepository(dbOmnichannel)
 	messageRepo := repository.NewMessageRepository(dbOmnichannel)
 	reporterRepo := repository.NewReporterRepository(dbOmnichannel)
-	metaWebhookService := service.NewMetaWebhookService(interactionRepo, messageRepo, reporterRepo)
-	metaWebhookHandler := handler.NewMetaWebhookHandler(metaWebhookService)
+	userRepo := repository.NewUserRepository(dbCRM)
+	channelAccountRepo := repository.NewChannelAccountRepository(dbCRM)
+	agentCapacityRepo := repository.NewAgentCapacityRepository(dbOmnichannel)
+	capacityService := service.NewCapacityService(agentCapacityRepo, interactionRepo, userRepo)
+	routingPolicyRepo := repository.NewRoutingPolicyRepository(dbOmnichannel)
+	chatbotFlowRepo := repository.NewChatbotFlowRepository(dbOmnichannel)
+	routingService := service.NewRoutingService(routingPolicyRepo, channelAccountRepo, interactionRepo, userRepo, chatbotFlowRepo, capacityService)
 
-	gmailService := service.NewGmailService()
-	emailRepo := repository.NewEmailRepository(dbOmnichannel, gmailService)
 	threadRepo := repository.NewThreadRepository(dbOmnichannel)
-	emailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo)
-
-	watchRes, err := gmailService.Users.Watch("me", &gmail.WatchRequest{
-		LabelIds:  []string{"INBOX", "UNREAD"},
-		TopicName: "projects/email-api-406816/topics/gmail-webhook",
-	}).Do()
+	csatSurveyRepo := repository.NewCsatSurveyRepository(dbOmnichannel)
+
+	emailRepo := repository.NewEmailRepository(dbOmnichannel, service.NewGmailService())
+	gmailEmailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, service.NewAttachmentStore())
+	imapEmailService := service.NewImapEmailService(interactionRepo, messageRepo, reporterRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, repository.NewImapMailboxRepository(dbOmnichannel), repository.NewWebhookInboxRepository(dbOmnichannel))
+	emailService := service.NewMailboxEmailService(gmailEmailService, imapEmailService, interactionRepo, channelAccountRepo)
+
+	interactionTransferRepo := repository.NewInteractionTransferRepository(dbOmnichannel)
+	slaPolicyRepo := repository.NewSlaPolicyRepository(dbOmnichannel)
+	slaService := service.NewSlaService(slaPolicyRepo, interactionRepo, messageRepo)
+	channelAdapterRegistry := service.NewDefaultChannelAdapterRegistry(channelAccountRepo, interactionRepo, messageRepo, reporterRepo, emailService)
+	interactionService := service.NewInteractionService(interactionRepo, messageRepo, userRepo, reporterRepo, channelAccountRepo, emailService, threadRepo, routingService, capacityService, interactionTransferRepo, slaService, channelAdapterRegistry, service.NewAttachmentStore())
+	businessCalendarRepo := repository.NewBusinessCalendarRepository(dbOmnichannel)
+	businessHoursService := service.NewBusinessHoursService(businessCalendarRepo, channelAccountRepo, interactionRepo, messageRepo, interactionService)
+
+	chatbotService := service.NewChatbotService(chatbotFlowRepo, channelAccountRepo, interactionRepo, reporterRepo, routingService, interactionService)
+
+	rejectedWebhookRepo := repository.NewRejectedWebhookRepository(dbOmnichannel)
+	messageStatusRepo := repository.NewMessageStatusRepository(dbOmnichannel)
+	channelWebhookService := service.NewChannelWebhookService(channelAdapterRegistry, interactionRepo, messageRepo, reporterRepo, routingService, businessHoursService, chatbotService, csatSurveyRepo, rejectedWebhookRepo, messageStatusRepo)
+
+	webhookInboxRepo := repository.NewWebhookInboxRepository(dbOmnichannel)
+	webhookInboxService := service.NewWebhookInboxService(webhookInboxRepo)
+	webhookInboxService.RegisterProcessor(channelWebhookService, enum.FACEBOOK, enum.IG, enum.WA, enum.TELEGRAM)
+
+	// Gmail mailboxes push their changes to the webhook, IMAP mailboxes are
+	// read by the ingester, which leaves the emails it can not read as dead
+	// letters to be replayed
+	gmailMailboxRepo := repository.NewGmailMailboxRepository(dbOmnichannel)
+	gmailWatchService := service.NewGmailWatchService(gmailEmailService, gmailMailboxRepo)
+	err := gmailWatchService.RenewGmailWatches(true)
 	if err != nil {
-		fmt.Println("Error when creating watch request to google api")
+		logger.Info(fmt.Sprintf("[FAILED][Gmail Watch] Watch mailboxes: %+v", err))
 	}
+	go gmailWatchService.StartGmailWatchScheduler()
 
-	var gmailHandler handler.GmailHandler
-	if watchRes != nil {
-		gmailHandler = handler.NewGmailHandler(emailService, int(watchRes.HistoryId))
-	} else {
-		gmailHandler = handler.NewGmailHandler(emailService, 0)
-	}
+	gmailWebhookProcessor := service.NewGmailWebhookProcessor(gmailEmailService, gmailMailboxRepo)
+	webhookInboxService.RegisterProcessor(gmailWebhookProcessor, enum.EMAIL)
+
+	webhookInboxService.RegisterProcessor(imapEmailService, enum.IMAP)
+	go imapEmailService.StartImapIngester()
+	go webhookInboxService.StartWebhookInboxWorkers()
+
+	metaWebhookHandler := handler.NewMetaWebhookHandler(channelWebhookService, webhookInboxService)
+	telegramWebhookHandler := handler.NewTelegramWebhookHandler(channelWebhookService, webhookInboxService)
+	gmailHandler := handler.NewGmailHandler(webhookInboxService)
 
 	metaWebhookApi := router.Group("webhook-meta/")
 	{
@@ -148,10 +308,19 @@ func SetupWebhookRouter(dbOmnichannel *gorm.DB) *gin.Engine {
 		whatsappWebhookApi.GET("", metaWebhookHandler.ValidateVerificationRequest)
 	}
 
+	telegramWebhookApi := router.Group("/webhook-telegram")
+	{
+		telegramWebhookApi.POST("/:bot_id", telegramWebhookHandler.TelegramMessageInteractionHandler)
+	}
+
 	gmailWebhook := router.Group("/webhook-gmail")
 	{
 		gmailWebhook.POST("", gmailHandler.Webhook)
 	}
 
+	emailSubscriptionHandler := handler.NewEmailSubscriptionHandler(gmailWatchService)
+	router.GET("/health/email", emailSubscriptionHandler.GetEmailSubscriptionHealth)
+	router.GET("/health/email/detail", middleware.AdminAuthMiddleware(), emailSubscriptionHandler.GetEmailSubscriptionHealthDetail)
+
 	return router
 }
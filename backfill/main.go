@@ -0,0 +1,116 @@
+package main
+
+import (
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/config"
+	"Omnichannel-CRM/package/database"
+	"Omnichannel-CRM/package/logger"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func init() {
+	config.GetConfig()
+}
+
+// parseBackfillTime reads an RFC3339 time or a date.
+func parseBackfillTime(value string) (time.Time, error) {
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsedTime, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// Re-syncs the inbox of a Gmail mailbox over a time window, for a history id
+// Gmail no longer keeps. When the window ends now (-before left out) the
+// history cursor of the mailbox is moved to the current history, a window
+// ending in the past leaves it as it is since the emails received after it
+// were not read.
+//
+// The cursor is also moved by the webhook service, which takes no lock shared
+// with this command: stop the webhook service while it runs, the Gmail
+// notifications received meanwhile are delivered again once it is back.
+func main() {
+	logger.InitLogger()
+
+	var emailAddress, after, before string
+	flag.StringVar(&emailAddress, "email", "", "Address of the mailbox to backfill")
+	flag.StringVar(&after, "after", "", "Start of the window, RFC3339 or YYYY-MM-DD")
+	flag.StringVar(&before, "before", "", "End of the window, RFC3339 or YYYY-MM-DD (default now, the only window that moves the history cursor)")
+	flag.Parse()
+
+	if emailAddress == "" || after == "" {
+		flag.Usage()
+		return
+	}
+
+	afterTime, err := parseBackfillTime(after)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	beforeTime := time.Now()
+	if before != "" {
+		beforeTime, err = parseBackfillTime(before)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+
+	dbCRM, err := database.InitDB(viper.GetString("Database.CRMDBName"), viper.GetString("Database.CRMDBHost"))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	dbOmnichannel, err := database.InitDB(viper.GetString("Database.OmnichannelDBName"), viper.GetString("Database.Host"))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	interactionRepo := repository.NewInteractionRepository(dbOmnichannel)
+	messageRepo := repository.NewMessageRepository(dbOmnichannel)
+	reporterRepo := repository.NewReporterRepository(dbOmnichannel)
+	userRepo := repository.NewUserRepository(dbCRM)
+	channelAccountRepo := repository.NewChannelAccountRepository(dbCRM)
+	agentCapacityRepo := repository.NewAgentCapacityRepository(dbOmnichannel)
+	capacityService := service.NewCapacityService(agentCapacityRepo, interactionRepo, userRepo)
+	routingPolicyRepo := repository.NewRoutingPolicyRepository(dbOmnichannel)
+	chatbotFlowRepo := repository.NewChatbotFlowRepository(dbOmnichannel)
+	routingService := service.NewRoutingService(routingPolicyRepo, channelAccountRepo, interactionRepo, userRepo, chatbotFlowRepo, capacityService)
+
+	threadRepo := repository.NewThreadRepository(dbOmnichannel)
+	csatSurveyRepo := repository.NewCsatSurveyRepository(dbOmnichannel)
+	emailRepo := repository.NewEmailRepository(dbOmnichannel, service.NewGmailService())
+	emailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, service.NewAttachmentStore())
+	gmailMailboxRepo := repository.NewGmailMailboxRepository(dbOmnichannel)
+
+	historyId, err := emailService.BackfillMailbox(emailAddress, afterTime, beforeTime)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if before != "" {
+		logger.Info(fmt.Sprintf("[Gmail Backfill] %s synced from %s to %s, history cursor left as it is", emailAddress, afterTime.Format(time.RFC3339), beforeTime.Format(time.RFC3339)))
+		return
+	}
+
+	_, err = gmailMailboxRepo.SaveGmailMailboxHistoryId(emailAddress, historyId)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	logger.Info(fmt.Sprintf("[Gmail Backfill] %s synced from %s to %s, history id %d", emailAddress, afterTime.Format(time.RFC3339), beforeTime.Format(time.RFC3339), historyId))
+}
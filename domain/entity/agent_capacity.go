@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+type AgentCapacity struct {
+	gorm.Model
+	AgentId    string `json:"agent_id"`
+	Role       int    `json:"role"`
+	ChatLimit  int64  `json:"chat_limit"`
+	EmailLimit int64  `json:"email_limit"`
+}
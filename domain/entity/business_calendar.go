@@ -0,0 +1,13 @@
+package entity
+
+import "gorm.io/gorm"
+
+type BusinessCalendar struct {
+	gorm.Model
+	ChannelAccountId uint   `json:"channel_account_id"`
+	Timezone         string `json:"timezone"`
+	WeeklySchedule   string `json:"weekly_schedule" gorm:"type:text"`
+	Holidays         string `json:"holidays" gorm:"type:text"`
+	AutoReplyMessage string `json:"auto_reply_message" gorm:"type:text"`
+	IsActive         bool   `json:"is_active"`
+}
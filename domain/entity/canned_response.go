@@ -0,0 +1,13 @@
+package entity
+
+import "gorm.io/gorm"
+
+type CannedResponse struct {
+	gorm.Model
+	Title            string `json:"title"`
+	Shortcut         string `json:"shortcut"`
+	Content          string `json:"content" gorm:"type:text"`
+	Scope            string `json:"scope"`
+	ChannelAccountId uint   `json:"channel_account_id"`
+	AgentId          string `json:"agent_id"`
+}
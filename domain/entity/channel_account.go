@@ -12,5 +12,17 @@ type ChannelAccount struct {
 	FacebookAccessToken  string `json:"facebook_access_token"`
 	InstagramAccessToken string `json:"instagram_access_token"`
 	WhatsappAccessToken  string `json:"whatsapp_access_token"`
+	MetaAppSecret        string `json:"-"`
+	TelegramBotId        string `json:"telegram_bot_id"`
+	TelegramBotToken     string `json:"-"`
+	TelegramSecretToken  string `json:"-"`
+	EmailAddress         string `json:"email_address"`
+	GmailRefreshToken    string `json:"-"`
+	ImapAddress          string `json:"imap_address"`
+	ImapUsername         string `json:"imap_username"`
+	ImapPassword         string `json:"-"`
+	SmtpAddress          string `json:"smtp_address"`
+	SmtpUsername         string `json:"smtp_username"`
+	SmtpPassword         string `json:"-"`
 	IsLiveChatActive     bool   `json:"is_live_chat_active"`
 }
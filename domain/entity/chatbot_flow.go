@@ -0,0 +1,23 @@
+package entity
+
+import "gorm.io/gorm"
+
+type ChatbotFlow struct {
+	gorm.Model
+	Name             string `json:"name"`
+	ChannelAccountId uint   `json:"channel_account_id"`
+	Platform         string `json:"platform"`
+	Steps            string `json:"steps" gorm:"type:text"`
+	HandoverKeywords string `json:"handover_keywords"`
+	HandoverMessage  string `json:"handover_message" gorm:"type:text"`
+	IsActive         bool   `json:"is_active"`
+}
+
+type ChatbotSession struct {
+	gorm.Model
+	InteractionId uint   `json:"interaction_id" gorm:"uniqueIndex"`
+	ChatbotFlowId uint   `json:"chatbot_flow_id"`
+	CurrentStep   string `json:"current_step"`
+	LastMessageId uint   `json:"last_message_id"`
+	IsCompleted   bool   `json:"is_completed"`
+}
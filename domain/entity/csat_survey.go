@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CsatSurvey struct {
+	gorm.Model
+	InteractionId uint       `json:"interaction_id" gorm:"uniqueIndex"`
+	AgentId       string     `json:"agent_id"`
+	ReporterId    uint       `json:"reporter_id"`
+	Platform      string     `json:"platform"`
+	Status        string     `json:"status"`
+	Score         int64      `json:"score"`
+	Reply         string     `json:"reply" gorm:"type:text"`
+	AnsweredAt    *time.Time `json:"answered_at"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GmailMailbox keeps the history cursor and the push subscription of a Gmail
+// mailbox. HistoryId is the last history id whose emails were all read, the
+// Watch fields are the ones of the last watch and WatchError is set while its
+// renewal fails.
+type GmailMailbox struct {
+	gorm.Model
+	EmailAddress    string     `json:"email_address" gorm:"uniqueIndex"`
+	HistoryId       uint64     `json:"history_id"`
+	LastSyncedAt    *time.Time `json:"last_synced_at"`
+	WatchHistoryId  uint64     `json:"watch_history_id"`
+	WatchExpiration *time.Time `json:"watch_expiration"`
+	WatchRenewedAt  *time.Time `json:"watch_renewed_at"`
+	WatchError      string     `json:"watch_error" gorm:"type:text"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImapMailbox keeps the cursor of a mailbox read over IMAP. LastUid is the UID
+// of the last email read, it only means something for the UidValidity it was
+// read under.
+type ImapMailbox struct {
+	gorm.Model
+	EmailAddress string     `json:"email_address" gorm:"uniqueIndex"`
+	UidValidity  uint32     `json:"uid_validity"`
+	LastUid      uint32     `json:"last_uid"`
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+}
@@ -8,18 +8,33 @@ import (
 
 type Interaction struct {
 	gorm.Model
-	PlatformId      string    `json:"platform_id"`
-	ReporterId      uint      `json:"reporter_id"`
-	ConversationId  string    `json:"conversation_id"`
-	MentionMediaId  string    `json:"media_id"`
-	MentionMediaUrl string    `json:"media_url"`
-	AgentId         string    `json:"agent_id"`
-	Status          string    `json:"status"`
-	Platform        string    `json:"platform"`
-	InteractionType string    `json:"interaction_type"`
-	Latitude        string    `json:"Latitude"`
-	Longitude       string    `json:"Longitude"`
-	Duration        time.Time `json:"duration"`
+	PlatformId      string `json:"platform_id"`
+	ReporterId      uint   `json:"reporter_id"`
+	ConversationId  string `json:"conversation_id"`
+	MentionMediaId  string `json:"media_id"`
+	MentionMediaUrl string `json:"media_url"`
+	AgentId         string `json:"agent_id"`
+	Status          string `json:"status"`
+	Platform        string `json:"platform"`
+	InteractionType string `json:"interaction_type"`
+	RoutingStrategy string `json:"routing_strategy"`
+	Latitude        string `json:"Latitude"`
+	Longitude       string `json:"Longitude"`
+
+	ClaimedAt       *time.Time `json:"claimed_at"`
+	FirstResponseAt *time.Time `json:"first_response_at"`
+	ClosedAt        *time.Time `json:"closed_at"`
+
+	// durations in seconds, stored when the interaction is closed
+	HandleTimeSeconds    int64 `json:"handle_time_seconds"`
+	WaitTimeSeconds      int64 `json:"wait_time_seconds"`
+	TotalOpenTimeSeconds int64 `json:"total_open_time_seconds"`
+
+	// score of the answered CSAT survey
+	CsatScore *int64 `json:"csat_score"`
+
+	// secret of the live chat session, the reporter sends it with its messages
+	LiveChatToken string `json:"-"`
 }
 
 type GeotagInformation struct {
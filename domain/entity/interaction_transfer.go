@@ -0,0 +1,12 @@
+package entity
+
+import "gorm.io/gorm"
+
+type InteractionTransfer struct {
+	gorm.Model
+	InteractionId uint   `json:"interaction_id"`
+	FromAgentId   string `json:"from_agent_id"`
+	ToAgentId     string `json:"to_agent_id"`
+	TransferredBy string `json:"transferred_by"`
+	Note          string `json:"note"`
+}
@@ -13,10 +13,16 @@ type Message struct {
 	RecipientId      string    `json:"recipient_id"`
 	MetaMessageId    string    `json:"mid"`
 	Message          string    `json:"message"`
+	HtmlMessage      string    `json:"html_message" gorm:"type:text"`
 	MessageTimestamp time.Time `json:"message_timestamp"`
 	AttachmentType   string    `json:"attachment_type"`
 	AttachmentUrl    string    `json:"attachment_url"`
 	SentBy           string    `json:"sent_by"`
 	IsRead           bool      `json:"is_read"`
 	IsDeleted        bool      `json:"is_deleted"`
+
+	// delivery status of messages sent to the reporter, see MessageStatus
+	DeliveryStatus   string     `json:"delivery_status"`
+	DeliveryStatusAt *time.Time `json:"delivery_status_at"`
+	DeliveryError    string     `json:"delivery_error"`
 }
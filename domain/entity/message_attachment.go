@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// MessageAttachment is a file received with a message. Emails can carry
+// several, the first one is also kept in the AttachmentUrl of the Message.
+// ContentId is set for the inline images the HTML body refers to.
+type MessageAttachment struct {
+	gorm.Model
+	MessageId     uint   `json:"message_id" gorm:"index"`
+	InteractionId uint   `json:"interaction_id" gorm:"index"`
+	Name          string `json:"name"`
+	ContentType   string `json:"content_type"`
+	ContentId     string `json:"content_id"`
+	Size          int64  `json:"size"`
+	Url           string `json:"url"`
+}
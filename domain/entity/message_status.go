@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MessageStatus is a delivery status received for a sent message. Statuses of
+// messages that were not sent from the CRM are kept with MessageId 0.
+type MessageStatus struct {
+	gorm.Model
+	MessageId       uint      `json:"message_id" gorm:"index"`
+	MetaMessageId   string    `json:"mid" gorm:"index"`
+	RecipientId     string    `json:"recipient_id"`
+	Status          string    `json:"status"`
+	ErrorCode       int       `json:"error_code"`
+	ErrorTitle      string    `json:"error_title"`
+	ErrorMessage    string    `json:"error_message"`
+	StatusTimestamp time.Time `json:"status_timestamp"`
+}
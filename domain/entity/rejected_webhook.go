@@ -0,0 +1,15 @@
+package entity
+
+import "gorm.io/gorm"
+
+// RejectedWebhook is a webhook delivery that failed verification, kept for
+// security review.
+type RejectedWebhook struct {
+	gorm.Model
+	Platform   string `json:"platform"`
+	PlatformId string `json:"platform_id"`
+	Reason     string `json:"reason"`
+	Signature  string `json:"signature"`
+	RemoteAddr string `json:"remote_addr"`
+	Payload    string `json:"payload" gorm:"type:text"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+type RoutingPolicy struct {
+	gorm.Model
+	ChannelAccountId    uint   `json:"channel_account_id"`
+	Strategy            string `json:"strategy"`
+	AgentIds            string `json:"agent_ids"`
+	LastAssignedAgentId string `json:"last_assigned_agent_id"`
+}
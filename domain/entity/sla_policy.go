@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SlaPolicy struct {
+	gorm.Model
+	Platform             string `json:"platform"`
+	InteractionType      string `json:"interaction_type"`
+	FirstResponseMinutes int64  `json:"first_response_minutes"`
+	ResolutionMinutes    int64  `json:"resolution_minutes"`
+	NearBreachPercent    int64  `json:"near_breach_percent"`
+}
+
+type SlaEscalation struct {
+	gorm.Model
+	InteractionId  uint      `json:"interaction_id"`
+	SlaPolicyId    uint      `json:"sla_policy_id"`
+	AgentId        string    `json:"agent_id"`
+	EscalationType string    `json:"escalation_type"`
+	Deadline       time.Time `json:"deadline"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookInbox is a verified webhook delivery waiting to be processed by the
+// webhook inbox workers. PayloadHash lets a redelivery of the same payload be
+// acknowledged without being queued twice.
+type WebhookInbox struct {
+	gorm.Model
+	Platform      string     `json:"platform" gorm:"index"`
+	PlatformId    string     `json:"platform_id"`
+	PayloadHash   string     `json:"payload_hash" gorm:"uniqueIndex"`
+	Payload       string     `json:"payload" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"index"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+}
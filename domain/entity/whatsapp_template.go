@@ -0,0 +1,19 @@
+package entity
+
+import "gorm.io/gorm"
+
+// WhatsappTemplate is a message template of a WhatsApp business account, as
+// last synced from Meta. ParameterCount is the number of {{n}} placeholders of
+// the body.
+type WhatsappTemplate struct {
+	gorm.Model
+	WhatsappBusinessId string `json:"whatsapp_business_id" gorm:"uniqueIndex:idx_whatsapp_template"`
+	Name               string `json:"name" gorm:"uniqueIndex:idx_whatsapp_template"`
+	Language           string `json:"language" gorm:"uniqueIndex:idx_whatsapp_template"`
+	MetaTemplateId     string `json:"meta_template_id"`
+	Category           string `json:"category"`
+	Status             string `json:"status"`
+	BodyText           string `json:"body_text" gorm:"type:text"`
+	Components         string `json:"components" gorm:"type:text"`
+	ParameterCount     int    `json:"parameter_count"`
+}
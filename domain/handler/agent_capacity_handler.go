@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AgentCapacityHandler struct {
+	capacityService service.ICapacityService
+}
+
+func NewAgentCapacityHandler(capacityService service.ICapacityService) *AgentCapacityHandler {
+	agentCapacityHandler := AgentCapacityHandler{
+		capacityService: capacityService,
+	}
+	return &agentCapacityHandler
+}
+
+func (ach *AgentCapacityHandler) CreateAgentCapacity(c *gin.Context) {
+	var acm presentation.AgentCapacityModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&acm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Create Agent Capacity] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := acm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Create Agent Capacity] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := ach.capacityService.CreateAgentCapacity(&acm)
+	if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Create Agent Capacity] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (ach *AgentCapacityHandler) GetAgentCapacityList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	result, err := ach.capacityService.GetAgentCapacityList()
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (ach *AgentCapacityHandler) UpdateAgentCapacity(c *gin.Context) {
+	var uacm presentation.UpdateAgentCapacityModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&uacm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Update Agent Capacity] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := uacm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Update Agent Capacity] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := ach.capacityService.UpdateAgentCapacity(&uacm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (ach *AgentCapacityHandler) DeleteAgentCapacity(c *gin.Context) {
+	var dacm presentation.DeleteAgentCapacityModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&dacm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Delete Agent Capacity] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := ach.capacityService.DeleteAgentCapacityById(&dacm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
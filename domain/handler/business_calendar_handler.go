@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessCalendarHandler struct {
+	businessHoursService service.IBusinessHoursService
+}
+
+func NewBusinessCalendarHandler(businessHoursService service.IBusinessHoursService) *BusinessCalendarHandler {
+	businessCalendarHandler := BusinessCalendarHandler{
+		businessHoursService: businessHoursService,
+	}
+	return &businessCalendarHandler
+}
+
+func (bchs *BusinessCalendarHandler) CreateBusinessCalendar(c *gin.Context) {
+	var bcm presentation.BusinessCalendarModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&bcm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Create Business Calendar] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := bcm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Create Business Calendar] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := bchs.businessHoursService.CreateBusinessCalendar(&bcm)
+	if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Create Business Calendar] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (bchs *BusinessCalendarHandler) GetBusinessCalendarList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	result, err := bchs.businessHoursService.GetBusinessCalendarList()
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (bchs *BusinessCalendarHandler) GetBusinessCalendarById(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	businessCalendarIdQuery := c.Query("business_calendar_id")
+	if businessCalendarIdQuery == "" {
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	businessCalendarId, err := strconv.ParseUint(businessCalendarIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Business Calendar] Invalid Value of Query business_calendar_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := bchs.businessHoursService.GetBusinessCalendarById(uint(businessCalendarId))
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (bchs *BusinessCalendarHandler) UpdateBusinessCalendar(c *gin.Context) {
+	var ubcm presentation.UpdateBusinessCalendarModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&ubcm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Update Business Calendar] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := ubcm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Update Business Calendar] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := bchs.businessHoursService.UpdateBusinessCalendar(&ubcm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (bchs *BusinessCalendarHandler) DeleteBusinessCalendar(c *gin.Context) {
+	var dbcm presentation.DeleteBusinessCalendarModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&dbcm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Delete Business Calendar] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := bchs.businessHoursService.DeleteBusinessCalendarById(&dbcm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
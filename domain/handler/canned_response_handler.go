@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CannedResponseHandler struct {
+	cannedResponseService service.ICannedResponseService
+}
+
+func NewCannedResponseHandler(cannedResponseService service.ICannedResponseService) *CannedResponseHandler {
+	cannedResponseHandler := CannedResponseHandler{
+		cannedResponseService: cannedResponseService,
+	}
+	return &cannedResponseHandler
+}
+
+func getTokenChannelAccount(c *gin.Context) (*entity.ChannelAccount, error) {
+	var channelAccount entity.ChannelAccount
+
+	channelAccountJson, err := json.Marshal(c.Keys["channel_account"])
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(channelAccountJson, &channelAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channelAccount, nil
+}
+
+func (crh *CannedResponseHandler) CreateCannedResponse(c *gin.Context) {
+	userId := c.GetString("user_id")
+	role := c.GetInt("role")
+	var crm presentation.CannedResponseModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&crm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Create Canned Response] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := crm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Create Canned Response] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := crh.cannedResponseService.CreateCannedResponse(&crm, userId, role)
+	if errors.Is(err, enum.CANNED_RESPONSE_NOT_ACCESSIBLE) {
+		errorMessage["errorStatus"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS
+		errorMessage["errorMessage"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE
+		response.ResponseForbidden(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Create Canned Response] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (crh *CannedResponseHandler) GetCannedResponseList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	filters, err := presentation.ParseGetListCannedResponseFilters(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Get Canned Response List] Invalid Query Params: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := crh.cannedResponseService.GetCannedResponseList(filters)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (crh *CannedResponseHandler) GetAvailableCannedResponseList(c *gin.Context) {
+	userId := c.GetString("user_id")
+	errorMessage := make(map[string]string)
+
+	channelAccount, err := getTokenChannelAccount(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Get Available Canned Response List] Invalid Channel Account Data from Token: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := crh.cannedResponseService.GetAvailableCannedResponseList(userId, channelAccount.ID)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (crh *CannedResponseHandler) GetCannedResponseById(c *gin.Context) {
+	userId := c.GetString("user_id")
+	role := c.GetInt("role")
+	errorMessage := make(map[string]string)
+
+	cannedResponseIdQuery := c.Query("canned_response_id")
+	if cannedResponseIdQuery == "" {
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	cannedResponseId, err := strconv.ParseUint(cannedResponseIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Canned Response] Invalid Value of Query canned_response_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	channelAccount, err := getTokenChannelAccount(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Get Canned Response] Invalid Channel Account Data from Token: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := crh.cannedResponseService.GetCannedResponseById(uint(cannedResponseId), userId, channelAccount.ID, role)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CANNED_RESPONSE_NOT_ACCESSIBLE) {
+		errorMessage["errorStatus"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS
+		errorMessage["errorMessage"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE
+		response.ResponseForbidden(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (crh *CannedResponseHandler) UpdateCannedResponse(c *gin.Context) {
+	userId := c.GetString("user_id")
+	role := c.GetInt("role")
+	var ucrm presentation.UpdateCannedResponseModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&ucrm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Update Canned Response] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := ucrm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Update Canned Response] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := crh.cannedResponseService.UpdateCannedResponse(&ucrm, userId, role)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CANNED_RESPONSE_NOT_ACCESSIBLE) {
+		errorMessage["errorStatus"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS
+		errorMessage["errorMessage"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE
+		response.ResponseForbidden(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (crh *CannedResponseHandler) DeleteCannedResponse(c *gin.Context) {
+	userId := c.GetString("user_id")
+	role := c.GetInt("role")
+	var dcrm presentation.DeleteCannedResponseModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&dcrm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Delete Canned Response] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := crh.cannedResponseService.DeleteCannedResponseById(&dcrm, userId, role)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CANNED_RESPONSE_NOT_ACCESSIBLE) {
+		errorMessage["errorStatus"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS
+		errorMessage["errorMessage"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE
+		response.ResponseForbidden(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
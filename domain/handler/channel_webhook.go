@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// receiveChannelWebhook handles the webhook delivery of any channel: the
+// platform adapter verifies the signatureHeader, then the delivery is queued
+// in the webhook inbox and acknowledged right away. The webhook inbox workers
+// parse and store the messages.
+func receiveChannelWebhook(c *gin.Context, channelWebhookService service.IChannelWebhookService, webhookInboxService service.IWebhookInboxService, platform string, platformId string, signatureHeader string) {
+	errorMessage := make(map[string]string)
+
+	bodyBytes, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+	bodyString := string(bodyBytes)
+	logger.Info(bodyString)
+
+	defer c.Request.Body.Close()
+
+	delivery := presentation.WebhookDelivery{
+		PlatformId: platformId,
+		Signature:  c.GetHeader(signatureHeader),
+		RemoteAddr: c.ClientIP(),
+		Body:       bodyBytes,
+	}
+
+	err = channelWebhookService.VerifyWebhook(platform, &delivery)
+	if errors.Is(err, enum.WEBHOOK_SIGNATURE_MISSING) || errors.Is(err, enum.INVALID_WEBHOOK_SIGNATURE) || errors.Is(err, enum.WEBHOOK_SECRET_NOT_SET) || errors.Is(err, enum.WEBHOOK_ENTRIES_NOT_MATCH) {
+		errorMessage["errorMessage"] = enum.INVALID_WEBHOOK_SIGNATURE_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_WEBHOOK_SIGNATURE_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Verify Signature: %+v", platform, err))
+		response.ResponseUnauthorized(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
+		errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction]: %+v", platform, err))
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Internal Error: %+v", platform, err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	enqueueWebhook(c, webhookInboxService, platform, platformId, bodyBytes)
+}
+
+// enqueueWebhook queues a delivery in the webhook inbox. Payloads that are not
+// JSON are refused here since no retry would make them processable.
+func enqueueWebhook(c *gin.Context, webhookInboxService service.IWebhookInboxService, platform string, platformId string, body []byte) {
+	errorMessage := make(map[string]string)
+
+	if !json.Valid(body) {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Bind JSON Body: invalid JSON", platform))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := webhookInboxService.EnqueueWebhook(platform, platformId, body)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Enqueue Delivery: %+v", platform, err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChatbotFlowHandler struct {
+	chatbotService service.IChatbotService
+}
+
+func NewChatbotFlowHandler(chatbotService service.IChatbotService) *ChatbotFlowHandler {
+	chatbotFlowHandler := ChatbotFlowHandler{
+		chatbotService: chatbotService,
+	}
+	return &chatbotFlowHandler
+}
+
+func (cfh *ChatbotFlowHandler) CreateChatbotFlow(c *gin.Context) {
+	var cfm presentation.ChatbotFlowModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&cfm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Create Chatbot Flow] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := cfm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Create Chatbot Flow] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := cfh.chatbotService.CreateChatbotFlow(&cfm)
+	if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Create Chatbot Flow] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (cfh *ChatbotFlowHandler) GetChatbotFlowList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	result, err := cfh.chatbotService.GetChatbotFlowList()
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (cfh *ChatbotFlowHandler) GetChatbotFlowById(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	chatbotFlowIdQuery := c.Query("chatbot_flow_id")
+	if chatbotFlowIdQuery == "" {
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	chatbotFlowId, err := strconv.ParseUint(chatbotFlowIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Chatbot Flow] Invalid Value of Query chatbot_flow_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := cfh.chatbotService.GetChatbotFlowById(uint(chatbotFlowId))
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (cfh *ChatbotFlowHandler) UpdateChatbotFlow(c *gin.Context) {
+	var ucfm presentation.UpdateChatbotFlowModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&ucfm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Update Chatbot Flow] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := ucfm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Update Chatbot Flow] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := cfh.chatbotService.UpdateChatbotFlow(&ucfm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (cfh *ChatbotFlowHandler) DeleteChatbotFlow(c *gin.Context) {
+	var dcfm presentation.DeleteChatbotFlowModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&dcfm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Delete Chatbot Flow] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := cfh.chatbotService.DeleteChatbotFlowById(&dcfm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
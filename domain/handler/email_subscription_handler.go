@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailSubscriptionHandler struct {
+	gmailWatchService service.IGmailWatchService
+}
+
+func NewEmailSubscriptionHandler(gmailWatchService service.IGmailWatchService) *EmailSubscriptionHandler {
+	emailSubscriptionHandler := EmailSubscriptionHandler{
+		gmailWatchService: gmailWatchService,
+	}
+	return &emailSubscriptionHandler
+}
+
+// GetEmailSubscriptionHealth answers 503 while a mailbox is not watched, so
+// uptime checks can alert on it. It is public, so only the overall health is
+// given, the mailboxes are listed by GetEmailSubscriptionHealthDetail.
+func (esh *EmailSubscriptionHandler) GetEmailSubscriptionHealth(c *gin.Context) {
+	esh.respondEmailSubscriptionHealth(c, false)
+}
+
+// GetEmailSubscriptionHealthDetail gives the watch of every mailbox, with its
+// history id and the last error of Gmail, to admins only.
+func (esh *EmailSubscriptionHandler) GetEmailSubscriptionHealthDetail(c *gin.Context) {
+	esh.respondEmailSubscriptionHealth(c, true)
+}
+
+func (esh *EmailSubscriptionHandler) respondEmailSubscriptionHealth(c *gin.Context, detail bool) {
+	errorMessage := make(map[string]string)
+
+	result, err := esh.gmailWatchService.GetEmailSubscriptionHealth()
+	if result != nil && !detail {
+		result = map[string]interface{}{"healthy": result["healthy"]}
+	}
+
+	if errors.Is(err, enum.EMAIL_SUBSCRIPTION_UNHEALTHY) {
+		errorMessage["errorStatus"] = enum.EMAIL_SUBSCRIPTION_UNHEALTHY_STATUS
+		errorMessage["errorMessage"] = enum.EMAIL_SUBSCRIPTION_UNHEALTHY_MESSAGE
+		response.ResponseServiceUnavailable(c, result, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Email Subscription Health] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
@@ -6,7 +6,9 @@ import (
 	"Omnichannel-CRM/package/logger"
 	"Omnichannel-CRM/package/presentation"
 	"Omnichannel-CRM/package/response"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -14,14 +16,12 @@ import (
 )
 
 type GmailHandler struct {
-	emailService service.IEmailService
-	historyId    int
+	webhookInboxService service.IWebhookInboxService
 }
 
-func NewGmailHandler(emailService service.IEmailService, historyId int) GmailHandler {
+func NewGmailHandler(webhookInboxService service.IWebhookInboxService) GmailHandler {
 	return GmailHandler{
-		emailService: emailService,
-		historyId:    historyId,
+		webhookInboxService: webhookInboxService,
 	}
 }
 
@@ -49,15 +49,16 @@ type WebhookPayload struct {
 	AttachmentMetaDatas []AttachmentMetaData `json:"attachmentMetaDatas"`
 }
 
-func (handler *GmailHandler) SetHistoryId(historyId int) {
-	handler.historyId = historyId
-}
-
+// Webhook queues the Pub/Sub push notification in the webhook inbox, the
+// history is read by the GmailWebhookProcessor.
 func (handler *GmailHandler) Webhook(c *gin.Context) {
 	var req presentation.GmailInteractionRequest
 	errorMessage := make(map[string]string)
 
-	err := c.BindJSON(&req)
+	bodyBytes, err := io.ReadAll(c.Request.Body)
+	if err == nil {
+		err = json.Unmarshal(bodyBytes, &req)
+	}
 	if err != nil {
 		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
 		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
@@ -68,15 +69,14 @@ func (handler *GmailHandler) Webhook(c *gin.Context) {
 
 	logger.Info(fmt.Sprintf("====== Received Gmail webhook payload: %+v", req))
 
-	historyId, err := handler.emailService.ProcessWebhook(req.Message.Data, handler.historyId)
+	_, err = handler.webhookInboxService.EnqueueWebhook(enum.EMAIL, "", bodyBytes)
 	if err != nil {
 		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
 		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Gmail Message Interaction] Internal Error: %+v", err))
-		// response.ResponseInternalServerError(c, nil, errorMessage)
+		logger.Info(fmt.Sprintf("[FAILED][Gmail Message Interaction] Enqueue Delivery: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
 		return
 	}
 
-	handler.SetHistoryId(int(historyId))
 	c.JSON(http.StatusOK, gin.H{"message": "Webhook received successfully"})
 }
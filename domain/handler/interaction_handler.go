@@ -13,15 +13,24 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type InteractionHandler struct {
-	interactionService service.IInteractionService
+	interactionService    service.IInteractionService
+	businessHoursService  service.IBusinessHoursService
+	cannedResponseService service.ICannedResponseService
+	chatbotService        service.IChatbotService
+	csatService           service.ICsatService
 }
 
-func NewInteractionHandler(interactionService service.IInteractionService) *InteractionHandler {
+func NewInteractionHandler(interactionService service.IInteractionService, businessHoursService service.IBusinessHoursService, cannedResponseService service.ICannedResponseService, chatbotService service.IChatbotService, csatService service.ICsatService) *InteractionHandler {
 	interactionHandler := InteractionHandler{
-		interactionService: interactionService,
+		interactionService:    interactionService,
+		businessHoursService:  businessHoursService,
+		cannedResponseService: cannedResponseService,
+		chatbotService:        chatbotService,
+		csatService:           csatService,
 	}
 	return &interactionHandler
 }
@@ -98,13 +107,20 @@ func (ih *InteractionHandler) ClaimInteractionByAgent(c *gin.Context) {
 		return
 	}
 
-	interaction, err := ih.interactionService.UpdateInteractionStatusByAgent(&cir, userId, enum.IN_PROGRESS)
+	interaction, err := ih.interactionService.ClaimInteraction(&cir, userId)
 	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
 		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
 		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
 		response.ResponseNotFound(c, nil, errorMessage)
 		return
 
+	} else if errors.Is(err, enum.AGENT_CAPACITY_EXCEEDED) {
+		errorMessage["errorStatus"] = enum.AGENT_CAPACITY_EXCEEDED_STATUS
+		errorMessage["errorMessage"] = enum.AGENT_CAPACITY_EXCEEDED_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Claim Interaction] Agent %s reached capacity", userId))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
 	} else if err != nil {
 		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
 		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
@@ -119,7 +135,127 @@ func (ih *InteractionHandler) ClaimInteractionByAgent(c *gin.Context) {
 	response.ResponseWithData(c, result, errorMessage)
 }
 
+func (ih *InteractionHandler) TransferInteraction(c *gin.Context) {
+	userId := c.GetString("user_id")
+	role := c.GetInt("role")
+	var tir presentation.TransferInteractionRequest
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&tir)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Transfer Interaction] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := tir.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Transfer Interaction] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := ih.interactionService.TransferInteraction(&tir, userId, role)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INTERACTION_NOT_IN_PROGRESS) {
+		errorMessage["errorStatus"] = enum.INTERACTION_NOT_IN_PROGRESS_STATUS
+		errorMessage["errorMessage"] = enum.INTERACTION_NOT_IN_PROGRESS_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INTERACTION_NOT_OWNED) {
+		errorMessage["errorStatus"] = enum.INTERACTION_NOT_OWNED_STATUS
+		errorMessage["errorMessage"] = enum.INTERACTION_NOT_OWNED_MESSAGE
+		response.ResponseForbidden(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INVALID_TRANSFER_TARGET) {
+		errorMessage["errorStatus"] = enum.INVALID_TRANSFER_TARGET_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_TRANSFER_TARGET_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.AGENT_CAPACITY_EXCEEDED) {
+		errorMessage["errorStatus"] = enum.AGENT_CAPACITY_EXCEEDED_STATUS
+		errorMessage["errorMessage"] = enum.AGENT_CAPACITY_EXCEEDED_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Transfer Interaction] Agent %s reached capacity", tir.ToAgentId))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Transfer Interaction] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (ih *InteractionHandler) GetInteractionTransferList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	interactionIdQuery := c.Query("interaction_id")
+	if interactionIdQuery == "" {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Interaction Transfers] Invalid Query. Required interaction_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	interactionId, err := strconv.ParseUint(interactionIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Interaction Transfers] Invalid Value of Query interaction_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := ih.interactionService.GetInteractionTransferList(uint(interactionId))
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+// bindSendMessageRequest reads the send request from a JSON body, or from a
+// multipart form when the sender uploads an attachment.
+func bindSendMessageRequest(c *gin.Context, msmr *presentation.MetaSendMessageRequest) error {
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		return c.BindJSON(msmr)
+	}
+
+	err := c.ShouldBind(msmr)
+	if err != nil {
+		return err
+	}
+
+	msmr.Attachment, err = presentation.ParseOutboundAttachment(c)
+	return err
+}
+
 func (ih *InteractionHandler) MessengerSendMessage(c *gin.Context) {
+	userId := c.GetString("user_id")
 	var msmr presentation.MetaSendMessageRequest
 	result := make(map[string]interface{})
 	errorMessage := make(map[string]string)
@@ -145,115 +281,125 @@ func (ih *InteractionHandler) MessengerSendMessage(c *gin.Context) {
 		return
 	}
 
-	err = c.BindJSON(&msmr)
-	if err != nil {
+	err = bindSendMessageRequest(c, &msmr)
+	if errors.Is(err, enum.ATTACHMENT_TOO_LARGE) {
+		errorMessage["errorMessage"] = enum.ATTACHMENT_TOO_LARGE_MESSAGE
+		errorMessage["errorStatus"] = enum.ATTACHMENT_TOO_LARGE_STATUS
+		logger.Info("[FAILED][Messenger Send Message] Attachment Too Large")
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
 		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
 		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
 		logger.Info(fmt.Sprintf("[FAILED][Claim Interaction] Bind JSON Body: %+v", err))
 		response.ResponseBadRequest(c, nil, errorMessage)
 		return
 	}
+	msmr.SentBy = enum.AGENT
 
-	platform := msmr.Platform
-	if platform == enum.FACEBOOK || platform == enum.IG {
-		result, message, err = ih.interactionService.MessengerSendMessagetoMeta(&msmr, &channelAccount)
-		if errors.Is(err, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT) {
-			errorMessage["errorMessage"] = enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseBadRequest(c, nil, errorMessage)
-			return
-
-		} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
-			errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
-			return
+	validation := msmr.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Messenger Send Message] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
 
-		} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
-			errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
+	if msmr.CannedResponseId != 0 {
+		msmr.Message, err = ih.cannedResponseService.RenderCannedResponse(msmr.CannedResponseId, msmr.InteractionId, userId, channelAccount.ID)
+		if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+			errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+			errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+			response.ResponseNotFound(c, nil, errorMessage)
 			return
 
-		} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
-			errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseBadRequest(c, nil, errorMessage)
+		} else if errors.Is(err, enum.CANNED_RESPONSE_NOT_ACCESSIBLE) {
+			errorMessage["errorStatus"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS
+			errorMessage["errorMessage"] = enum.CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE
+			response.ResponseForbidden(c, nil, errorMessage)
 			return
 
 		} else if err != nil {
-			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
 			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta] Internal Error: %+v", err))
+			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message] Render Canned Response: %+v", err))
 			response.ResponseInternalServerError(c, nil, errorMessage)
 			return
 		}
+	}
 
-	} else if platform == enum.WA {
-		result, message, err = ih.interactionService.WhatsappSendMessagetoMeta(&msmr, &channelAccount)
-		if errors.Is(err, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT) {
-			errorMessage["errorMessage"] = enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseBadRequest(c, nil, errorMessage)
-			return
+	result, message, err = ih.interactionService.SendMessage(&msmr, &channelAccount)
+	if errors.Is(err, enum.INVALID_PLATFORM) {
+		errorMessage["errorMessage"] = enum.INVALID_PLATFORM_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
 
-		} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
-			errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
-			return
+	} else if errors.Is(err, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT) {
+		errorMessage["errorMessage"] = enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
 
-		} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
-			errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
-			return
+	} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
 
-		} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
-			errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
-			errorMessage["errorStatus"] = enum.FAILED_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta]: %+v", err))
-			response.ResponseBadRequest(c, nil, errorMessage)
-			return
+	} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
 
-		} else if err != nil {
-			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message to Meta] Internal Error: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
-			return
-		}
+	} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
+		errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
 
-	} else if platform == enum.LIVE_CHAT {
-		result, message, err = ih.interactionService.LiveChatSendMessage(&msmr)
-		if err != nil {
-			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Whatsapp Send Message to Meta] Internal Error: %+v", err))
-			response.ResponseInternalServerError(c, nil, errorMessage)
-			return
-		}
+	} else if errors.Is(err, enum.WHATSAPP_WINDOW_EXPIRED) {
+		errorMessage["errorMessage"] = enum.WHATSAPP_TEMPLATE_REQUIRED_MESSAGE
+		errorMessage["errorStatus"] = enum.WHATSAPP_TEMPLATE_REQUIRED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.ATTACHMENT_NOT_SUPPORTED) {
+		errorMessage["errorMessage"] = enum.ATTACHMENT_NOT_SUPPORTED_MESSAGE
+		errorMessage["errorStatus"] = enum.ATTACHMENT_NOT_SUPPORTED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
 
-	} else if platform == enum.EMAIL {
-		result, _, err = ih.interactionService.SendMessageToEmail(presentation.MessengerSendEmailRequest{
-			InteractionId: msmr.InteractionId,
-			Message:       msmr.Message,
-		})
+	} else if errors.Is(err, enum.ATTACHMENT_TYPE_NOT_ALLOWED) {
+		errorMessage["errorMessage"] = enum.ATTACHMENT_TYPE_NOT_ALLOWED_MESSAGE
+		errorMessage["errorStatus"] = enum.ATTACHMENT_TYPE_NOT_ALLOWED_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message]: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
 	}
 
-	if platform != enum.EMAIL {
+	capabilities, err := ih.interactionService.GetChannelCapabilities(msmr.Platform)
+	if err == nil && capabilities.Realtime && message != nil {
 		err = ih.interactionService.WebsocketSendService(*message)
 		if err != nil {
 			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
 			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-			logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Internal Error: %+v", err))
+			logger.Info(fmt.Sprintf("[FAILED][Messenger Send Message] Websocket Error: %+v", err))
 			response.ResponseInternalServerError(c, nil, errorMessage)
 			return
 		}
@@ -268,8 +414,15 @@ func (ih *InteractionHandler) LiveChatSendMessage(c *gin.Context) {
 	errorMessage := make(map[string]string)
 	var message *entity.Message
 
-	err := c.BindJSON(&msmr)
-	if err != nil {
+	err := bindSendMessageRequest(c, &msmr)
+	if errors.Is(err, enum.ATTACHMENT_TOO_LARGE) {
+		errorMessage["errorMessage"] = enum.ATTACHMENT_TOO_LARGE_MESSAGE
+		errorMessage["errorStatus"] = enum.ATTACHMENT_TOO_LARGE_STATUS
+		logger.Info("[FAILED][Live Chat Send Message] Attachment Too Large")
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
 		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
 		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
 		logger.Info(fmt.Sprintf("[FAILED][Live Chat Send Message] Bind JSON Body: %+v", err))
@@ -277,11 +430,32 @@ func (ih *InteractionHandler) LiveChatSendMessage(c *gin.Context) {
 		return
 	}
 
+	// agents come with their JWT, the reporter with its live chat session
+	if c.GetString("user_id") != "" {
+		msmr.SentBy = enum.AGENT
+	} else {
+		msmr.SentBy = enum.REPORTER
+	}
+
 	platform := msmr.Platform
 
 	if platform == enum.LIVE_CHAT {
-		result, message, err = ih.interactionService.LiveChatSendMessage(&msmr)
-		if err != nil {
+		result, message, err = ih.interactionService.LiveChatSendMessage(&msmr, c.GetString("live_chat_token"))
+		if errors.Is(err, enum.LIVE_CHAT_SESSION_INVALID) {
+			errorMessage["errorMessage"] = enum.LIVE_CHAT_SESSION_INVALID_MESSAGE
+			errorMessage["errorStatus"] = enum.LIVE_CHAT_SESSION_INVALID_STATUS
+			logger.Info("[FAILED][Live Chat Send Message] Invalid Live Chat Session")
+			response.ResponseUnauthorized(c, nil, errorMessage)
+			return
+
+		} else if errors.Is(err, enum.ATTACHMENT_TYPE_NOT_ALLOWED) {
+			errorMessage["errorMessage"] = enum.ATTACHMENT_TYPE_NOT_ALLOWED_MESSAGE
+			errorMessage["errorStatus"] = enum.ATTACHMENT_TYPE_NOT_ALLOWED_STATUS
+			logger.Info("[FAILED][Live Chat Send Message] Attachment Type Not Allowed")
+			response.ResponseBadRequest(c, nil, errorMessage)
+			return
+
+		} else if err != nil {
 			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
 			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
 			logger.Info(fmt.Sprintf("[FAILED][Live Chat Send Message] Internal Error: %+v", err))
@@ -306,6 +480,23 @@ func (ih *InteractionHandler) LiveChatSendMessage(c *gin.Context) {
 		return
 	}
 
+	if msmr.SentBy == enum.REPORTER {
+		_, err = ih.csatService.RecordCsatReply(msmr.ReporterId, msmr.Message)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Live Chat CSAT Reply]: %+v", err))
+		}
+	}
+
+	err = ih.chatbotService.HandleReporterMessage(*message)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Live Chat Chatbot]: %+v", err))
+	}
+
+	err = ih.businessHoursService.SendOutOfHoursReply(*message)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Live Chat Out of Hours Reply]: %+v", err))
+	}
+
 	response.ResponseWithData(c, result, errorMessage)
 }
 
@@ -423,6 +614,11 @@ func (ih *InteractionHandler) CloseInteractionByAgent(c *gin.Context) {
 		return
 	}
 
+	err = ih.csatService.SendCsatSurvey(interaction)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Send CSAT Survey] Interaction %d: %+v", interaction.ID, err))
+	}
+
 	result["interaction_id"] = interaction.ID
 	result["agent_id"] = interaction.AgentId
 	result["interaction_status"] = interaction.Status
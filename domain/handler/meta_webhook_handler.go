@@ -4,11 +4,6 @@ import (
 	"Omnichannel-CRM/domain/service"
 	"Omnichannel-CRM/package/enum"
 	"Omnichannel-CRM/package/logger"
-	"Omnichannel-CRM/package/presentation"
-	"Omnichannel-CRM/package/response"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 
@@ -17,12 +12,14 @@ import (
 )
 
 type MetaWebhookHandler struct {
-	metaWebhookService service.IMetaWebhookService
+	channelWebhookService service.IChannelWebhookService
+	webhookInboxService   service.IWebhookInboxService
 }
 
-func NewMetaWebhookHandler(metaWebhookService service.IMetaWebhookService) *MetaWebhookHandler {
+func NewMetaWebhookHandler(channelWebhookService service.IChannelWebhookService, webhookInboxService service.IWebhookInboxService) *MetaWebhookHandler {
 	metaWebhookHandler := MetaWebhookHandler{
-		metaWebhookService: metaWebhookService,
+		channelWebhookService: channelWebhookService,
+		webhookInboxService:   webhookInboxService,
 	}
 	return &metaWebhookHandler
 }
@@ -52,130 +49,13 @@ func (mwh *MetaWebhookHandler) ValidateVerificationRequest(c *gin.Context) {
 }
 
 func (mwh *MetaWebhookHandler) FacebookWebhookInteractionHandler(c *gin.Context) {
-	var fwr presentation.FacebookWebhookRequest
-	errorMessage := make(map[string]string)
-
-	bodyBytes, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		response.ResponseInternalServerError(c, nil, errorMessage)
-	}
-	bodyString := string(bodyBytes)
-	logger.Info(bodyString)
-
-	defer c.Request.Body.Close()
-
-	err = json.Unmarshal(bodyBytes, &fwr)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
-		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Webhook Message Interaction] Bind JSON Body: %+v", err))
-		response.ResponseBadRequest(c, nil, errorMessage)
-		return
-	}
-
-	result, resMessage, err := mwh.metaWebhookService.FacebookInteractionService(&fwr)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Webhook Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	err = mwh.metaWebhookService.WebsocketSendService(resMessage)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	response.ResponseWithData(c, result, errorMessage)
+	receiveChannelWebhook(c, mwh.channelWebhookService, mwh.webhookInboxService, enum.FACEBOOK, "", "X-Hub-Signature-256")
 }
 
 func (mwh *MetaWebhookHandler) InstagramWebhookInteractionHandler(c *gin.Context) {
-	var iwr presentation.InstagramWebhookRequest
-	errorMessage := make(map[string]string)
-
-	bodyBytes, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		response.ResponseInternalServerError(c, nil, errorMessage)
-	}
-	bodyString := string(bodyBytes)
-	logger.Info(bodyString)
-
-	defer c.Request.Body.Close()
-
-	err = json.Unmarshal(bodyBytes, &iwr)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
-		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Webhook Message Interaction] Bind JSON Body: %+v", err))
-		response.ResponseBadRequest(c, nil, errorMessage)
-		return
-	}
-
-	result, resMessage, err := mwh.metaWebhookService.InstagramInteractionService(&iwr)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Webhook Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	err = mwh.metaWebhookService.WebsocketSendService(resMessage)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	response.ResponseWithData(c, result, errorMessage)
+	receiveChannelWebhook(c, mwh.channelWebhookService, mwh.webhookInboxService, enum.IG, "", "X-Hub-Signature-256")
 }
 
 func (mwh *MetaWebhookHandler) WhatsappMessageInteractionHandler(c *gin.Context) {
-	var wir presentation.WhatsappInteractionRequest
-	errorMessage := make(map[string]string)
-
-	bodyBytes, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		response.ResponseInternalServerError(c, nil, errorMessage)
-	}
-	bodyString := string(bodyBytes)
-	logger.Info(bodyString)
-
-	defer c.Request.Body.Close()
-
-	err = json.Unmarshal(bodyBytes, &wir)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
-		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Bind JSON Body: %+v", err))
-		response.ResponseBadRequest(c, nil, errorMessage)
-		return
-	}
-
-	result, resMessage, err := mwh.metaWebhookService.WhatsappMessageInteractionService(&wir)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	err = mwh.metaWebhookService.WebsocketSendService(resMessage)
-	if err != nil {
-		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		logger.Info(fmt.Sprintf("[FAILED][Whatsapp Message Interaction] Internal Error: %+v", err))
-		response.ResponseInternalServerError(c, nil, errorMessage)
-		return
-	}
-
-	response.ResponseWithData(c, result, errorMessage)
+	receiveChannelWebhook(c, mwh.channelWebhookService, mwh.webhookInboxService, enum.WA, "", "X-Hub-Signature-256")
 }
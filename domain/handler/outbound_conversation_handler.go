@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboundConversationHandler struct {
+	outboundConversationService service.IOutboundConversationService
+	interactionService          service.IInteractionService
+}
+
+func NewOutboundConversationHandler(outboundConversationService service.IOutboundConversationService, interactionService service.IInteractionService) *OutboundConversationHandler {
+	outboundConversationHandler := OutboundConversationHandler{
+		outboundConversationService: outboundConversationService,
+		interactionService:          interactionService,
+	}
+	return &outboundConversationHandler
+}
+
+func (och *OutboundConversationHandler) StartOutboundConversation(c *gin.Context) {
+	userId := c.GetString("user_id")
+	var socr presentation.StartOutboundConversationRequest
+	errorMessage := make(map[string]string)
+
+	channelAccount, err := getTokenChannelAccount(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Invalid Channel Account Data from Token: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	err = c.BindJSON(&socr)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := socr.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Start Outbound Conversation] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, message, err := och.outboundConversationService.StartOutboundConversation(&socr, userId, channelAccount)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INVALID_PLATFORM) {
+		errorMessage["errorMessage"] = enum.INVALID_PLATFORM_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.REPORTER_NOT_REACHABLE) {
+		errorMessage["errorStatus"] = enum.REPORTER_NOT_REACHABLE_STATUS
+		errorMessage["errorMessage"] = enum.REPORTER_NOT_REACHABLE_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INTERACTION_ALREADY_ONGOING) {
+		errorMessage["errorStatus"] = enum.INTERACTION_ALREADY_ONGOING_STATUS
+		errorMessage["errorMessage"] = enum.INTERACTION_ALREADY_ONGOING_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.AGENT_CAPACITY_EXCEEDED) {
+		errorMessage["errorStatus"] = enum.AGENT_CAPACITY_EXCEEDED_STATUS
+		errorMessage["errorMessage"] = enum.AGENT_CAPACITY_EXCEEDED_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Agent %s reached capacity", userId))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.WHATSAPP_WINDOW_EXPIRED) {
+		errorMessage["errorStatus"] = enum.WHATSAPP_TEMPLATE_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.WHATSAPP_TEMPLATE_REQUIRED_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.MESSAGING_WINDOW_EXPIRED) {
+		errorMessage["errorStatus"] = enum.MESSAGING_WINDOW_EXPIRED_STATUS
+		errorMessage["errorMessage"] = enum.MESSAGING_WINDOW_EXPIRED_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.WHATSAPP_TEMPLATE_NOT_APPROVED) {
+		errorMessage["errorStatus"] = enum.WHATSAPP_TEMPLATE_NOT_APPROVED_STATUS
+		errorMessage["errorMessage"] = enum.WHATSAPP_TEMPLATE_NOT_APPROVED_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INVALID_TEMPLATE_PARAMETERS) {
+		errorMessage["errorStatus"] = enum.INVALID_TEMPLATE_PARAMETERS_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_TEMPLATE_PARAMETERS_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT) {
+		errorMessage["errorMessage"] = enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
+		errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	// the conversation is started at this point, a failed push only delays the
+	// message on the dashboard
+	if message != nil {
+		err = och.interactionService.WebsocketSendService(*message)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Websocket Error: %+v", err))
+		}
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoutingPolicyHandler struct {
+	routingService service.IRoutingService
+}
+
+func NewRoutingPolicyHandler(routingService service.IRoutingService) *RoutingPolicyHandler {
+	routingPolicyHandler := RoutingPolicyHandler{
+		routingService: routingService,
+	}
+	return &routingPolicyHandler
+}
+
+func (rph *RoutingPolicyHandler) CreateRoutingPolicy(c *gin.Context) {
+	var rpm presentation.RoutingPolicyModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&rpm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Create Routing Policy] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := rpm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Create Routing Policy] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := rph.routingService.CreateRoutingPolicy(&rpm)
+	if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Create Routing Policy] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (rph *RoutingPolicyHandler) GetRoutingPolicyList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	result, err := rph.routingService.GetRoutingPolicyList()
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (rph *RoutingPolicyHandler) GetRoutingPolicyById(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	routingPolicyIdQuery := c.Query("routing_policy_id")
+	if routingPolicyIdQuery == "" {
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	routingPolicyId, err := strconv.ParseUint(routingPolicyIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Routing Policy] Invalid Value of Query routing_policy_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := rph.routingService.GetRoutingPolicyById(uint(routingPolicyId))
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (rph *RoutingPolicyHandler) UpdateRoutingPolicy(c *gin.Context) {
+	var urpm presentation.UpdateRoutingPolicyModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&urpm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Update Routing Policy] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := urpm.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Update Routing Policy] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, err := rph.routingService.UpdateRoutingPolicy(&urpm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (rph *RoutingPolicyHandler) DeleteRoutingPolicy(c *gin.Context) {
+	var drpm presentation.DeleteRoutingPolicyModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&drpm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Delete Routing Policy] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := rph.routingService.DeleteRoutingPolicyById(&drpm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
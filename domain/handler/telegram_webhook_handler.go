@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TelegramWebhookHandler struct {
+	channelWebhookService service.IChannelWebhookService
+	webhookInboxService   service.IWebhookInboxService
+}
+
+func NewTelegramWebhookHandler(channelWebhookService service.IChannelWebhookService, webhookInboxService service.IWebhookInboxService) *TelegramWebhookHandler {
+	telegramWebhookHandler := TelegramWebhookHandler{
+		channelWebhookService: channelWebhookService,
+		webhookInboxService:   webhookInboxService,
+	}
+	return &telegramWebhookHandler
+}
+
+func (twh *TelegramWebhookHandler) TelegramMessageInteractionHandler(c *gin.Context) {
+	receiveChannelWebhook(c, twh.channelWebhookService, twh.webhookInboxService, enum.TELEGRAM, c.Param("bot_id"), "X-Telegram-Bot-Api-Secret-Token")
+}
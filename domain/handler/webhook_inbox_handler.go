@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookInboxHandler struct {
+	webhookInboxService service.IWebhookInboxService
+}
+
+func NewWebhookInboxHandler(webhookInboxService service.IWebhookInboxService) *WebhookInboxHandler {
+	webhookInboxHandler := WebhookInboxHandler{
+		webhookInboxService: webhookInboxService,
+	}
+	return &webhookInboxHandler
+}
+
+func (wih *WebhookInboxHandler) GetWebhookInboxList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	filters, err := presentation.ParseGetListWebhookInboxFilters(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Get Webhook Inbox List] Invalid Query Params: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := wih.webhookInboxService.GetWebhookInboxList(filters)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (wih *WebhookInboxHandler) GetWebhookInboxById(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	webhookInboxIdQuery := c.Query("webhook_inbox_id")
+	if webhookInboxIdQuery == "" {
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	webhookInboxId, err := strconv.ParseUint(webhookInboxIdQuery, 10, 64)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info("[FAILED][Get Webhook Inbox] Invalid Value of Query webhook_inbox_id")
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := wih.webhookInboxService.GetWebhookInboxById(uint(webhookInboxId))
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (wih *WebhookInboxHandler) ReplayWebhookInbox(c *gin.Context) {
+	var rwim presentation.ReplayWebhookInboxModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&rwim)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Replay Webhook Inbox] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := wih.webhookInboxService.ReplayWebhookInbox(&rwim)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.WEBHOOK_INBOX_PROCESSING) {
+		errorMessage["errorStatus"] = enum.WEBHOOK_INBOX_PROCESSING_STATUS
+		errorMessage["errorMessage"] = enum.WEBHOOK_INBOX_PROCESSING_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Replay Webhook Inbox] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/service"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/response"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WhatsappTemplateHandler struct {
+	whatsappTemplateService service.IWhatsappTemplateService
+	interactionService      service.IInteractionService
+}
+
+func NewWhatsappTemplateHandler(whatsappTemplateService service.IWhatsappTemplateService, interactionService service.IInteractionService) *WhatsappTemplateHandler {
+	whatsappTemplateHandler := WhatsappTemplateHandler{
+		whatsappTemplateService: whatsappTemplateService,
+		interactionService:      interactionService,
+	}
+	return &whatsappTemplateHandler
+}
+
+func (wth *WhatsappTemplateHandler) SyncWhatsappTemplates(c *gin.Context) {
+	var swtm presentation.SyncWhatsappTemplateModel
+	errorMessage := make(map[string]string)
+
+	err := c.BindJSON(&swtm)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Sync Whatsapp Template] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	result, err := wth.whatsappTemplateService.SyncWhatsappTemplates(&swtm)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		logger.Info(fmt.Sprintf("[FAILED][Sync Whatsapp Template] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+// GetWhatsappTemplateList lists the templates of the business account of the
+// agent's channel account unless whatsapp_business_id is given.
+func (wth *WhatsappTemplateHandler) GetWhatsappTemplateList(c *gin.Context) {
+	errorMessage := make(map[string]string)
+
+	filters := presentation.ParseGetListWhatsappTemplateFilters(c)
+	if filters["whatsapp_business_id"] == nil {
+		channelAccount, err := getTokenChannelAccount(c)
+		if err != nil || channelAccount.WhatsappBusinessId == "" {
+			errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+			errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+			logger.Info(fmt.Sprintf("[FAILED][Get Whatsapp Template List] Invalid Channel Account Data from Token: %+v", err))
+			response.ResponseInvalidRequest(c, nil, errorMessage)
+			return
+		}
+		filters["whatsapp_business_id"] = channelAccount.WhatsappBusinessId
+	}
+
+	result, err := wth.whatsappTemplateService.GetWhatsappTemplateList(filters)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
+
+func (wth *WhatsappTemplateHandler) SendWhatsappTemplate(c *gin.Context) {
+	var swtr presentation.SendWhatsappTemplateRequest
+	errorMessage := make(map[string]string)
+	var message *entity.Message
+
+	channelAccount, err := getTokenChannelAccount(c)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.INVALID_QUERY_MESSAGE
+		errorMessage["errorStatus"] = enum.INVALID_QUERY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Send Whatsapp Template] Invalid Channel Account Data from Token: %+v", err))
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+	}
+
+	err = c.BindJSON(&swtr)
+	if err != nil {
+		errorMessage["errorMessage"] = enum.FAILED_BIND_JSON_MESSAGE
+		errorMessage["errorStatus"] = enum.FAILED_BIND_JSON_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Send Whatsapp Template] Bind JSON Body: %+v", err))
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+	}
+
+	validation := swtr.ValidatePayload()
+	if validation["errorStatus"] != "" {
+		logger.Info("[FAILED][Send Whatsapp Template] Invalid Payload")
+		response.ResponseInvalidRequest(c, nil, validation)
+		return
+	}
+
+	result, message, err := wth.whatsappTemplateService.SendWhatsappTemplate(&swtr, channelAccount)
+	if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+		errorMessage["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		errorMessage["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		response.ResponseNotFound(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.WHATSAPP_TEMPLATE_NOT_APPROVED) {
+		errorMessage["errorStatus"] = enum.WHATSAPP_TEMPLATE_NOT_APPROVED_STATUS
+		errorMessage["errorMessage"] = enum.WHATSAPP_TEMPLATE_NOT_APPROVED_MESSAGE
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.INVALID_TEMPLATE_PARAMETERS) {
+		errorMessage["errorStatus"] = enum.INVALID_TEMPLATE_PARAMETERS_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_TEMPLATE_PARAMETERS_MESSAGE
+		response.ResponseInvalidRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT) {
+		errorMessage["errorMessage"] = enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ID_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ID_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.PLATFORM_ACCESS_TOKEN_NOT_SET) {
+		errorMessage["errorMessage"] = enum.PLATFORM_ACCESS_TOKEN_NOT_SET_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+
+	} else if errors.Is(err, enum.CHANNEL_ACCOUNT_NOT_MATCH) {
+		errorMessage["errorMessage"] = enum.CHANNEL_ACCOUNT_NOT_MATCH_MSG
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		response.ResponseBadRequest(c, nil, errorMessage)
+		return
+
+	} else if err != nil {
+		errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		logger.Info(fmt.Sprintf("[FAILED][Send Whatsapp Template] Internal Error: %+v", err))
+		response.ResponseInternalServerError(c, nil, errorMessage)
+		return
+	}
+
+	if message != nil {
+		err = wth.interactionService.WebsocketSendService(*message)
+		if err != nil {
+			errorMessage["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+			errorMessage["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+			logger.Info(fmt.Sprintf("[FAILED][Send Whatsapp Template] Websocket Error: %+v", err))
+			response.ResponseInternalServerError(c, nil, errorMessage)
+			return
+		}
+	}
+
+	response.ResponseWithData(c, result, errorMessage)
+}
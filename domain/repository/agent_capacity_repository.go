@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type AgentCapacityRepository struct {
+	db *gorm.DB
+}
+
+type IAgentCapacityRepository interface {
+	CreateAgentCapacity(*entity.AgentCapacity) (*entity.AgentCapacity, error)
+	UpdateAgentCapacity(uint, *entity.AgentCapacity) (*entity.AgentCapacity, error)
+	GetAgentCapacityList() ([]entity.AgentCapacity, error)
+	GetAgentCapacityById(uint) (*entity.AgentCapacity, error)
+	GetAgentCapacityByAgentId(string) (*entity.AgentCapacity, error)
+	GetAgentCapacityByRole(int) (*entity.AgentCapacity, error)
+	DeleteAgentCapacity(uint) error
+}
+
+func NewAgentCapacityRepository(db *gorm.DB) *AgentCapacityRepository {
+	agentCapacityRepo := AgentCapacityRepository{
+		db: db,
+	}
+
+	return &agentCapacityRepo
+}
+
+func (acr *AgentCapacityRepository) CreateAgentCapacity(agentCapacity *entity.AgentCapacity) (*entity.AgentCapacity, error) {
+	err := acr.db.Create(&agentCapacity).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return agentCapacity, nil
+}
+
+func (acr *AgentCapacityRepository) UpdateAgentCapacity(id uint, newAgentCapacity *entity.AgentCapacity) (*entity.AgentCapacity, error) {
+	var currentAgentCapacity entity.AgentCapacity
+
+	err := acr.db.Where("id = ?", id).First(&currentAgentCapacity).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// both limits are always written, 0 sets a limit back to unlimited
+	currentAgentCapacity.ChatLimit = newAgentCapacity.ChatLimit
+	currentAgentCapacity.EmailLimit = newAgentCapacity.EmailLimit
+
+	err = acr.db.Save(&currentAgentCapacity).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentAgentCapacity, nil
+}
+
+func (acr *AgentCapacityRepository) GetAgentCapacityList() ([]entity.AgentCapacity, error) {
+	var agentCapacityList []entity.AgentCapacity
+
+	result := acr.db.Order("role ASC, agent_id ASC").Find(&agentCapacityList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return agentCapacityList, nil
+}
+
+func (acr *AgentCapacityRepository) GetAgentCapacityById(id uint) (*entity.AgentCapacity, error) {
+	var agentCapacity entity.AgentCapacity
+
+	err := acr.db.Where("id = ?", id).Take(&agentCapacity).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &agentCapacity, nil
+}
+
+func (acr *AgentCapacityRepository) GetAgentCapacityByAgentId(agentId string) (*entity.AgentCapacity, error) {
+	var agentCapacity entity.AgentCapacity
+
+	err := acr.db.Where("agent_id = ?", agentId).Take(&agentCapacity).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &agentCapacity, nil
+}
+
+func (acr *AgentCapacityRepository) GetAgentCapacityByRole(role int) (*entity.AgentCapacity, error) {
+	var agentCapacity entity.AgentCapacity
+
+	err := acr.db.Where("role = ? AND agent_id = ''", role).Take(&agentCapacity).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &agentCapacity, nil
+}
+
+func (acr *AgentCapacityRepository) DeleteAgentCapacity(id uint) error {
+	var agentCapacity entity.AgentCapacity
+
+	err := acr.db.Unscoped().Where("id = ?", id).Delete(&agentCapacity).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type BusinessCalendarRepository struct {
+	db *gorm.DB
+}
+
+type IBusinessCalendarRepository interface {
+	CreateBusinessCalendar(*entity.BusinessCalendar) (*entity.BusinessCalendar, error)
+	UpdateBusinessCalendar(uint, *entity.BusinessCalendar) (*entity.BusinessCalendar, error)
+	GetBusinessCalendarList() ([]entity.BusinessCalendar, error)
+	GetBusinessCalendarById(uint) (*entity.BusinessCalendar, error)
+	GetBusinessCalendarByChannelAccountId(uint) (*entity.BusinessCalendar, error)
+	DeleteBusinessCalendar(uint) error
+}
+
+func NewBusinessCalendarRepository(db *gorm.DB) *BusinessCalendarRepository {
+	businessCalendarRepo := BusinessCalendarRepository{
+		db: db,
+	}
+
+	return &businessCalendarRepo
+}
+
+func (bcr *BusinessCalendarRepository) CreateBusinessCalendar(businessCalendar *entity.BusinessCalendar) (*entity.BusinessCalendar, error) {
+	err := bcr.db.Create(&businessCalendar).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return businessCalendar, nil
+}
+
+func (bcr *BusinessCalendarRepository) UpdateBusinessCalendar(id uint, newBusinessCalendar *entity.BusinessCalendar) (*entity.BusinessCalendar, error) {
+	var currentBusinessCalendar entity.BusinessCalendar
+
+	err := bcr.db.Where("id = ?", id).First(&currentBusinessCalendar).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if newBusinessCalendar.Timezone != "" {
+		currentBusinessCalendar.Timezone = newBusinessCalendar.Timezone
+	}
+
+	if newBusinessCalendar.WeeklySchedule != "" {
+		currentBusinessCalendar.WeeklySchedule = newBusinessCalendar.WeeklySchedule
+	}
+
+	if newBusinessCalendar.Holidays != "" {
+		currentBusinessCalendar.Holidays = newBusinessCalendar.Holidays
+	}
+
+	if newBusinessCalendar.AutoReplyMessage != "" {
+		currentBusinessCalendar.AutoReplyMessage = newBusinessCalendar.AutoReplyMessage
+	}
+
+	currentBusinessCalendar.IsActive = newBusinessCalendar.IsActive
+
+	err = bcr.db.Save(&currentBusinessCalendar).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentBusinessCalendar, nil
+}
+
+func (bcr *BusinessCalendarRepository) GetBusinessCalendarList() ([]entity.BusinessCalendar, error) {
+	var businessCalendarList []entity.BusinessCalendar
+
+	result := bcr.db.Order("channel_account_id ASC").Find(&businessCalendarList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return businessCalendarList, nil
+}
+
+func (bcr *BusinessCalendarRepository) GetBusinessCalendarById(id uint) (*entity.BusinessCalendar, error) {
+	var businessCalendar entity.BusinessCalendar
+
+	err := bcr.db.Where("id = ?", id).Take(&businessCalendar).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &businessCalendar, nil
+}
+
+func (bcr *BusinessCalendarRepository) GetBusinessCalendarByChannelAccountId(channelAccountId uint) (*entity.BusinessCalendar, error) {
+	var businessCalendar entity.BusinessCalendar
+
+	err := bcr.db.Where("channel_account_id = ?", channelAccountId).Take(&businessCalendar).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &businessCalendar, nil
+}
+
+func (bcr *BusinessCalendarRepository) DeleteBusinessCalendar(id uint) error {
+	var businessCalendar entity.BusinessCalendar
+
+	err := bcr.db.Unscoped().Where("id = ?", id).Delete(&businessCalendar).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
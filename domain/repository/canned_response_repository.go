@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/package/enum"
+
+	"gorm.io/gorm"
+)
+
+type CannedResponseRepository struct {
+	db *gorm.DB
+}
+
+type ICannedResponseRepository interface {
+	CreateCannedResponse(*entity.CannedResponse) (*entity.CannedResponse, error)
+	UpdateCannedResponse(uint, *entity.CannedResponse) (*entity.CannedResponse, error)
+	GetCannedResponseList(map[string]interface{}) ([]entity.CannedResponse, error)
+	GetAvailableCannedResponseList(string, uint) ([]entity.CannedResponse, error)
+	GetCannedResponseById(uint) (*entity.CannedResponse, error)
+	DeleteCannedResponse(uint) error
+}
+
+func NewCannedResponseRepository(db *gorm.DB) *CannedResponseRepository {
+	cannedResponseRepo := CannedResponseRepository{
+		db: db,
+	}
+
+	return &cannedResponseRepo
+}
+
+func (crr *CannedResponseRepository) CreateCannedResponse(cannedResponse *entity.CannedResponse) (*entity.CannedResponse, error) {
+	err := crr.db.Create(&cannedResponse).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cannedResponse, nil
+}
+
+func (crr *CannedResponseRepository) UpdateCannedResponse(id uint, newCannedResponse *entity.CannedResponse) (*entity.CannedResponse, error) {
+	var currentCannedResponse entity.CannedResponse
+
+	err := crr.db.Where("id = ?", id).First(&currentCannedResponse).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if newCannedResponse.Title != "" {
+		currentCannedResponse.Title = newCannedResponse.Title
+	}
+
+	if newCannedResponse.Shortcut != "" {
+		currentCannedResponse.Shortcut = newCannedResponse.Shortcut
+	}
+
+	if newCannedResponse.Content != "" {
+		currentCannedResponse.Content = newCannedResponse.Content
+	}
+
+	err = crr.db.Save(&currentCannedResponse).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentCannedResponse, nil
+}
+
+func (crr *CannedResponseRepository) GetCannedResponseList(filters map[string]interface{}) ([]entity.CannedResponse, error) {
+	var cannedResponseList []entity.CannedResponse
+
+	query := crr.db.Order("scope ASC, title ASC")
+
+	if filters["scope"] != nil {
+		query = query.Where("scope = ?", filters["scope"])
+	}
+
+	if filters["channel_account_id"] != nil {
+		query = query.Where("channel_account_id = ?", filters["channel_account_id"])
+	}
+
+	if filters["agent_id"] != nil {
+		query = query.Where("agent_id = ?", filters["agent_id"])
+	}
+
+	result := query.Find(&cannedResponseList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return cannedResponseList, nil
+}
+
+// GetAvailableCannedResponseList lists the global responses together with the
+// ones of the agent's channel account and the agent's own.
+func (crr *CannedResponseRepository) GetAvailableCannedResponseList(agentId string, channelAccountId uint) ([]entity.CannedResponse, error) {
+	var cannedResponseList []entity.CannedResponse
+
+	result := crr.db.
+		Where("scope = ?", enum.GLOBAL).
+		Or("scope = ? AND channel_account_id = ?", enum.CHANNEL_ACCOUNT, channelAccountId).
+		Or("scope = ? AND agent_id = ?", enum.AGENT, agentId).
+		Order("scope ASC, title ASC").
+		Find(&cannedResponseList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return cannedResponseList, nil
+}
+
+func (crr *CannedResponseRepository) GetCannedResponseById(id uint) (*entity.CannedResponse, error) {
+	var cannedResponse entity.CannedResponse
+
+	err := crr.db.Where("id = ?", id).Take(&cannedResponse).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &cannedResponse, nil
+}
+
+func (crr *CannedResponseRepository) DeleteCannedResponse(id uint) error {
+	var cannedResponse entity.CannedResponse
+
+	err := crr.db.Unscoped().Where("id = ?", id).Delete(&cannedResponse).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -15,6 +15,10 @@ type IChannelAccountRepository interface {
 	UpdateChannelAccount(uint, *entity.ChannelAccount) (*entity.ChannelAccount, error)
 	GetChannelAccountList() ([]entity.ChannelAccount, error)
 	GetChannelAccountById(uint) (*entity.ChannelAccount, error)
+	GetChannelAccountByPlatformId(string) (*entity.ChannelAccount, error)
+	GetLiveChatChannelAccount() (*entity.ChannelAccount, error)
+	GetGmailChannelAccountList() ([]entity.ChannelAccount, error)
+	GetImapChannelAccountList() ([]entity.ChannelAccount, error)
 	DeleteChannelAccount(uint) error
 }
 
@@ -76,6 +80,54 @@ func (car *ChannelAccountRepository) UpdateChannelAccount(id uint, newChannelAcc
 		currentChannelAccount.WhatsappAccessToken = newChannelAccount.WhatsappAccessToken
 	}
 
+	if newChannelAccount.MetaAppSecret != "" {
+		currentChannelAccount.MetaAppSecret = newChannelAccount.MetaAppSecret
+	}
+
+	if newChannelAccount.TelegramBotId != "" {
+		currentChannelAccount.TelegramBotId = newChannelAccount.TelegramBotId
+	}
+
+	if newChannelAccount.TelegramBotToken != "" {
+		currentChannelAccount.TelegramBotToken = newChannelAccount.TelegramBotToken
+	}
+
+	if newChannelAccount.TelegramSecretToken != "" {
+		currentChannelAccount.TelegramSecretToken = newChannelAccount.TelegramSecretToken
+	}
+
+	if newChannelAccount.EmailAddress != "" {
+		currentChannelAccount.EmailAddress = newChannelAccount.EmailAddress
+	}
+
+	if newChannelAccount.GmailRefreshToken != "" {
+		currentChannelAccount.GmailRefreshToken = newChannelAccount.GmailRefreshToken
+	}
+
+	if newChannelAccount.ImapAddress != "" {
+		currentChannelAccount.ImapAddress = newChannelAccount.ImapAddress
+	}
+
+	if newChannelAccount.ImapUsername != "" {
+		currentChannelAccount.ImapUsername = newChannelAccount.ImapUsername
+	}
+
+	if newChannelAccount.ImapPassword != "" {
+		currentChannelAccount.ImapPassword = newChannelAccount.ImapPassword
+	}
+
+	if newChannelAccount.SmtpAddress != "" {
+		currentChannelAccount.SmtpAddress = newChannelAccount.SmtpAddress
+	}
+
+	if newChannelAccount.SmtpUsername != "" {
+		currentChannelAccount.SmtpUsername = newChannelAccount.SmtpUsername
+	}
+
+	if newChannelAccount.SmtpPassword != "" {
+		currentChannelAccount.SmtpPassword = newChannelAccount.SmtpPassword
+	}
+
 	err = car.db.Save(&currentChannelAccount).Error
 	if err != nil {
 		return nil, err
@@ -109,6 +161,60 @@ func (car *ChannelAccountRepository) GetChannelAccountById(channelAccountId uint
 	return &channelAccount, nil
 }
 
+func (car *ChannelAccountRepository) GetChannelAccountByPlatformId(platformId string) (*entity.ChannelAccount, error) {
+	var channelAccount entity.ChannelAccount
+
+	err := car.db.Where("faceboook_page_id = ? OR instagram_id = ? OR whatsapp_business_id = ? OR telegram_bot_id = ? OR email_address = ?", platformId, platformId, platformId, platformId, platformId).Take(&channelAccount).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &channelAccount, nil
+}
+
+func (car *ChannelAccountRepository) GetLiveChatChannelAccount() (*entity.ChannelAccount, error) {
+	var channelAccount entity.ChannelAccount
+
+	err := car.db.Where("is_live_chat_active = ?", true).Order("id ASC").Take(&channelAccount).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &channelAccount, nil
+}
+
+// GetGmailChannelAccountList returns the channel accounts that own a Gmail
+// mailbox.
+func (car *ChannelAccountRepository) GetGmailChannelAccountList() ([]entity.ChannelAccount, error) {
+	var channelAccountList []entity.ChannelAccount
+
+	result := car.db.Where("email_address <> '' AND gmail_refresh_token <> ''").Find(&channelAccountList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return channelAccountList, nil
+}
+
+// GetImapChannelAccountList returns the channel accounts that own a mailbox
+// read over IMAP.
+func (car *ChannelAccountRepository) GetImapChannelAccountList() ([]entity.ChannelAccount, error) {
+	var channelAccountList []entity.ChannelAccount
+
+	result := car.db.Where("email_address <> '' AND imap_address <> ''").Find(&channelAccountList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return channelAccountList, nil
+}
+
 func (car *ChannelAccountRepository) DeleteChannelAccount(channelAccountId uint) error {
 	var channelAccount entity.ChannelAccount
 
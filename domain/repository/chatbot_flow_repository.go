@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ChatbotFlowRepository struct {
+	db *gorm.DB
+}
+
+type IChatbotFlowRepository interface {
+	CreateChatbotFlow(*entity.ChatbotFlow) (*entity.ChatbotFlow, error)
+	UpdateChatbotFlow(uint, *entity.ChatbotFlow) (*entity.ChatbotFlow, error)
+	GetChatbotFlowList() ([]entity.ChatbotFlow, error)
+	GetChatbotFlowById(uint) (*entity.ChatbotFlow, error)
+	GetActiveChatbotFlow(uint, string) (*entity.ChatbotFlow, error)
+	DeleteChatbotFlow(uint) error
+
+	CreateChatbotSession(*entity.ChatbotSession) (*entity.ChatbotSession, error)
+	GetChatbotSessionByInteractionId(uint) (*entity.ChatbotSession, error)
+	UpdateChatbotSession(*entity.ChatbotSession) error
+}
+
+func NewChatbotFlowRepository(db *gorm.DB) *ChatbotFlowRepository {
+	chatbotFlowRepo := ChatbotFlowRepository{
+		db: db,
+	}
+
+	return &chatbotFlowRepo
+}
+
+func (cfr *ChatbotFlowRepository) CreateChatbotFlow(chatbotFlow *entity.ChatbotFlow) (*entity.ChatbotFlow, error) {
+	err := cfr.db.Create(&chatbotFlow).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return chatbotFlow, nil
+}
+
+func (cfr *ChatbotFlowRepository) UpdateChatbotFlow(id uint, newChatbotFlow *entity.ChatbotFlow) (*entity.ChatbotFlow, error) {
+	var currentChatbotFlow entity.ChatbotFlow
+
+	err := cfr.db.Where("id = ?", id).First(&currentChatbotFlow).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if newChatbotFlow.Name != "" {
+		currentChatbotFlow.Name = newChatbotFlow.Name
+	}
+
+	if newChatbotFlow.Platform != "" {
+		currentChatbotFlow.Platform = newChatbotFlow.Platform
+	}
+
+	if newChatbotFlow.Steps != "" {
+		currentChatbotFlow.Steps = newChatbotFlow.Steps
+	}
+
+	if newChatbotFlow.HandoverKeywords != "" {
+		currentChatbotFlow.HandoverKeywords = newChatbotFlow.HandoverKeywords
+	}
+
+	if newChatbotFlow.HandoverMessage != "" {
+		currentChatbotFlow.HandoverMessage = newChatbotFlow.HandoverMessage
+	}
+
+	currentChatbotFlow.IsActive = newChatbotFlow.IsActive
+
+	err = cfr.db.Save(&currentChatbotFlow).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentChatbotFlow, nil
+}
+
+func (cfr *ChatbotFlowRepository) GetChatbotFlowList() ([]entity.ChatbotFlow, error) {
+	var chatbotFlowList []entity.ChatbotFlow
+
+	result := cfr.db.Order("channel_account_id ASC").Find(&chatbotFlowList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return chatbotFlowList, nil
+}
+
+func (cfr *ChatbotFlowRepository) GetChatbotFlowById(id uint) (*entity.ChatbotFlow, error) {
+	var chatbotFlow entity.ChatbotFlow
+
+	err := cfr.db.Where("id = ?", id).Take(&chatbotFlow).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &chatbotFlow, nil
+}
+
+// GetActiveChatbotFlow returns the active flow of the channel account for the
+// platform, preferring a platform specific flow over one for every platform.
+func (cfr *ChatbotFlowRepository) GetActiveChatbotFlow(channelAccountId uint, platform string) (*entity.ChatbotFlow, error) {
+	var chatbotFlow entity.ChatbotFlow
+
+	err := cfr.db.
+		Where("channel_account_id = ? AND is_active = ? AND platform IN ?", channelAccountId, true, []string{platform, ""}).
+		Order("platform DESC").
+		Take(&chatbotFlow).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &chatbotFlow, nil
+}
+
+func (cfr *ChatbotFlowRepository) DeleteChatbotFlow(id uint) error {
+	var chatbotFlow entity.ChatbotFlow
+
+	err := cfr.db.Unscoped().Where("id = ?", id).Delete(&chatbotFlow).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cfr *ChatbotFlowRepository) CreateChatbotSession(chatbotSession *entity.ChatbotSession) (*entity.ChatbotSession, error) {
+	err := cfr.db.Create(&chatbotSession).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return chatbotSession, nil
+}
+
+func (cfr *ChatbotFlowRepository) GetChatbotSessionByInteractionId(interactionId uint) (*entity.ChatbotSession, error) {
+	var chatbotSession entity.ChatbotSession
+
+	err := cfr.db.Where("interaction_id = ?", interactionId).Take(&chatbotSession).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &chatbotSession, nil
+}
+
+func (cfr *ChatbotFlowRepository) UpdateChatbotSession(chatbotSession *entity.ChatbotSession) error {
+	err := cfr.db.Save(chatbotSession).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CsatSurveyRepository struct {
+	db *gorm.DB
+}
+
+type ICsatSurveyRepository interface {
+	CreateCsatSurvey(*entity.CsatSurvey) (*entity.CsatSurvey, error)
+	GetPendingCsatSurveyByReporterId(uint, time.Time) (*entity.CsatSurvey, error)
+	AnswerCsatSurvey(*entity.CsatSurvey) error
+	GetCsatSurveyList(map[string]interface{}) ([]entity.CsatSurvey, error)
+	GetCsatSummaryByAgentIds([]string) ([]presentation.AgentCsatSummary, error)
+}
+
+func NewCsatSurveyRepository(db *gorm.DB) *CsatSurveyRepository {
+	csatSurveyRepo := CsatSurveyRepository{
+		db: db,
+	}
+
+	return &csatSurveyRepo
+}
+
+func (csr *CsatSurveyRepository) CreateCsatSurvey(csatSurvey *entity.CsatSurvey) (*entity.CsatSurvey, error) {
+	err := csr.db.Create(&csatSurvey).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return csatSurvey, nil
+}
+
+// GetPendingCsatSurveyByReporterId returns the latest unanswered survey of the
+// reporter sent after since.
+func (csr *CsatSurveyRepository) GetPendingCsatSurveyByReporterId(reporterId uint, since time.Time) (*entity.CsatSurvey, error) {
+	var csatSurvey entity.CsatSurvey
+
+	err := csr.db.
+		Where("reporter_id = ? AND status = ? AND created_at >= ?", reporterId, enum.SENT, since).
+		Order("created_at DESC").
+		Take(&csatSurvey).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &csatSurvey, nil
+}
+
+// AnswerCsatSurvey saves the answered survey and copies its score to the
+// interaction.
+func (csr *CsatSurveyRepository) AnswerCsatSurvey(csatSurvey *entity.CsatSurvey) error {
+	return csr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(csatSurvey).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Interaction{}).Where("id = ?", csatSurvey.InteractionId).UpdateColumn("csat_score", csatSurvey.Score).Error
+	})
+}
+
+func (csr *CsatSurveyRepository) GetCsatSurveyList(filters map[string]interface{}) ([]entity.CsatSurvey, error) {
+	var csatSurveyList []entity.CsatSurvey
+
+	query := csr.db.Order("created_at DESC")
+
+	if filters["interaction_id"] != nil {
+		query = query.Where("interaction_id = ?", filters["interaction_id"])
+	}
+
+	if filters["agent_id"] != nil {
+		query = query.Where("agent_id = ?", filters["agent_id"])
+	}
+
+	if filters["status"] != nil {
+		query = query.Where("status = ?", filters["status"])
+	}
+
+	result := query.Find(&csatSurveyList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return csatSurveyList, nil
+}
+
+// GetCsatSummaryByAgentIds returns, per agent, the number of answered surveys
+// and their average score.
+func (csr *CsatSurveyRepository) GetCsatSummaryByAgentIds(agentIds []string) ([]presentation.AgentCsatSummary, error) {
+	var csatSummaryList []presentation.AgentCsatSummary
+
+	err := csr.db.Model(&entity.CsatSurvey{}).
+		Select("agent_id, COUNT(*) AS csat_count, AVG(score) AS csat_average").
+		Where("agent_id IN ? AND status = ?", agentIds, enum.ANSWERED).
+		Group("agent_id").
+		Scan(&csatSummaryList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return csatSummaryList, nil
+}
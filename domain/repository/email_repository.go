@@ -1,22 +1,36 @@
 package repository
 
 import (
+	"Omnichannel-CRM/package/enum"
 	"Omnichannel-CRM/package/utils"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
 
 	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
 	"gorm.io/gorm"
 )
 
+// EmailRepository reads and sends the emails of a Gmail mailbox. The mailbox
+// of the OAuth config is used unless a refresh token is set with ForMailbox.
 type EmailRepository struct {
 	db           *gorm.DB
 	gmailService *gmail.Service
+	refreshToken string
 }
 
 type IEmailRepository interface {
 	GetHistoryList(historyId uint64) (res *gmail.ListHistoryResponse, err error)
+	ListMessageIds(query string) (messageIds []string, err error)
 	GetMessageById(messageId string) (res *gmail.Message, err error)
+	GetAttachment(messageId string, attachmentId string) (data []byte, err error)
 	SendEmail(message *gmail.Message) (res *gmail.Message, err error)
 	GetProfile() (res *gmail.Profile, err error)
+	Watch(topicName string) (res *gmail.WatchResponse, err error)
+	ForMailbox(refreshToken string) IEmailRepository
 }
 
 func NewEmailRepository(db *gorm.DB, gmailService *gmail.Service) *EmailRepository {
@@ -28,25 +42,92 @@ func NewEmailRepository(db *gorm.DB, gmailService *gmail.Service) *EmailReposito
 	return &emailRepo
 }
 
-func (repo *EmailRepository) RefreshGmailService() {
-	gmailService := utils.NewGmailService()
+// ForMailbox returns the repository of the mailbox that granted the refresh
+// token.
+func (repo *EmailRepository) ForMailbox(refreshToken string) IEmailRepository {
+	mailboxRepo := EmailRepository{
+		db:           repo.db,
+		refreshToken: refreshToken,
+	}
+
+	return &mailboxRepo
+}
+
+func (repo *EmailRepository) RefreshGmailService() error {
+	if repo.refreshToken == "" {
+		repo.gmailService = utils.NewGmailService()
+		return nil
+	}
+
+	gmailService, err := utils.NewGmailServiceByRefreshToken(repo.refreshToken)
+	if err != nil {
+		return err
+	}
 	repo.gmailService = gmailService
+
+	return nil
 }
 
+// GetHistoryList returns every page of the emails added to the inbox since the
+// history id. A history id Gmail no longer keeps returns GMAIL_HISTORY_EXPIRED.
 func (repo *EmailRepository) GetHistoryList(historyId uint64) (res *gmail.ListHistoryResponse, err error) {
-	repo.RefreshGmailService()
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
 	req := repo.gmailService.Users.History.List("me")
 	req.StartHistoryId(historyId)
-	res, err = req.Do()
-	if err != nil {
+	req.HistoryTypes("messageAdded")
+	req.LabelId("INBOX")
+
+	res = &gmail.ListHistoryResponse{}
+	err = req.Pages(context.Background(), func(page *gmail.ListHistoryResponse) error {
+		res.History = append(res.History, page.History...)
+		res.HistoryId = page.HistoryId
+		return nil
+	})
+	if isGmailNotFound(err) {
+		return nil, enum.GMAIL_HISTORY_EXPIRED
+
+	} else if err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
+// ListMessageIds returns the ids of every email matching the Gmail search query.
+func (repo *EmailRepository) ListMessageIds(query string) (messageIds []string, err error) {
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
+	req := repo.gmailService.Users.Messages.List("me")
+	req.Q(query)
+
+	err = req.Pages(context.Background(), func(page *gmail.ListMessagesResponse) error {
+		for _, message := range page.Messages {
+			messageIds = append(messageIds, message.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messageIds, nil
+}
+
+func isGmailNotFound(err error) bool {
+	var gmailErr *googleapi.Error
+	return errors.As(err, &gmailErr) && gmailErr.Code == http.StatusNotFound
+}
+
 func (repo *EmailRepository) GetMessageById(messageId string) (res *gmail.Message, err error) {
-	repo.RefreshGmailService()
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
 	req := repo.gmailService.Users.Messages.Get("me", messageId)
 	res, err = req.Do()
 	if err != nil {
@@ -56,8 +137,27 @@ func (repo *EmailRepository) GetMessageById(messageId string) (res *gmail.Messag
 	return res, nil
 }
 
+// GetAttachment downloads the body of a message part that Gmail keeps apart
+// from the message, which it does for attachments and large parts.
+func (repo *EmailRepository) GetAttachment(messageId string, attachmentId string) (data []byte, err error) {
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
+	req := repo.gmailService.Users.Messages.Attachments.Get("me", messageId, attachmentId)
+	res, err := req.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(res.Data, "="))
+}
+
 func (repo *EmailRepository) SendEmail(message *gmail.Message) (res *gmail.Message, err error) {
-	repo.RefreshGmailService()
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
 	req := repo.gmailService.Users.Messages.Send("me", message)
 	res, err = req.Do()
 	if err != nil {
@@ -68,7 +168,10 @@ func (repo *EmailRepository) SendEmail(message *gmail.Message) (res *gmail.Messa
 }
 
 func (repo *EmailRepository) GetProfile() (res *gmail.Profile, err error) {
-	repo.RefreshGmailService()
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
 	req := repo.gmailService.Users.GetProfile("me")
 	res, err = req.Do()
 	if err != nil {
@@ -77,3 +180,20 @@ func (repo *EmailRepository) GetProfile() (res *gmail.Profile, err error) {
 
 	return res, nil
 }
+
+func (repo *EmailRepository) Watch(topicName string) (res *gmail.WatchResponse, err error) {
+	err = repo.RefreshGmailService()
+	if err != nil {
+		return nil, err
+	}
+	req := repo.gmailService.Users.Watch("me", &gmail.WatchRequest{
+		LabelIds:  []string{"INBOX", "UNREAD"},
+		TopicName: topicName,
+	})
+	res, err = req.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
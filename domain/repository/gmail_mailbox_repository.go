@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GmailMailboxRepository struct {
+	db *gorm.DB
+}
+
+type IGmailMailboxRepository interface {
+	GetGmailMailboxByEmailAddress(string) (*entity.GmailMailbox, error)
+	GetGmailMailboxList() ([]entity.GmailMailbox, error)
+	SaveGmailMailboxHistoryId(string, uint64) (*entity.GmailMailbox, error)
+	SaveGmailMailboxWatch(string, uint64, time.Time) (*entity.GmailMailbox, error)
+	SaveGmailMailboxWatchError(string, string) (*entity.GmailMailbox, error)
+}
+
+func NewGmailMailboxRepository(db *gorm.DB) *GmailMailboxRepository {
+	gmailMailboxRepo := GmailMailboxRepository{
+		db: db,
+	}
+
+	return &gmailMailboxRepo
+}
+
+func (gmr *GmailMailboxRepository) GetGmailMailboxByEmailAddress(emailAddress string) (*entity.GmailMailbox, error) {
+	var gmailMailbox entity.GmailMailbox
+
+	err := gmr.db.Where("email_address = ?", emailAddress).Take(&gmailMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &gmailMailbox, nil
+}
+
+func (gmr *GmailMailboxRepository) GetGmailMailboxList() ([]entity.GmailMailbox, error) {
+	var gmailMailboxList []entity.GmailMailbox
+
+	result := gmr.db.Order("email_address ASC").Find(&gmailMailboxList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return gmailMailboxList, nil
+}
+
+// takeOrInitGmailMailbox returns the saved mailbox, or a new one to be created
+// by Save.
+func (gmr *GmailMailboxRepository) takeOrInitGmailMailbox(emailAddress string) (*entity.GmailMailbox, error) {
+	var gmailMailbox entity.GmailMailbox
+
+	err := gmr.db.Where("email_address = ?", emailAddress).Take(&gmailMailbox).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entity.GmailMailbox{EmailAddress: emailAddress}, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &gmailMailbox, nil
+}
+
+// SaveGmailMailboxHistoryId moves the cursor of the mailbox forward, a history
+// id older than the saved one only updates LastSyncedAt.
+func (gmr *GmailMailboxRepository) SaveGmailMailboxHistoryId(emailAddress string, historyId uint64) (*entity.GmailMailbox, error) {
+	now := time.Now()
+
+	gmailMailbox, err := gmr.takeOrInitGmailMailbox(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if historyId > gmailMailbox.HistoryId {
+		gmailMailbox.HistoryId = historyId
+	}
+	gmailMailbox.LastSyncedAt = &now
+
+	err = gmr.db.Save(gmailMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return gmailMailbox, nil
+}
+
+func (gmr *GmailMailboxRepository) SaveGmailMailboxWatch(emailAddress string, watchHistoryId uint64, watchExpiration time.Time) (*entity.GmailMailbox, error) {
+	now := time.Now()
+
+	gmailMailbox, err := gmr.takeOrInitGmailMailbox(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	gmailMailbox.WatchHistoryId = watchHistoryId
+	gmailMailbox.WatchExpiration = &watchExpiration
+	gmailMailbox.WatchRenewedAt = &now
+	gmailMailbox.WatchError = ""
+
+	err = gmr.db.Save(gmailMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return gmailMailbox, nil
+}
+
+// SaveGmailMailboxWatchError records a failed renewal, the last watch is kept
+// since it still runs until its expiration.
+func (gmr *GmailMailboxRepository) SaveGmailMailboxWatchError(emailAddress string, watchError string) (*entity.GmailMailbox, error) {
+	gmailMailbox, err := gmr.takeOrInitGmailMailbox(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	gmailMailbox.WatchError = watchError
+
+	err = gmr.db.Save(gmailMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return gmailMailbox, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ImapMailboxRepository struct {
+	db *gorm.DB
+}
+
+type IImapMailboxRepository interface {
+	GetImapMailboxByEmailAddress(string) (*entity.ImapMailbox, error)
+	SaveImapMailboxCursor(string, uint32, uint32) (*entity.ImapMailbox, error)
+}
+
+func NewImapMailboxRepository(db *gorm.DB) *ImapMailboxRepository {
+	imapMailboxRepo := ImapMailboxRepository{
+		db: db,
+	}
+
+	return &imapMailboxRepo
+}
+
+func (imr *ImapMailboxRepository) GetImapMailboxByEmailAddress(emailAddress string) (*entity.ImapMailbox, error) {
+	var imapMailbox entity.ImapMailbox
+
+	err := imr.db.Where("email_address = ?", emailAddress).Take(&imapMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &imapMailbox, nil
+}
+
+// SaveImapMailboxCursor saves the UIDVALIDITY of the mailbox and the UID of
+// the last email read, the mailbox is created on its first sync.
+func (imr *ImapMailboxRepository) SaveImapMailboxCursor(emailAddress string, uidValidity uint32, lastUid uint32) (*entity.ImapMailbox, error) {
+	now := time.Now()
+
+	imapMailbox, err := imr.GetImapMailboxByEmailAddress(emailAddress)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		imapMailbox = &entity.ImapMailbox{EmailAddress: emailAddress}
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	imapMailbox.UidValidity = uidValidity
+	imapMailbox.LastUid = lastUid
+	imapMailbox.LastSyncedAt = &now
+
+	err = imr.db.Save(imapMailbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return imapMailbox, nil
+}
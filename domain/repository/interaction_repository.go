@@ -25,8 +25,14 @@ type IinteractionRepository interface {
 	GetInteractionById(uint) (*entity.Interaction, error)
 	GetInteractionsByAgentId(string, map[string]interface{}) ([]presentation.InteractionWithLatestMessage, error)
 	GetActiveInteractionCount(string) (int64, error)
+	GetActiveInteractionCountByPlatform(string) (map[string]int64, error)
 	GetInteractionHandledTodayCount(string) (int64, error)
 	GetInteractionByConversationId(conversationid string) (*entity.Interaction, error)
+	GetLatestAssignedInteractionByReporterId(uint) (*entity.Interaction, error)
+	GetLatestInteractionByReporterIdAndPlatform(uint, string) (*entity.Interaction, error)
+	ReleaseInteraction(uint) (*entity.Interaction, error)
+	GetInteractionListByStatus([]string) ([]entity.Interaction, error)
+	DeleteInteraction(uint) error
 }
 
 func NewInteractionRepository(db *gorm.DB) *InteractionRepository {
@@ -58,6 +64,7 @@ func (ir *InteractionRepository) GetOngoingInteractionByReporterId(reporterId ui
 		enum.MISSED,
 		enum.UNPROCESSED,
 		enum.PROCESSED,
+		enum.BOT,
 	}
 
 	result := ir.db.Where("reporter_id = ? AND status IN ?", reporterId, ongoingInteractionStatus).Take(&interaction)
@@ -132,9 +139,22 @@ func (ir *InteractionRepository) UpdateInteraction(interactionId uint, newIntera
 	}
 
 	if newInteraction.Status != "" {
+		setInteractionTimestamps(&currentInteraction, newInteraction.Status, time.Now())
 		currentInteraction.Status = newInteraction.Status
 	}
 
+	if newInteraction.ConversationId != "" {
+		currentInteraction.ConversationId = newInteraction.ConversationId
+	}
+
+	if newInteraction.PlatformId != "" {
+		currentInteraction.PlatformId = newInteraction.PlatformId
+	}
+
+	if newInteraction.RoutingStrategy != "" {
+		currentInteraction.RoutingStrategy = newInteraction.RoutingStrategy
+	}
+
 	if newInteraction.Latitude != "" {
 		currentInteraction.Latitude = newInteraction.Latitude
 	}
@@ -151,28 +171,64 @@ func (ir *InteractionRepository) UpdateInteraction(interactionId uint, newIntera
 	return &currentInteraction, nil
 }
 
+// setInteractionTimestamps records the claim and close moments of a status
+// change and stores the durations once the interaction is closed. Reopening a
+// closed interaction clears its close data again.
+func setInteractionTimestamps(interaction *entity.Interaction, status string, now time.Time) {
+	if status == enum.IN_PROGRESS && interaction.ClaimedAt == nil {
+		interaction.ClaimedAt = &now
+	}
+
+	if status == enum.CLOSED && interaction.Status != enum.CLOSED {
+		interaction.ClosedAt = &now
+
+		durations := presentation.GetInteractionDurations(*interaction, now)
+		interaction.HandleTimeSeconds = durations.HandleTimeSeconds
+		interaction.WaitTimeSeconds = durations.WaitTimeSeconds
+		interaction.TotalOpenTimeSeconds = durations.TotalOpenTimeSeconds
+
+	} else if status != enum.CLOSED && interaction.Status == enum.CLOSED {
+		interaction.ClosedAt = nil
+		interaction.HandleTimeSeconds = 0
+		interaction.WaitTimeSeconds = 0
+		interaction.TotalOpenTimeSeconds = 0
+	}
+}
+
 func (ir *InteractionRepository) GetInteractionList(filters map[string]interface{}, channelAccount *entity.ChannelAccount) ([]entity.Interaction, int64, error) {
 	var interactionList []entity.Interaction
 	var count int64
 	queryDB := ir.db
-	initialCondition := "platform = 'EMAIL'"
 
-	if channelAccount.ID != 0 {
-		if channelAccount.FaceboookPageId != "" {
-			initialCondition = initialCondition + fmt.Sprintf(" OR platform_id = '%s'", channelAccount.FaceboookPageId)
-		}
-		if channelAccount.InstagramId != "" {
-			initialCondition = initialCondition + fmt.Sprintf(" OR platform_id = '%s'", channelAccount.InstagramId)
+	// the interactions of a channel account are the ones of its pages, numbers,
+	// bot, mailbox and live chat, a nil channel account is not scoped
+	if channelAccount != nil {
+		var conditions []string
+		var args []interface{}
+
+		if channelAccount.ID != 0 {
+			for _, platformId := range []string{channelAccount.FaceboookPageId, channelAccount.InstagramId, channelAccount.WhatsappBusinessId, channelAccount.TelegramBotId} {
+				if platformId != "" {
+					conditions = append(conditions, "platform_id = ?")
+					args = append(args, platformId)
+				}
+			}
+			if channelAccount.EmailAddress != "" {
+				conditions = append(conditions, "(platform = ? AND platform_id = ?)")
+				args = append(args, enum.EMAIL, channelAccount.EmailAddress)
+			}
+			if channelAccount.IsLiveChatActive == true {
+				conditions = append(conditions, "platform = ?")
+				args = append(args, enum.LIVE_CHAT)
+			}
 		}
-		if channelAccount.WhatsappBusinessId != "" {
-			initialCondition = initialCondition + fmt.Sprintf(" OR platform_id = '%s'", channelAccount.WhatsappBusinessId)
-		}
-		if channelAccount.IsLiveChatActive == true {
-			initialCondition = initialCondition + " OR platform = 'LIVE_CHAT'"
+
+		if len(conditions) == 0 {
+			return nil, 0, nil
 		}
-	}
 
-	queryDB = queryDB.Or(initialCondition)
+		queryDB = queryDB.Where(strings.Join(conditions, " OR "), args...)
+	}
 
 	if filters["interaction_ids"] != nil {
 		queryDB = queryDB.Where("id IN ?", filters["interaction_ids"])
@@ -236,6 +292,8 @@ func (ir *InteractionRepository) GetInteractionsByAgentId(agentId string, filter
 	statement := fmt.Sprintf(`SELECT
 		interactions.id AS interaction_id, 
 		interactions.created_at AS interaction_created_at,
+		interactions.updated_at AS interaction_updated_at,
+		interactions.closed_at AS interaction_closed_at,
 		interactions.platform_id,
 		interactions.reporter_id,
 		interactions.conversation_id,
@@ -312,6 +370,26 @@ func (ir *InteractionRepository) GetActiveInteractionCount(agentId string) (int6
 	return count, nil
 }
 
+func (ir *InteractionRepository) GetActiveInteractionCountByPlatform(agentId string) (map[string]int64, error) {
+	var platformCounts []struct {
+		Platform string
+		Count    int64
+	}
+	counts := make(map[string]int64)
+	status := []string{enum.IN_PROGRESS, enum.ACTIVE}
+
+	err := ir.db.Model(&entity.Interaction{}).Select("platform, COUNT(*) AS count").Where("agent_id = ? AND status IN ? ", agentId, status).Group("platform").Scan(&platformCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range platformCounts {
+		counts[v.Platform] = v.Count
+	}
+
+	return counts, nil
+}
+
 func (ir *InteractionRepository) GetInteractionHandledTodayCount(agentId string) (int64, error) {
 	var count int64
 	currentDateTime := time.Now()
@@ -340,3 +418,81 @@ func (ir *InteractionRepository) GetInteractionByConversationId(conversationid s
 
 	return &interaction, nil
 }
+
+func (ir *InteractionRepository) GetLatestAssignedInteractionByReporterId(reporterId uint) (*entity.Interaction, error) {
+	var interaction entity.Interaction
+
+	result := ir.db.Where("reporter_id = ? AND agent_id <> ''", reporterId).Order("created_at DESC, id DESC").Take(&interaction)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &interaction, nil
+}
+
+// GetLatestInteractionByReporterIdAndPlatform returns the last interaction of
+// the reporter on the platform, whatever its status.
+func (ir *InteractionRepository) GetLatestInteractionByReporterIdAndPlatform(reporterId uint, platform string) (*entity.Interaction, error) {
+	var interaction entity.Interaction
+
+	result := ir.db.Where("reporter_id = ? AND platform = ?", reporterId, platform).Order("created_at DESC, id DESC").Take(&interaction)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &interaction, nil
+}
+
+// ReleaseInteraction puts the interaction back to the unclaimed queue. The
+// agent and routing strategy are cleared explicitly because UpdateInteraction
+// skips empty fields.
+func (ir *InteractionRepository) ReleaseInteraction(interactionId uint) (*entity.Interaction, error) {
+	var currentInteraction entity.Interaction
+
+	err := ir.db.Where("id = ?", interactionId).First(&currentInteraction).Error
+	if err != nil {
+		return nil, err
+	}
+
+	currentInteraction.AgentId = ""
+	currentInteraction.Status = enum.UNCLAIMED
+	currentInteraction.RoutingStrategy = ""
+
+	err = ir.db.Save(&currentInteraction).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentInteraction, nil
+}
+
+func (ir *InteractionRepository) GetInteractionListByStatus(status []string) ([]entity.Interaction, error) {
+	var interactionList []entity.Interaction
+
+	result := ir.db.Where("status IN ?", status).Order("created_at ASC").Find(&interactionList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return interactionList, nil
+}
+
+func (ir *InteractionRepository) DeleteInteraction(interactionId uint) error {
+	var interaction entity.Interaction
+
+	err := ir.db.Unscoped().Where("id = ?", interactionId).Delete(&interaction).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
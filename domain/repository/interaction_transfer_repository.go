@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type InteractionTransferRepository struct {
+	db *gorm.DB
+}
+
+type IInteractionTransferRepository interface {
+	CreateInteractionTransfer(*entity.InteractionTransfer) (*entity.InteractionTransfer, error)
+	GetInteractionTransferListByInteractionId(uint) ([]entity.InteractionTransfer, error)
+}
+
+func NewInteractionTransferRepository(db *gorm.DB) *InteractionTransferRepository {
+	interactionTransferRepo := InteractionTransferRepository{
+		db: db,
+	}
+
+	return &interactionTransferRepo
+}
+
+func (itr *InteractionTransferRepository) CreateInteractionTransfer(interactionTransfer *entity.InteractionTransfer) (*entity.InteractionTransfer, error) {
+	err := itr.db.Create(&interactionTransfer).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return interactionTransfer, nil
+}
+
+func (itr *InteractionTransferRepository) GetInteractionTransferListByInteractionId(interactionId uint) ([]entity.InteractionTransfer, error) {
+	var interactionTransferList []entity.InteractionTransfer
+
+	result := itr.db.Where("interaction_id = ?", interactionId).Order("created_at ASC").Find(&interactionTransferList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return interactionTransferList, nil
+}
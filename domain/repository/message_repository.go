@@ -2,6 +2,8 @@ package repository
 
 import (
 	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
 
 	"gorm.io/gorm"
 )
@@ -13,8 +15,13 @@ type MessageRepository struct {
 type IMessageRepository interface {
 	CreateMessage(*entity.Message) (*entity.Message, error)
 	GetMessageByMetaMessageId(string) (*entity.Message, error)
+	UpdateMessageDeliveryStatus(*entity.Message) error
 	GetMessagesofInteraction(uint) ([]entity.Message, error)
 	GetLatestMessageofInteraction(uint) (*entity.Message, error)
+	GetLatestReporterMessage(string, string) (*entity.Message, error)
+	GetFirstMessageTimestamps([]uint) ([]presentation.InteractionFirstMessage, error)
+	CreateMessageAttachments([]entity.MessageAttachment) error
+	GetMessageAttachmentsofInteraction(uint) ([]entity.MessageAttachment, error)
 }
 
 func NewMessageRepository(db *gorm.DB) *MessageRepository {
@@ -32,6 +39,14 @@ func (mr *MessageRepository) CreateMessage(message *entity.Message) (*entity.Mes
 		return nil, err
 	}
 
+	// the first agent message marks the first response of the interaction
+	if message.SentBy == enum.AGENT {
+		err = mr.db.Model(&entity.Interaction{}).Where("id = ? AND first_response_at IS NULL", message.InteractionId).UpdateColumn("first_response_at", message.CreatedAt).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return message, nil
 }
 
@@ -46,6 +61,10 @@ func (mr *MessageRepository) GetMessageByMetaMessageId(metaMessageId string) (*e
 	return &message, nil
 }
 
+func (mr *MessageRepository) UpdateMessageDeliveryStatus(message *entity.Message) error {
+	return mr.db.Model(message).Select("delivery_status", "delivery_status_at", "delivery_error").Updates(message).Error
+}
+
 func (mr *MessageRepository) GetMessagesofInteraction(interactionId uint) ([]entity.Message, error) {
 	var messages []entity.Message
 
@@ -73,3 +92,59 @@ func (mr *MessageRepository) GetLatestMessageofInteraction(interactionId uint) (
 
 	return &message, nil
 }
+
+// GetLatestReporterMessage returns the last message received from the reporter
+// with the given platform id by the given business account, across all of
+// their interactions.
+func (mr *MessageRepository) GetLatestReporterMessage(senderId string, platformId string) (*entity.Message, error) {
+	var message entity.Message
+
+	err := mr.db.Joins("JOIN interactions ON interactions.id = messages.interaction_id").
+		Where("messages.sender_id = ? AND messages.sent_by = ? AND interactions.platform_id = ?", senderId, enum.REPORTER, platformId).
+		Order("messages.message_timestamp DESC").
+		Take(&message).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// GetFirstMessageTimestamps returns, per interaction, the timestamp of the first
+// message sent by the reporter and the first one sent by an agent.
+func (mr *MessageRepository) GetFirstMessageTimestamps(interactionIds []uint) ([]presentation.InteractionFirstMessage, error) {
+	var firstMessages []presentation.InteractionFirstMessage
+
+	err := mr.db.Model(&entity.Message{}).
+		Select("interaction_id, MIN(CASE WHEN sent_by = ? THEN message_timestamp END) AS first_reporter_message_at, MIN(CASE WHEN sent_by = ? THEN message_timestamp END) AS first_agent_message_at", enum.REPORTER, enum.AGENT).
+		Where("interaction_id IN ?", interactionIds).
+		Group("interaction_id").
+		Scan(&firstMessages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return firstMessages, nil
+}
+
+func (mr *MessageRepository) CreateMessageAttachments(messageAttachments []entity.MessageAttachment) error {
+	if len(messageAttachments) == 0 {
+		return nil
+	}
+
+	return mr.db.Create(&messageAttachments).Error
+}
+
+func (mr *MessageRepository) GetMessageAttachmentsofInteraction(interactionId uint) ([]entity.MessageAttachment, error) {
+	var messageAttachments []entity.MessageAttachment
+
+	result := mr.db.Where("interaction_id = ?", interactionId).Order("message_id ASC, id ASC").Find(&messageAttachments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return messageAttachments, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type MessageStatusRepository struct {
+	db *gorm.DB
+}
+
+type IMessageStatusRepository interface {
+	CreateMessageStatus(*entity.MessageStatus) (*entity.MessageStatus, error)
+	GetMessageStatus(string, string) (*entity.MessageStatus, error)
+}
+
+func NewMessageStatusRepository(db *gorm.DB) *MessageStatusRepository {
+	messageStatusRepo := MessageStatusRepository{
+		db: db,
+	}
+
+	return &messageStatusRepo
+}
+
+func (msr *MessageStatusRepository) CreateMessageStatus(messageStatus *entity.MessageStatus) (*entity.MessageStatus, error) {
+	err := msr.db.Create(&messageStatus).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return messageStatus, nil
+}
+
+func (msr *MessageStatusRepository) GetMessageStatus(metaMessageId string, status string) (*entity.MessageStatus, error) {
+	var messageStatus entity.MessageStatus
+
+	err := msr.db.Where("meta_message_id = ? AND status = ?", metaMessageId, status).Take(&messageStatus).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &messageStatus, nil
+}
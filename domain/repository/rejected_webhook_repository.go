@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type RejectedWebhookRepository struct {
+	db *gorm.DB
+}
+
+type IRejectedWebhookRepository interface {
+	CreateRejectedWebhook(*entity.RejectedWebhook) (*entity.RejectedWebhook, error)
+	GetRejectedWebhookList(map[string]interface{}) ([]entity.RejectedWebhook, error)
+}
+
+func NewRejectedWebhookRepository(db *gorm.DB) *RejectedWebhookRepository {
+	rejectedWebhookRepo := RejectedWebhookRepository{
+		db: db,
+	}
+
+	return &rejectedWebhookRepo
+}
+
+func (rwr *RejectedWebhookRepository) CreateRejectedWebhook(rejectedWebhook *entity.RejectedWebhook) (*entity.RejectedWebhook, error) {
+	err := rwr.db.Create(&rejectedWebhook).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rejectedWebhook, nil
+}
+
+func (rwr *RejectedWebhookRepository) GetRejectedWebhookList(filters map[string]interface{}) ([]entity.RejectedWebhook, error) {
+	var rejectedWebhookList []entity.RejectedWebhook
+
+	query := rwr.db.Order("created_at DESC")
+
+	if filters["platform"] != nil {
+		query = query.Where("platform = ?", filters["platform"])
+	}
+
+	if filters["reason"] != nil {
+		query = query.Where("reason = ?", filters["reason"])
+	}
+
+	if filters["since"] != nil {
+		query = query.Where("created_at >= ?", filters["since"])
+	}
+
+	result := query.Find(&rejectedWebhookList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return rejectedWebhookList, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type RoutingPolicyRepository struct {
+	db *gorm.DB
+}
+
+type IRoutingPolicyRepository interface {
+	CreateRoutingPolicy(*entity.RoutingPolicy) (*entity.RoutingPolicy, error)
+	UpdateRoutingPolicy(uint, *entity.RoutingPolicy) (*entity.RoutingPolicy, error)
+	GetRoutingPolicyList() ([]entity.RoutingPolicy, error)
+	GetRoutingPolicyById(uint) (*entity.RoutingPolicy, error)
+	GetRoutingPolicyByChannelAccountId(uint) (*entity.RoutingPolicy, error)
+	UpdateLastAssignedAgentId(uint, string) error
+	DeleteRoutingPolicy(uint) error
+}
+
+func NewRoutingPolicyRepository(db *gorm.DB) *RoutingPolicyRepository {
+	routingPolicyRepo := RoutingPolicyRepository{
+		db: db,
+	}
+
+	return &routingPolicyRepo
+}
+
+func (rpr *RoutingPolicyRepository) CreateRoutingPolicy(routingPolicy *entity.RoutingPolicy) (*entity.RoutingPolicy, error) {
+	err := rpr.db.Create(&routingPolicy).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return routingPolicy, nil
+}
+
+func (rpr *RoutingPolicyRepository) UpdateRoutingPolicy(id uint, newRoutingPolicy *entity.RoutingPolicy) (*entity.RoutingPolicy, error) {
+	var currentRoutingPolicy entity.RoutingPolicy
+
+	err := rpr.db.Where("id = ?", id).First(&currentRoutingPolicy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if newRoutingPolicy.Strategy != "" {
+		currentRoutingPolicy.Strategy = newRoutingPolicy.Strategy
+	}
+
+	if newRoutingPolicy.AgentIds != "" {
+		currentRoutingPolicy.AgentIds = newRoutingPolicy.AgentIds
+	}
+
+	err = rpr.db.Save(&currentRoutingPolicy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentRoutingPolicy, nil
+}
+
+func (rpr *RoutingPolicyRepository) GetRoutingPolicyList() ([]entity.RoutingPolicy, error) {
+	var routingPolicyList []entity.RoutingPolicy
+
+	result := rpr.db.Order("channel_account_id ASC").Find(&routingPolicyList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return routingPolicyList, nil
+}
+
+func (rpr *RoutingPolicyRepository) GetRoutingPolicyById(id uint) (*entity.RoutingPolicy, error) {
+	var routingPolicy entity.RoutingPolicy
+
+	err := rpr.db.Where("id = ?", id).Take(&routingPolicy).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &routingPolicy, nil
+}
+
+func (rpr *RoutingPolicyRepository) GetRoutingPolicyByChannelAccountId(channelAccountId uint) (*entity.RoutingPolicy, error) {
+	var routingPolicy entity.RoutingPolicy
+
+	err := rpr.db.Where("channel_account_id = ?", channelAccountId).Take(&routingPolicy).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &routingPolicy, nil
+}
+
+func (rpr *RoutingPolicyRepository) UpdateLastAssignedAgentId(id uint, agentId string) error {
+	err := rpr.db.Model(&entity.RoutingPolicy{}).Where("id = ?", id).Update("last_assigned_agent_id", agentId).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rpr *RoutingPolicyRepository) DeleteRoutingPolicy(id uint) error {
+	var routingPolicy entity.RoutingPolicy
+
+	err := rpr.db.Unscoped().Where("id = ?", id).Delete(&routingPolicy).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type SlaPolicyRepository struct {
+	db *gorm.DB
+}
+
+type ISlaPolicyRepository interface {
+	CreateSlaPolicy(*entity.SlaPolicy) (*entity.SlaPolicy, error)
+	UpdateSlaPolicy(uint, *entity.SlaPolicy) (*entity.SlaPolicy, error)
+	GetSlaPolicyList() ([]entity.SlaPolicy, error)
+	DeleteSlaPolicy(uint) error
+
+	CreateSlaEscalation(*entity.SlaEscalation) (*entity.SlaEscalation, error)
+	GetSlaEscalationList(map[string]interface{}) ([]entity.SlaEscalation, error)
+	GetSlaEscalationTypesByInteractionIds([]uint) ([]entity.SlaEscalation, error)
+}
+
+func NewSlaPolicyRepository(db *gorm.DB) *SlaPolicyRepository {
+	slaPolicyRepo := SlaPolicyRepository{
+		db: db,
+	}
+
+	return &slaPolicyRepo
+}
+
+func (spr *SlaPolicyRepository) CreateSlaPolicy(slaPolicy *entity.SlaPolicy) (*entity.SlaPolicy, error) {
+	err := spr.db.Create(&slaPolicy).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return slaPolicy, nil
+}
+
+func (spr *SlaPolicyRepository) UpdateSlaPolicy(id uint, newSlaPolicy *entity.SlaPolicy) (*entity.SlaPolicy, error) {
+	var currentSlaPolicy entity.SlaPolicy
+
+	err := spr.db.Where("id = ?", id).First(&currentSlaPolicy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if newSlaPolicy.FirstResponseMinutes != 0 {
+		currentSlaPolicy.FirstResponseMinutes = newSlaPolicy.FirstResponseMinutes
+	}
+
+	if newSlaPolicy.ResolutionMinutes != 0 {
+		currentSlaPolicy.ResolutionMinutes = newSlaPolicy.ResolutionMinutes
+	}
+
+	if newSlaPolicy.NearBreachPercent != 0 {
+		currentSlaPolicy.NearBreachPercent = newSlaPolicy.NearBreachPercent
+	}
+
+	err = spr.db.Save(&currentSlaPolicy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentSlaPolicy, nil
+}
+
+func (spr *SlaPolicyRepository) GetSlaPolicyList() ([]entity.SlaPolicy, error) {
+	var slaPolicyList []entity.SlaPolicy
+
+	result := spr.db.Order("platform ASC, interaction_type ASC").Find(&slaPolicyList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return slaPolicyList, nil
+}
+
+func (spr *SlaPolicyRepository) DeleteSlaPolicy(id uint) error {
+	var slaPolicy entity.SlaPolicy
+
+	err := spr.db.Unscoped().Where("id = ?", id).Delete(&slaPolicy).Error
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (spr *SlaPolicyRepository) CreateSlaEscalation(slaEscalation *entity.SlaEscalation) (*entity.SlaEscalation, error) {
+	err := spr.db.Create(&slaEscalation).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return slaEscalation, nil
+}
+
+func (spr *SlaPolicyRepository) GetSlaEscalationList(filters map[string]interface{}) ([]entity.SlaEscalation, error) {
+	var slaEscalationList []entity.SlaEscalation
+	queryDB := spr.db
+
+	if filters["interaction_id"] != nil {
+		queryDB = queryDB.Where("interaction_id = ?", filters["interaction_id"])
+	}
+	if filters["agent_id"] != nil {
+		queryDB = queryDB.Where("agent_id = ?", filters["agent_id"])
+	}
+
+	result := queryDB.Order("created_at DESC").Find(&slaEscalationList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return slaEscalationList, nil
+}
+
+func (spr *SlaPolicyRepository) GetSlaEscalationTypesByInteractionIds(interactionIds []uint) ([]entity.SlaEscalation, error) {
+	var slaEscalationList []entity.SlaEscalation
+
+	err := spr.db.Select("interaction_id, escalation_type").Where("interaction_id IN ?", interactionIds).Find(&slaEscalationList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return slaEscalationList, nil
+}
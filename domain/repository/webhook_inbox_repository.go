@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/package/enum"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookInboxRepository struct {
+	db *gorm.DB
+}
+
+type IWebhookInboxRepository interface {
+	CreateWebhookInbox(*entity.WebhookInbox) (*entity.WebhookInbox, error)
+	GetWebhookInboxByPayloadHash(string) (*entity.WebhookInbox, error)
+	GetWebhookInboxById(uint) (*entity.WebhookInbox, error)
+	GetWebhookInboxList(map[string]interface{}) ([]entity.WebhookInbox, error)
+	ClaimPendingWebhookInbox(time.Time) (*entity.WebhookInbox, error)
+	RequeueStaleWebhookInbox(time.Time) (int64, error)
+	UpdateWebhookInbox(*entity.WebhookInbox) error
+}
+
+func NewWebhookInboxRepository(db *gorm.DB) *WebhookInboxRepository {
+	webhookInboxRepo := WebhookInboxRepository{
+		db: db,
+	}
+
+	return &webhookInboxRepo
+}
+
+func (wir *WebhookInboxRepository) CreateWebhookInbox(webhookInbox *entity.WebhookInbox) (*entity.WebhookInbox, error) {
+	err := wir.db.Create(&webhookInbox).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookInbox, nil
+}
+
+func (wir *WebhookInboxRepository) GetWebhookInboxByPayloadHash(payloadHash string) (*entity.WebhookInbox, error) {
+	var webhookInbox entity.WebhookInbox
+
+	err := wir.db.Where("payload_hash = ?", payloadHash).Take(&webhookInbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookInbox, nil
+}
+
+func (wir *WebhookInboxRepository) GetWebhookInboxById(webhookInboxId uint) (*entity.WebhookInbox, error) {
+	var webhookInbox entity.WebhookInbox
+
+	err := wir.db.Where("id = ?", webhookInboxId).Take(&webhookInbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookInbox, nil
+}
+
+func (wir *WebhookInboxRepository) GetWebhookInboxList(filters map[string]interface{}) ([]entity.WebhookInbox, error) {
+	var webhookInboxList []entity.WebhookInbox
+
+	query := wir.db.Order("created_at DESC")
+
+	if filters["platform"] != nil {
+		query = query.Where("platform = ?", filters["platform"])
+	}
+
+	if filters["status"] != nil {
+		query = query.Where("status = ?", filters["status"])
+	}
+
+	if filters["since"] != nil {
+		query = query.Where("created_at >= ?", filters["since"])
+	}
+
+	result := query.Find(&webhookInboxList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return webhookInboxList, nil
+}
+
+// ClaimPendingWebhookInbox takes the oldest pending delivery that is due and
+// marks it PROCESSING. Rows locked by another worker are skipped, so several
+// workers can claim at the same time. It returns nil, nil when nothing is due.
+func (wir *WebhookInboxRepository) ClaimPendingWebhookInbox(now time.Time) (*entity.WebhookInbox, error) {
+	var webhookInbox entity.WebhookInbox
+
+	err := wir.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", enum.PENDING, now).
+			Order("next_attempt_at ASC").
+			Take(&webhookInbox).Error
+		if err != nil {
+			return err
+		}
+
+		webhookInbox.Status = enum.PROCESSING
+		webhookInbox.Attempts++
+
+		return tx.Save(&webhookInbox).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &webhookInbox, nil
+}
+
+// RequeueStaleWebhookInbox puts back deliveries left PROCESSING since before,
+// e.g. when the webhook service was restarted in the middle of processing.
+func (wir *WebhookInboxRepository) RequeueStaleWebhookInbox(before time.Time) (int64, error) {
+	result := wir.db.Model(&entity.WebhookInbox{}).
+		Where("status = ? AND updated_at < ?", enum.PROCESSING, before).
+		Updates(map[string]interface{}{
+			"status":          enum.PENDING,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (wir *WebhookInboxRepository) UpdateWebhookInbox(webhookInbox *entity.WebhookInbox) error {
+	return wir.db.Save(webhookInbox).Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"Omnichannel-CRM/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type WhatsappTemplateRepository struct {
+	db *gorm.DB
+}
+
+type IWhatsappTemplateRepository interface {
+	SaveWhatsappTemplate(*entity.WhatsappTemplate) (*entity.WhatsappTemplate, error)
+	GetWhatsappTemplate(string, string, string) (*entity.WhatsappTemplate, error)
+	GetWhatsappTemplateList(map[string]interface{}) ([]entity.WhatsappTemplate, error)
+}
+
+func NewWhatsappTemplateRepository(db *gorm.DB) *WhatsappTemplateRepository {
+	whatsappTemplateRepo := WhatsappTemplateRepository{
+		db: db,
+	}
+
+	return &whatsappTemplateRepo
+}
+
+// SaveWhatsappTemplate creates the template or updates the stored one with the
+// same business account, name and language.
+func (wtr *WhatsappTemplateRepository) SaveWhatsappTemplate(whatsappTemplate *entity.WhatsappTemplate) (*entity.WhatsappTemplate, error) {
+	var currentTemplate entity.WhatsappTemplate
+
+	err := wtr.db.Where(entity.WhatsappTemplate{
+		WhatsappBusinessId: whatsappTemplate.WhatsappBusinessId,
+		Name:               whatsappTemplate.Name,
+		Language:           whatsappTemplate.Language,
+	}).Assign(entity.WhatsappTemplate{
+		MetaTemplateId: whatsappTemplate.MetaTemplateId,
+		Category:       whatsappTemplate.Category,
+		Status:         whatsappTemplate.Status,
+		BodyText:       whatsappTemplate.BodyText,
+		Components:     whatsappTemplate.Components,
+		ParameterCount: whatsappTemplate.ParameterCount,
+	}).FirstOrCreate(&currentTemplate).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentTemplate, nil
+}
+
+func (wtr *WhatsappTemplateRepository) GetWhatsappTemplate(whatsappBusinessId string, name string, language string) (*entity.WhatsappTemplate, error) {
+	var whatsappTemplate entity.WhatsappTemplate
+
+	err := wtr.db.Where("whatsapp_business_id = ? AND name = ? AND language = ?", whatsappBusinessId, name, language).Take(&whatsappTemplate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &whatsappTemplate, nil
+}
+
+func (wtr *WhatsappTemplateRepository) GetWhatsappTemplateList(filters map[string]interface{}) ([]entity.WhatsappTemplate, error) {
+	var whatsappTemplateList []entity.WhatsappTemplate
+
+	query := wtr.db.Order("name ASC, language ASC")
+
+	if filters["whatsapp_business_id"] != nil {
+		query = query.Where("whatsapp_business_id = ?", filters["whatsapp_business_id"])
+	}
+
+	if filters["status"] != nil {
+		query = query.Where("status = ?", filters["status"])
+	}
+
+	if filters["name"] != nil {
+		query = query.Where("name = ?", filters["name"])
+	}
+
+	result := query.Find(&whatsappTemplateList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return whatsappTemplateList, nil
+}
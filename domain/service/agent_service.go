@@ -1,6 +1,7 @@
 package service
 
 import (
+	"Omnichannel-CRM/domain/entity"
 	"Omnichannel-CRM/domain/repository"
 	"Omnichannel-CRM/package/enum"
 	"Omnichannel-CRM/package/presentation"
@@ -13,20 +14,44 @@ import (
 type AgentService struct {
 	userRepo        repository.IUserRepository
 	interactionRepo repository.IinteractionRepository
+	csatSurveyRepo  repository.ICsatSurveyRepository
+	capacityService ICapacityService
 }
 
 type IAgentService interface {
 	GetAgentList(map[string]interface{}) (map[string]interface{}, error)
 }
 
-func NewAgentService(userRepo repository.IUserRepository, interactionRepo repository.IinteractionRepository) *AgentService {
+func NewAgentService(userRepo repository.IUserRepository, interactionRepo repository.IinteractionRepository, csatSurveyRepo repository.ICsatSurveyRepository, capacityService ICapacityService) *AgentService {
 	agentService := AgentService{
 		userRepo:        userRepo,
 		interactionRepo: interactionRepo,
+		csatSurveyRepo:  csatSurveyRepo,
+		capacityService: capacityService,
 	}
 	return &agentService
 }
 
+func (as *AgentService) getAgentCsatSummaries(agentList []entity.User) (map[string]presentation.AgentCsatSummary, error) {
+	var agentIds []string
+	csatSummaries := make(map[string]presentation.AgentCsatSummary)
+
+	for _, v := range agentList {
+		agentIds = append(agentIds, v.ID)
+	}
+
+	csatSummaryList, err := as.csatSurveyRepo.GetCsatSummaryByAgentIds(agentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range csatSummaryList {
+		csatSummaries[v.AgentId] = v
+	}
+
+	return csatSummaries, nil
+}
+
 func (as *AgentService) GetAgentList(filters map[string]interface{}) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	var agentDashboardList []presentation.AgentDashboardData
@@ -39,6 +64,11 @@ func (as *AgentService) GetAgentList(filters map[string]interface{}) (map[string
 		return nil, err
 	}
 
+	csatSummaries, err := as.getAgentCsatSummaries(agentList)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, v := range agentList {
 		agentData := presentation.AgentDashboardData{
 			AgentId:   v.ID,
@@ -51,12 +81,22 @@ func (as *AgentService) GetAgentList(filters map[string]interface{}) (map[string
 		}
 		agentData.ActiveInteraction = activeInteractionCount
 
+		remainingCapacity, err := as.capacityService.GetRemainingCapacity(&v)
+		if err != nil {
+			return nil, err
+		}
+		agentData.RemainingChatCapacity = remainingCapacity.RemainingChatCapacity
+		agentData.RemainingEmailCapacity = remainingCapacity.RemainingEmailCapacity
+
 		interactionTodayCount, err := as.interactionRepo.GetInteractionHandledTodayCount(v.ID)
 		if err != nil {
 			return nil, err
 		}
 		agentData.InteractionHandled = interactionTodayCount
 
+		agentData.CsatCount = csatSummaries[v.ID].CsatCount
+		agentData.CsatAverage = csatSummaries[v.ID].CsatAverage
+
 		agentDashboardList = append(agentDashboardList, agentData)
 	}
 
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// AttachmentStore keeps the media received from the channels and returns the
+// url the dashboard loads them from.
+type AttachmentStore interface {
+	SaveAttachment(platform string, name string, contentType string, content io.Reader) (string, error)
+}
+
+// NewAttachmentStore returns the default store, the local filesystem set by
+// Attachment.LocalDir and Attachment.BaseUrl.
+func NewAttachmentStore() AttachmentStore {
+	return NewLocalAttachmentStore(viper.GetString("Attachment.LocalDir"), viper.GetString("Attachment.BaseUrl"))
+}
+
+// LocalAttachmentStore writes the attachments under dir, which is served by
+// the /files static route (/var/www by default).
+type LocalAttachmentStore struct {
+	dir     string
+	baseUrl string
+}
+
+func NewLocalAttachmentStore(dir string, baseUrl string) *LocalAttachmentStore {
+	if dir == "" {
+		dir = "/var/www"
+	}
+	if baseUrl == "" {
+		baseUrl = "/files"
+	}
+
+	localAttachmentStore := LocalAttachmentStore{
+		dir:     dir,
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+	}
+	return &localAttachmentStore
+}
+
+// attachmentExtensions are the content types kept under their own extension.
+// The /files route serves a file by its extension, so anything else (html, svg,
+// scripts) is stored as .bin and only ever downloaded, never rendered on the
+// origin of the dashboard.
+var attachmentExtensions = map[string]string{
+	"image/jpeg":                    ".jpg",
+	"image/png":                     ".png",
+	"image/gif":                     ".gif",
+	"image/webp":                    ".webp",
+	"video/mp4":                     ".mp4",
+	"video/3gpp":                    ".3gp",
+	"video/quicktime":               ".mov",
+	"audio/mpeg":                    ".mp3",
+	"audio/ogg":                     ".ogg",
+	"audio/aac":                     ".aac",
+	"audio/mp4":                     ".m4a",
+	"audio/amr":                     ".amr",
+	"application/pdf":               ".pdf",
+	"application/zip":               ".zip",
+	"application/msword":            ".doc",
+	"application/vnd.ms-excel":      ".xls",
+	"application/vnd.ms-powerpoint": ".ppt",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"text/plain": ".txt",
+	"text/csv":   ".csv",
+}
+
+// unknownAttachmentExtension is the extension of the attachments whose content
+// type is not in attachmentExtensions.
+const unknownAttachmentExtension = ".bin"
+
+func init() {
+	// the static route falls back to sniffing the content of an extension it
+	// does not know, which could serve a .bin as html
+	for contentType, extension := range attachmentExtensions {
+		mime.AddExtensionType(extension, contentType)
+	}
+	mime.AddExtensionType(unknownAttachmentExtension, "application/octet-stream")
+}
+
+// attachmentExtension returns the extension an attachment of contentType is
+// stored with, false when the content type is not allowed.
+func attachmentExtension(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return unknownAttachmentExtension, false
+	}
+
+	extension, found := attachmentExtensions[strings.ToLower(mediaType)]
+	if !found {
+		return unknownAttachmentExtension, false
+	}
+
+	return extension, true
+}
+
+// attachmentFileName keeps the base name only, so the name given by a channel
+// cannot point outside of the store, and makes it end with the extension of
+// contentType. The extension chosen by the sender is never trusted, a
+// "page.html" is kept as "page.html.bin".
+func attachmentFileName(name string, contentType string) string {
+	name = filepath.Base(filepath.Clean("/" + name))
+
+	extension, _ := attachmentExtension(contentType)
+	if strings.EqualFold(filepath.Ext(name), extension) {
+		return name
+	}
+
+	return name + extension
+}
+
+func (las *LocalAttachmentStore) SaveAttachment(platform string, name string, contentType string, content io.Reader) (string, error) {
+	relativeDir := path.Join("attachments", strings.ToLower(platform), time.Now().Format("2006/01"))
+	fileName := attachmentFileName(name, contentType)
+
+	err := os.MkdirAll(filepath.Join(las.dir, filepath.FromSlash(relativeDir)), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(las.dir, filepath.FromSlash(relativeDir), fileName)
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, content)
+	if err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/%s", las.baseUrl, relativeDir, fileName), nil
+}
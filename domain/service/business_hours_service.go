@@ -0,0 +1,351 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BusinessHoursService struct {
+	businessCalendarRepo repository.IBusinessCalendarRepository
+	channelAccountRepo   repository.IChannelAccountRepository
+	interactionRepo      repository.IinteractionRepository
+	messageRepo          repository.IMessageRepository
+	interactionService   IInteractionService
+}
+
+type IBusinessHoursService interface {
+	IsWithinBusinessHours(*entity.BusinessCalendar, time.Time) (bool, error)
+	SendOutOfHoursReply(entity.Message) error
+
+	CreateBusinessCalendar(*presentation.BusinessCalendarModel) (map[string]interface{}, error)
+	GetBusinessCalendarList() (map[string]interface{}, error)
+	GetBusinessCalendarById(uint) (map[string]interface{}, error)
+	UpdateBusinessCalendar(*presentation.UpdateBusinessCalendarModel) (map[string]interface{}, error)
+	DeleteBusinessCalendarById(*presentation.DeleteBusinessCalendarModel) (map[string]interface{}, error)
+}
+
+func NewBusinessHoursService(businessCalendarRepo repository.IBusinessCalendarRepository, channelAccountRepo repository.IChannelAccountRepository, interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, interactionService IInteractionService) *BusinessHoursService {
+	businessHoursService := BusinessHoursService{
+		businessCalendarRepo: businessCalendarRepo,
+		channelAccountRepo:   channelAccountRepo,
+		interactionRepo:      interactionRepo,
+		messageRepo:          messageRepo,
+		interactionService:   interactionService,
+	}
+	return &businessHoursService
+}
+
+func minuteOfDay(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// IsWithinBusinessHours checks t against the holidays and the weekly schedule
+// of the calendar, both read in the calendar's timezone.
+func (bhs *BusinessHoursService) IsWithinBusinessHours(calendar *entity.BusinessCalendar, t time.Time) (bool, error) {
+	var weeklySchedule map[string][]presentation.BusinessHoursRange
+
+	location, err := time.LoadLocation(calendar.Timezone)
+	if err != nil {
+		return false, err
+	}
+	localTime := t.In(location)
+
+	date := localTime.Format("2006-01-02")
+	for _, v := range strings.Split(calendar.Holidays, ",") {
+		if v == date {
+			return false, nil
+		}
+	}
+
+	err = json.Unmarshal([]byte(calendar.WeeklySchedule), &weeklySchedule)
+	if err != nil {
+		return false, err
+	}
+
+	now := localTime.Hour()*60 + localTime.Minute()
+	for _, v := range weeklySchedule[strings.ToUpper(localTime.Weekday().String())] {
+		openAt, err := minuteOfDay(v.Open)
+		if err != nil {
+			return false, err
+		}
+
+		closeAt, err := minuteOfDay(v.Close)
+		if err != nil {
+			return false, err
+		}
+
+		if now >= openAt && now < closeAt {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getBusinessCalendar returns the calendar of the channel account, falling back
+// to the global calendar (channel account 0).
+func (bhs *BusinessHoursService) getBusinessCalendar(channelAccountId uint) (*entity.BusinessCalendar, error) {
+	calendar, err := bhs.businessCalendarRepo.GetBusinessCalendarByChannelAccountId(channelAccountId)
+	if errors.Is(err, gorm.ErrRecordNotFound) && channelAccountId != 0 {
+		calendar, err = bhs.businessCalendarRepo.GetBusinessCalendarByChannelAccountId(0)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// hasPendingAutoReply reports whether the auto-reply was already sent and no
+// agent has answered since, so a reporter writing several messages at night
+// gets a single acknowledgement.
+func (bhs *BusinessHoursService) hasPendingAutoReply(interactionId uint, autoReplyMessage string) (bool, error) {
+	messages, err := bhs.messageRepo.GetMessagesofInteraction(interactionId)
+	if err != nil {
+		return false, err
+	}
+
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].SentBy == enum.SYSTEM && messages[i].Message == autoReplyMessage {
+			return true, nil
+		}
+		if messages[i].SentBy == enum.AGENT {
+			return false, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SendOutOfHoursReply answers an inbound WhatsApp, Messenger, Instagram or live
+// chat message received outside the business hours of its channel account.
+// The auto-reply goes through the regular outbound path and is stored with
+// SentBy SYSTEM.
+func (bhs *BusinessHoursService) SendOutOfHoursReply(message entity.Message) error {
+	if message.SentBy != enum.REPORTER {
+		return nil
+	}
+
+	interaction, err := bhs.interactionRepo.GetInteractionById(message.InteractionId)
+	if err != nil {
+		return err
+	}
+
+	if interaction.InteractionType == enum.MENTION || interaction.Status == enum.BOT || interaction.Status == enum.CLOSED {
+		return nil
+	}
+	capabilities, err := bhs.interactionService.GetChannelCapabilities(interaction.Platform)
+	if err != nil || !capabilities.AutoReply {
+		return nil
+	}
+
+	channelAccount, err := getInteractionChannelAccount(bhs.channelAccountRepo, interaction)
+	if channelAccount == nil || err != nil {
+		return err
+	}
+
+	calendar, err := bhs.getBusinessCalendar(channelAccount.ID)
+	if calendar == nil || err != nil {
+		return err
+	}
+	if !calendar.IsActive || calendar.AutoReplyMessage == "" {
+		return nil
+	}
+
+	isOpen, err := bhs.IsWithinBusinessHours(calendar, messageTime(&message))
+	if isOpen || err != nil {
+		return err
+	}
+
+	isReplied, err := bhs.hasPendingAutoReply(interaction.ID, calendar.AutoReplyMessage)
+	if isReplied || err != nil {
+		return err
+	}
+
+	err = sendSystemMessage(bhs.interactionService, interaction, channelAccount, calendar.AutoReplyMessage)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("[Business Hours] Out of hours auto-reply sent for interaction %d", interaction.ID))
+
+	return nil
+}
+
+// sendSystemMessage sends an automatic message to the reporter through the
+// regular outbound path of the platform, stores it with SentBy SYSTEM and
+// pushes it to the agent dashboard.
+func sendSystemMessage(interactionService IInteractionService, interaction *entity.Interaction, channelAccount *entity.ChannelAccount, text string) error {
+	msmr := presentation.MetaSendMessageRequest{
+		InteractionId: interaction.ID,
+		PlatformId:    interaction.PlatformId,
+		ReporterId:    interaction.ReporterId,
+		Message:       text,
+		Platform:      interaction.Platform,
+		SentBy:        enum.SYSTEM,
+	}
+
+	capabilities, err := interactionService.GetChannelCapabilities(interaction.Platform)
+	if err != nil {
+		return err
+	}
+
+	_, message, err := interactionService.SendMessage(&msmr, channelAccount)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.Realtime || message == nil {
+		return nil
+	}
+
+	return interactionService.WebsocketSendService(*message)
+}
+
+func normalizeWeeklySchedule(weeklySchedule map[string][]presentation.BusinessHoursRange) (string, error) {
+	normalizedSchedule := make(map[string][]presentation.BusinessHoursRange)
+	for day, ranges := range weeklySchedule {
+		normalizedSchedule[strings.ToUpper(day)] = ranges
+	}
+
+	scheduleJson, err := json.Marshal(normalizedSchedule)
+	if err != nil {
+		return "", err
+	}
+
+	return string(scheduleJson), nil
+}
+
+func (bhs *BusinessHoursService) CreateBusinessCalendar(bcm *presentation.BusinessCalendarModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	weeklySchedule, err := normalizeWeeklySchedule(bcm.WeeklySchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	newBusinessCalendar := entity.BusinessCalendar{
+		ChannelAccountId: bcm.ChannelAccountId,
+		Timezone:         bcm.Timezone,
+		WeeklySchedule:   weeklySchedule,
+		Holidays:         strings.Join(bcm.Holidays, ","),
+		AutoReplyMessage: bcm.AutoReplyMessage,
+		IsActive:         bcm.IsActive,
+	}
+
+	businessCalendar, err := bhs.businessCalendarRepo.CreateBusinessCalendar(&newBusinessCalendar)
+	if err != nil {
+		return nil, err
+	}
+
+	result["business_calendar"] = businessCalendar
+
+	return result, nil
+}
+
+func (bhs *BusinessHoursService) GetBusinessCalendarList() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	businessCalendarList, err := bhs.businessCalendarRepo.GetBusinessCalendarList()
+	if (businessCalendarList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["business_calendar_list"] = businessCalendarList
+
+	return result, nil
+}
+
+func (bhs *BusinessHoursService) GetBusinessCalendarById(businessCalendarId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	businessCalendar, err := bhs.businessCalendarRepo.GetBusinessCalendarById(businessCalendarId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["business_calendar"] = businessCalendar
+
+	return result, nil
+}
+
+func (bhs *BusinessHoursService) UpdateBusinessCalendar(ubcm *presentation.UpdateBusinessCalendarModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	currentBusinessCalendar, err := bhs.businessCalendarRepo.GetBusinessCalendarById(ubcm.BusinessCalendarId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	newBusinessCalendar := entity.BusinessCalendar{
+		Timezone:         ubcm.Timezone,
+		Holidays:         strings.Join(ubcm.Holidays, ","),
+		AutoReplyMessage: ubcm.AutoReplyMessage,
+		IsActive:         currentBusinessCalendar.IsActive,
+	}
+
+	if ubcm.WeeklySchedule != nil {
+		newBusinessCalendar.WeeklySchedule, err = normalizeWeeklySchedule(ubcm.WeeklySchedule)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ubcm.IsActive != nil {
+		newBusinessCalendar.IsActive = *ubcm.IsActive
+	}
+
+	businessCalendar, err := bhs.businessCalendarRepo.UpdateBusinessCalendar(ubcm.BusinessCalendarId, &newBusinessCalendar)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["business_calendar"] = businessCalendar
+
+	return result, nil
+}
+
+func (bhs *BusinessHoursService) DeleteBusinessCalendarById(dbcm *presentation.DeleteBusinessCalendarModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	err := bhs.businessCalendarRepo.DeleteBusinessCalendar(dbcm.BusinessCalendarId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
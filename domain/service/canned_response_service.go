@@ -0,0 +1,265 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type CannedResponseService struct {
+	cannedResponseRepo repository.ICannedResponseRepository
+	interactionRepo    repository.IinteractionRepository
+	reporterRepo       repository.IReporterRepository
+	userRepo           repository.IUserRepository
+}
+
+type ICannedResponseService interface {
+	RenderCannedResponse(uint, uint, string, uint) (string, error)
+
+	CreateCannedResponse(*presentation.CannedResponseModel, string, int) (map[string]interface{}, error)
+	GetCannedResponseList(map[string]interface{}) (map[string]interface{}, error)
+	GetAvailableCannedResponseList(string, uint) (map[string]interface{}, error)
+	GetCannedResponseById(uint, string, uint, int) (map[string]interface{}, error)
+	UpdateCannedResponse(*presentation.UpdateCannedResponseModel, string, int) (map[string]interface{}, error)
+	DeleteCannedResponseById(*presentation.DeleteCannedResponseModel, string, int) (map[string]interface{}, error)
+}
+
+func NewCannedResponseService(cannedResponseRepo repository.ICannedResponseRepository, interactionRepo repository.IinteractionRepository, reporterRepo repository.IReporterRepository, userRepo repository.IUserRepository) *CannedResponseService {
+	cannedResponseService := CannedResponseService{
+		cannedResponseRepo: cannedResponseRepo,
+		interactionRepo:    interactionRepo,
+		reporterRepo:       reporterRepo,
+		userRepo:           userRepo,
+	}
+	return &cannedResponseService
+}
+
+func isCannedResponseAvailable(cannedResponse *entity.CannedResponse, userId string, channelAccountId uint) bool {
+	switch cannedResponse.Scope {
+	case enum.GLOBAL:
+		return true
+	case enum.CHANNEL_ACCOUNT:
+		return cannedResponse.ChannelAccountId == channelAccountId
+	case enum.AGENT:
+		return cannedResponse.AgentId == userId
+	}
+
+	return false
+}
+
+// canManageCannedResponse allows admins to manage every response and agents to
+// manage only their own AGENT scoped ones.
+func canManageCannedResponse(cannedResponse *entity.CannedResponse, userId string, role int) bool {
+	if isAdminRole(role) {
+		return true
+	}
+
+	return cannedResponse.Scope == enum.AGENT && cannedResponse.AgentId == userId
+}
+
+// RenderCannedResponse returns the content of the canned response with its
+// placeholders filled for the interaction:
+// {{reporter_name}}, {{reporter_phone}}, {{agent_name}}, {{agent_first_name}},
+// {{agent_last_name}} and {{agent_email}}.
+func (crs *CannedResponseService) RenderCannedResponse(cannedResponseId uint, interactionId uint, userId string, channelAccountId uint) (string, error) {
+	reporter := &entity.Reporter{}
+	agent := entity.User{}
+
+	cannedResponse, err := crs.cannedResponseRepo.GetCannedResponseById(cannedResponseId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return "", err
+	}
+
+	if !isCannedResponseAvailable(cannedResponse, userId, channelAccountId) {
+		return "", enum.CANNED_RESPONSE_NOT_ACCESSIBLE
+	}
+
+	interaction, err := crs.interactionRepo.GetInteractionById(interactionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return "", err
+	}
+
+	if interaction.ReporterId != 0 {
+		reporter, err = crs.reporterRepo.GetReporterByReporterId(interaction.ReporterId)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		if reporter == nil {
+			reporter = &entity.Reporter{}
+		}
+	}
+
+	userList, err := crs.userRepo.GetUserListByIds([]string{userId})
+	if err != nil {
+		return "", err
+	}
+	if len(userList) > 0 {
+		agent = userList[0]
+	}
+
+	replacer := strings.NewReplacer(
+		"{{reporter_name}}", reporter.Name,
+		"{{reporter_phone}}", reporter.PhoneNumber,
+		"{{agent_name}}", strings.TrimSpace(agent.FirstName+" "+agent.LastName),
+		"{{agent_first_name}}", agent.FirstName,
+		"{{agent_last_name}}", agent.LastName,
+		"{{agent_email}}", agent.Email,
+	)
+
+	return replacer.Replace(cannedResponse.Content), nil
+}
+
+func (crs *CannedResponseService) CreateCannedResponse(crm *presentation.CannedResponseModel, userId string, role int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newCannedResponse := entity.CannedResponse{
+		Title:    crm.Title,
+		Shortcut: crm.Shortcut,
+		Content:  crm.Content,
+		Scope:    crm.Scope,
+	}
+
+	switch crm.Scope {
+	case enum.CHANNEL_ACCOUNT:
+		newCannedResponse.ChannelAccountId = crm.ChannelAccountId
+	case enum.AGENT:
+		newCannedResponse.AgentId = userId
+		if isAdminRole(role) && crm.AgentId != "" {
+			newCannedResponse.AgentId = crm.AgentId
+		}
+	}
+
+	if !canManageCannedResponse(&newCannedResponse, userId, role) {
+		return nil, enum.CANNED_RESPONSE_NOT_ACCESSIBLE
+	}
+
+	cannedResponse, err := crs.cannedResponseRepo.CreateCannedResponse(&newCannedResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	result["canned_response"] = cannedResponse
+
+	return result, nil
+}
+
+func (crs *CannedResponseService) GetCannedResponseList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	cannedResponseList, err := crs.cannedResponseRepo.GetCannedResponseList(filters)
+	if (cannedResponseList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["canned_response_list"] = cannedResponseList
+
+	return result, nil
+}
+
+func (crs *CannedResponseService) GetAvailableCannedResponseList(userId string, channelAccountId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	cannedResponseList, err := crs.cannedResponseRepo.GetAvailableCannedResponseList(userId, channelAccountId)
+	if (cannedResponseList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["canned_response_list"] = cannedResponseList
+
+	return result, nil
+}
+
+func (crs *CannedResponseService) GetCannedResponseById(cannedResponseId uint, userId string, channelAccountId uint, role int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	cannedResponse, err := crs.cannedResponseRepo.GetCannedResponseById(cannedResponseId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !isAdminRole(role) && !isCannedResponseAvailable(cannedResponse, userId, channelAccountId) {
+		return nil, enum.CANNED_RESPONSE_NOT_ACCESSIBLE
+	}
+
+	result["canned_response"] = cannedResponse
+
+	return result, nil
+}
+
+func (crs *CannedResponseService) UpdateCannedResponse(ucrm *presentation.UpdateCannedResponseModel, userId string, role int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	currentCannedResponse, err := crs.cannedResponseRepo.GetCannedResponseById(ucrm.CannedResponseId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !canManageCannedResponse(currentCannedResponse, userId, role) {
+		return nil, enum.CANNED_RESPONSE_NOT_ACCESSIBLE
+	}
+
+	newCannedResponse := entity.CannedResponse{
+		Title:    ucrm.Title,
+		Shortcut: ucrm.Shortcut,
+		Content:  ucrm.Content,
+	}
+
+	cannedResponse, err := crs.cannedResponseRepo.UpdateCannedResponse(ucrm.CannedResponseId, &newCannedResponse)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["canned_response"] = cannedResponse
+
+	return result, nil
+}
+
+func (crs *CannedResponseService) DeleteCannedResponseById(dcrm *presentation.DeleteCannedResponseModel, userId string, role int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	cannedResponse, err := crs.cannedResponseRepo.GetCannedResponseById(dcrm.CannedResponseId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !canManageCannedResponse(cannedResponse, userId, role) {
+		return nil, enum.CANNED_RESPONSE_NOT_ACCESSIBLE
+	}
+
+	err = crs.cannedResponseRepo.DeleteCannedResponse(dcrm.CannedResponseId)
+	if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
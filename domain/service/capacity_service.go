@@ -0,0 +1,220 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type CapacityService struct {
+	agentCapacityRepo repository.IAgentCapacityRepository
+	interactionRepo   repository.IinteractionRepository
+	userRepo          repository.IUserRepository
+}
+
+type ICapacityService interface {
+	CheckAgentCapacity(string, string) error
+	GetRemainingCapacity(*entity.User) (*presentation.AgentRemainingCapacity, error)
+
+	CreateAgentCapacity(*presentation.AgentCapacityModel) (map[string]interface{}, error)
+	GetAgentCapacityList() (map[string]interface{}, error)
+	UpdateAgentCapacity(*presentation.UpdateAgentCapacityModel) (map[string]interface{}, error)
+	DeleteAgentCapacityById(*presentation.DeleteAgentCapacityModel) (map[string]interface{}, error)
+}
+
+func NewCapacityService(agentCapacityRepo repository.IAgentCapacityRepository, interactionRepo repository.IinteractionRepository, userRepo repository.IUserRepository) *CapacityService {
+	capacityService := CapacityService{
+		agentCapacityRepo: agentCapacityRepo,
+		interactionRepo:   interactionRepo,
+		userRepo:          userRepo,
+	}
+	return &capacityService
+}
+
+// getCapacityLimit resolves the limits of an agent: an agent specific row wins
+// over the row of the agent's role, which wins over the configured defaults.
+// A limit of 0 means the agent is not limited.
+func (cs *CapacityService) getCapacityLimit(agent *entity.User) (*entity.AgentCapacity, error) {
+	agentCapacity, err := cs.agentCapacityRepo.GetAgentCapacityByAgentId(agent.ID)
+	if err == nil {
+		return agentCapacity, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	agentCapacity, err = cs.agentCapacityRepo.GetAgentCapacityByRole(agent.Role)
+	if err == nil {
+		return agentCapacity, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	defaultCapacity := entity.AgentCapacity{
+		ChatLimit:  viper.GetInt64("Capacity.DefaultChatLimit"),
+		EmailLimit: viper.GetInt64("Capacity.DefaultEmailLimit"),
+	}
+
+	return &defaultCapacity, nil
+}
+
+func (cs *CapacityService) getActiveCount(agentId string) (chatCount int64, emailCount int64, err error) {
+	counts, err := cs.interactionRepo.GetActiveInteractionCountByPlatform(agentId)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for platform, count := range counts {
+		if platform == enum.EMAIL {
+			emailCount += count
+		} else {
+			chatCount += count
+		}
+	}
+
+	return chatCount, emailCount, nil
+}
+
+// CheckAgentCapacity returns enum.AGENT_CAPACITY_EXCEEDED when the agent can not
+// take another interaction of the given platform.
+func (cs *CapacityService) CheckAgentCapacity(agentId string, platform string) error {
+	agentList, err := cs.userRepo.GetUserListByIds([]string{agentId})
+	if err != nil {
+		return err
+	}
+	if len(agentList) == 0 {
+		return enum.ERROR_DATA_NOT_FOUND
+	}
+
+	remainingCapacity, err := cs.GetRemainingCapacity(&agentList[0])
+	if err != nil {
+		return err
+	}
+
+	remaining := remainingCapacity.RemainingChatCapacity
+	if platform == enum.EMAIL {
+		remaining = remainingCapacity.RemainingEmailCapacity
+	}
+
+	if remaining != nil && *remaining <= 0 {
+		return enum.AGENT_CAPACITY_EXCEEDED
+	}
+
+	return nil
+}
+
+func (cs *CapacityService) GetRemainingCapacity(agent *entity.User) (*presentation.AgentRemainingCapacity, error) {
+	var remainingCapacity presentation.AgentRemainingCapacity
+
+	agentCapacity, err := cs.getCapacityLimit(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	chatCount, emailCount, err := cs.getActiveCount(agent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agentCapacity.ChatLimit > 0 {
+		remainingChat := agentCapacity.ChatLimit - chatCount
+		remainingCapacity.RemainingChatCapacity = &remainingChat
+	}
+
+	if agentCapacity.EmailLimit > 0 {
+		remainingEmail := agentCapacity.EmailLimit - emailCount
+		remainingCapacity.RemainingEmailCapacity = &remainingEmail
+	}
+
+	return &remainingCapacity, nil
+}
+
+func (cs *CapacityService) CreateAgentCapacity(acm *presentation.AgentCapacityModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newAgentCapacity := entity.AgentCapacity{
+		AgentId:    acm.AgentId,
+		Role:       acm.Role,
+		ChatLimit:  acm.ChatLimit,
+		EmailLimit: acm.EmailLimit,
+	}
+
+	agentCapacity, err := cs.agentCapacityRepo.CreateAgentCapacity(&newAgentCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	result["agent_capacity"] = agentCapacity
+
+	return result, nil
+}
+
+func (cs *CapacityService) GetAgentCapacityList() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	agentCapacityList, err := cs.agentCapacityRepo.GetAgentCapacityList()
+	if (agentCapacityList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["agent_capacity_list"] = agentCapacityList
+
+	return result, nil
+}
+
+func (cs *CapacityService) UpdateAgentCapacity(uacm *presentation.UpdateAgentCapacityModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	currentAgentCapacity, err := cs.agentCapacityRepo.GetAgentCapacityById(uacm.AgentCapacityId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	newAgentCapacity := entity.AgentCapacity{
+		ChatLimit:  currentAgentCapacity.ChatLimit,
+		EmailLimit: currentAgentCapacity.EmailLimit,
+	}
+	if uacm.ChatLimit != nil {
+		newAgentCapacity.ChatLimit = *uacm.ChatLimit
+	}
+	if uacm.EmailLimit != nil {
+		newAgentCapacity.EmailLimit = *uacm.EmailLimit
+	}
+
+	agentCapacity, err := cs.agentCapacityRepo.UpdateAgentCapacity(uacm.AgentCapacityId, &newAgentCapacity)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["agent_capacity"] = agentCapacity
+
+	return result, nil
+}
+
+func (cs *CapacityService) DeleteAgentCapacityById(dacm *presentation.DeleteAgentCapacityModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	err := cs.agentCapacityRepo.DeleteAgentCapacity(dacm.AgentCapacityId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
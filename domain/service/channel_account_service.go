@@ -7,6 +7,7 @@ import (
 	"Omnichannel-CRM/package/presentation"
 
 	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -30,6 +31,38 @@ func NewChannelAccountService(channelAccountRepo repository.IChannelAccountRepos
 	return &channelAccountService
 }
 
+// telegramBotId returns the bot id part of a Telegram bot token
+// ("<bot_id>:<secret>"), the bot id is used as the platform id of Telegram
+// interactions.
+func telegramBotId(botToken string) string {
+	botId, _, found := strings.Cut(botToken, ":")
+	if !found {
+		return ""
+	}
+
+	return botId
+}
+
+// loadChannelAccount returns the stored channel account of the one in the JWT.
+// The token only holds the channel account as it was at login, so it is not
+// trusted to scope what the user sees or sends through. A channel account
+// removed since then gives an empty one.
+func loadChannelAccount(channelAccountRepo repository.IChannelAccountRepository, channelAccount *entity.ChannelAccount) (*entity.ChannelAccount, error) {
+	if channelAccount == nil || channelAccount.ID == 0 {
+		return channelAccount, nil
+	}
+
+	storedChannelAccount, err := channelAccountRepo.GetChannelAccountById(channelAccount.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entity.ChannelAccount{}, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return storedChannelAccount, nil
+}
+
 func (cas *ChannelAccountService) CreateChannelAccount(cam *presentation.ChannelAccountModel) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
@@ -42,6 +75,18 @@ func (cas *ChannelAccountService) CreateChannelAccount(cam *presentation.Channel
 		FacebookAccessToken:  cam.FacebookAccessToken,
 		InstagramAccessToken: cam.InstagramAccessToken,
 		WhatsappAccessToken:  cam.WhatsappAccessToken,
+		MetaAppSecret:        cam.MetaAppSecret,
+		TelegramBotId:        telegramBotId(cam.TelegramBotToken),
+		TelegramBotToken:     cam.TelegramBotToken,
+		TelegramSecretToken:  cam.TelegramSecretToken,
+		EmailAddress:         cam.EmailAddress,
+		GmailRefreshToken:    cam.GmailRefreshToken,
+		ImapAddress:          cam.ImapAddress,
+		ImapUsername:         cam.ImapUsername,
+		ImapPassword:         cam.ImapPassword,
+		SmtpAddress:          cam.SmtpAddress,
+		SmtpUsername:         cam.SmtpUsername,
+		SmtpPassword:         cam.SmtpPassword,
 	}
 
 	channelAccount, err := cas.channelAccountRepo.CreateChannelAccount(&newChannelAccount)
@@ -99,6 +144,18 @@ func (cas *ChannelAccountService) UpdateChannelAccount(ucam *presentation.Update
 		FacebookAccessToken:  ucam.FacebookAccessToken,
 		InstagramAccessToken: ucam.InstagramAccessToken,
 		WhatsappAccessToken:  ucam.WhatsappAccessToken,
+		MetaAppSecret:        ucam.MetaAppSecret,
+		TelegramBotId:        telegramBotId(ucam.TelegramBotToken),
+		TelegramBotToken:     ucam.TelegramBotToken,
+		TelegramSecretToken:  ucam.TelegramSecretToken,
+		EmailAddress:         ucam.EmailAddress,
+		GmailRefreshToken:    ucam.GmailRefreshToken,
+		ImapAddress:          ucam.ImapAddress,
+		ImapUsername:         ucam.ImapUsername,
+		ImapPassword:         ucam.ImapPassword,
+		SmtpAddress:          ucam.SmtpAddress,
+		SmtpUsername:         ucam.SmtpUsername,
+		SmtpPassword:         ucam.SmtpPassword,
 	}
 
 	channelAccount, err := cas.channelAccountRepo.UpdateChannelAccount(channelAccountId, &newChannelAccount)
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+)
+
+type ChannelCapabilities struct {
+	// Webhook channels receive their messages through ParseInbound
+	Webhook bool
+	// Media channels resolve inbound media ids through FetchMedia
+	Media bool
+	// AutoReply channels receive automatic replies such as out of hours messages
+	AutoReply bool
+	// Realtime channels push sent messages to the agent dashboard websocket
+	Realtime bool
+	// Attachments channels send the files and location pins of agents
+	Attachments bool
+}
+
+// ChannelAdapter holds everything that is specific to a messaging platform.
+// The reporter, interaction and message pipeline is shared by every channel,
+// see ChannelWebhookService.
+type ChannelAdapter interface {
+	Platform() string
+	Capabilities() ChannelCapabilities
+	VerifyWebhook(delivery *presentation.WebhookDelivery) error
+	ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error)
+	FetchMedia(inboundMessage *presentation.InboundMessage) error
+	SendMessage(*presentation.MetaSendMessageRequest, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
+}
+
+// MessageStatusParser is implemented by the adapters of channels whose
+// webhooks also report the delivery status of the messages sent by agents.
+type MessageStatusParser interface {
+	ParseStatuses(platformId string, body []byte) ([]presentation.MessageStatusUpdate, error)
+}
+
+type ChannelAdapterRegistry struct {
+	adapters map[string]ChannelAdapter
+}
+
+type IChannelAdapterRegistry interface {
+	Register(ChannelAdapter)
+	GetAdapter(string) (ChannelAdapter, error)
+}
+
+func NewChannelAdapterRegistry(adapters ...ChannelAdapter) *ChannelAdapterRegistry {
+	channelAdapterRegistry := ChannelAdapterRegistry{
+		adapters: make(map[string]ChannelAdapter),
+	}
+	for _, adapter := range adapters {
+		channelAdapterRegistry.Register(adapter)
+	}
+	return &channelAdapterRegistry
+}
+
+// NewDefaultChannelAdapterRegistry registers the adapters of every supported
+// platform, a new channel only has to be added here.
+func NewDefaultChannelAdapterRegistry(channelAccountRepo repository.IChannelAccountRepository, interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, emailService IEmailService) *ChannelAdapterRegistry {
+	return NewChannelAdapterRegistry(
+		NewWhatsappAdapter(channelAccountRepo, messageRepo, reporterRepo, NewWhatsappMediaClient(), NewAttachmentStore()),
+		NewMessengerAdapter(enum.FACEBOOK, channelAccountRepo, messageRepo, reporterRepo),
+		NewMessengerAdapter(enum.IG, channelAccountRepo, messageRepo, reporterRepo),
+		NewLiveChatAdapter(interactionRepo, messageRepo),
+		NewTelegramAdapter(channelAccountRepo, messageRepo, reporterRepo),
+		NewEmailAdapter(emailService),
+	)
+}
+
+func (car *ChannelAdapterRegistry) Register(adapter ChannelAdapter) {
+	car.adapters[adapter.Platform()] = adapter
+}
+
+func (car *ChannelAdapterRegistry) GetAdapter(platform string) (ChannelAdapter, error) {
+	adapter, ok := car.adapters[platform]
+	if !ok {
+		return nil, enum.INVALID_PLATFORM
+	}
+
+	return adapter, nil
+}
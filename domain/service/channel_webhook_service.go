@@ -0,0 +1,504 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/config"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type ChannelWebhookService struct {
+	channelAdapterRegistry IChannelAdapterRegistry
+	interactionRepo        repository.IinteractionRepository
+	messageRepo            repository.IMessageRepository
+	reporterRepo           repository.IReporterRepository
+	routingService         IRoutingService
+	businessHoursService   IBusinessHoursService
+	chatbotService         IChatbotService
+	csatSurveyRepo         repository.ICsatSurveyRepository
+	rejectedWebhookRepo    repository.IRejectedWebhookRepository
+	messageStatusRepo      repository.IMessageStatusRepository
+}
+
+type IChannelWebhookService interface {
+	VerifyWebhook(string, *presentation.WebhookDelivery) error
+	ProcessWebhookInbox(*entity.WebhookInbox) error
+	ProcessInboundMessages(string, []presentation.InboundMessage) (map[string]interface{}, []entity.Message, error)
+	ProcessStatusUpdates([]presentation.MessageStatusUpdate) ([]presentation.MessageStatusNotification, error)
+	WebsocketSendService(messages []entity.Message) error
+	RunChatbot(messages []entity.Message)
+	ReplyOutOfHours(messages []entity.Message)
+	WebsocketMessageStatusService([]presentation.MessageStatusNotification) error
+}
+
+func NewChannelWebhookService(channelAdapterRegistry IChannelAdapterRegistry, interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, routingService IRoutingService, businessHoursService IBusinessHoursService, chatbotService IChatbotService, csatSurveyRepo repository.ICsatSurveyRepository, rejectedWebhookRepo repository.IRejectedWebhookRepository, messageStatusRepo repository.IMessageStatusRepository) *ChannelWebhookService {
+	channelWebhookService := ChannelWebhookService{
+		channelAdapterRegistry: channelAdapterRegistry,
+		interactionRepo:        interactionRepo,
+		messageRepo:            messageRepo,
+		reporterRepo:           reporterRepo,
+		routingService:         routingService,
+		businessHoursService:   businessHoursService,
+		chatbotService:         chatbotService,
+		csatSurveyRepo:         csatSurveyRepo,
+		rejectedWebhookRepo:    rejectedWebhookRepo,
+		messageStatusRepo:      messageStatusRepo,
+	}
+	return &channelWebhookService
+}
+
+type MessageToSend struct {
+	Action  string               `json:"action"`
+	Message presentation.Message `json:"message"`
+}
+
+type MessageStatusToSend struct {
+	Action string                                 `json:"action"`
+	Status presentation.MessageStatusNotification `json:"status"`
+}
+
+// RunChatbot lets the chatbot answer the received messages of interactions that
+// are still handled by a bot flow. Failures are logged like ReplyOutOfHours.
+func (cws *ChannelWebhookService) RunChatbot(messages []entity.Message) {
+	for _, message := range messages {
+		err := cws.chatbotService.HandleReporterMessage(message)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Chatbot] Interaction %d: %+v", message.InteractionId, err))
+		}
+	}
+}
+
+// ReplyOutOfHours sends the out-of-hours auto-reply for the received messages.
+// The webhook has already been processed, so failures are only logged.
+func (cws *ChannelWebhookService) ReplyOutOfHours(messages []entity.Message) {
+	for _, message := range messages {
+		err := cws.businessHoursService.SendOutOfHoursReply(message)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Out of Hours Reply] Interaction %d: %+v", message.InteractionId, err))
+		}
+	}
+}
+
+func (cws *ChannelWebhookService) WebsocketSendService(messages []entity.Message) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	for _, message := range messages {
+		query := fmt.Sprintf("user_id=%s&room_id=%d", message.SenderId, message.InteractionId)
+		client, err := NewWebSocketClient(host, channel, message.SenderId, query)
+		if err != nil {
+			return err
+		}
+		send := MessageToSend{
+			Action: "send-message",
+			Message: presentation.Message{
+				ID:               message.ID,
+				CreatedAt:        message.CreatedAt,
+				UpdatedAt:        message.UpdatedAt,
+				InteractionId:    message.InteractionId,
+				SenderId:         message.SenderId,
+				RecipientId:      message.RecipientId,
+				MetaMessageId:    message.MetaMessageId,
+				Message:          message.Message,
+				MessageTimestamp: message.MessageTimestamp,
+				AttachmentType:   message.AttachmentType,
+				AttachmentUrl:    message.AttachmentUrl,
+				SentBy:           message.SentBy,
+				IsRead:           message.IsRead,
+				IsDeleted:        message.IsDeleted,
+			},
+		}
+		client.Write(send)
+	}
+
+	return nil
+}
+
+// VerifyWebhook checks a webhook delivery with the adapter of the platform
+// before it is queued. Deliveries that fail verification are recorded for
+// review. delivery.PlatformId is only needed by channels that do not send it
+// in the payload.
+func (cws *ChannelWebhookService) VerifyWebhook(platform string, delivery *presentation.WebhookDelivery) error {
+	adapter, err := cws.channelAdapterRegistry.GetAdapter(platform)
+	if err != nil {
+		return err
+	}
+
+	if !adapter.Capabilities().Webhook {
+		return enum.INVALID_PLATFORM
+	}
+
+	err = adapter.VerifyWebhook(delivery)
+	if err != nil {
+		cws.recordRejectedWebhook(platform, delivery, err)
+		return err
+	}
+
+	return nil
+}
+
+// ProcessWebhookInbox parses a queued delivery and runs its messages through
+// the inbound pipeline, the dashboard, the chatbot and the out-of-hours reply.
+// An error leaves the delivery to be retried, messages stored by an earlier
+// attempt are skipped by their message id. A failed dashboard push is only
+// logged since the messages are already stored.
+func (cws *ChannelWebhookService) ProcessWebhookInbox(webhookInbox *entity.WebhookInbox) error {
+	adapter, err := cws.channelAdapterRegistry.GetAdapter(webhookInbox.Platform)
+	if err != nil {
+		return err
+	}
+
+	inboundMessages, err := adapter.ParseInbound(webhookInbox.PlatformId, []byte(webhookInbox.Payload))
+	if err != nil {
+		return err
+	}
+
+	_, resMessage, err := cws.ProcessInboundMessages(webhookInbox.Platform, inboundMessages)
+	if err != nil {
+		return err
+	}
+
+	err = cws.WebsocketSendService(resMessage)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Websocket: %+v", webhookInbox.Platform, err))
+	}
+
+	cws.RunChatbot(resMessage)
+	cws.ReplyOutOfHours(resMessage)
+
+	statusParser, ok := adapter.(MessageStatusParser)
+	if !ok {
+		return nil
+	}
+
+	statusUpdates, err := statusParser.ParseStatuses(webhookInbox.PlatformId, []byte(webhookInbox.Payload))
+	if err != nil {
+		return err
+	}
+
+	notifications, err := cws.ProcessStatusUpdates(statusUpdates)
+	if len(notifications) > 0 {
+		websocketErr := cws.WebsocketMessageStatusService(notifications)
+		if websocketErr != nil {
+			logger.Info(fmt.Sprintf("[FAILED][%s Webhook Interaction] Websocket Message Status: %+v", webhookInbox.Platform, websocketErr))
+		}
+	}
+
+	return err
+}
+
+// messageDeliveryStatusRank orders the delivery statuses, statuses may arrive
+// out of order and a message never goes back to an earlier status.
+func messageDeliveryStatusRank(status string) int {
+	switch status {
+	case enum.SENT:
+		return 1
+	case enum.DELIVERED:
+		return 2
+	case enum.READ:
+		return 3
+	case enum.FAILED:
+		return 4
+	}
+	return 0
+}
+
+// ProcessStatusUpdates records the received delivery statuses and moves the
+// sent messages they refer to forward. It returns the status changes to push
+// to the interaction rooms, statuses already received are skipped. A status
+// can arrive before the message it refers to is stored, such statuses are left
+// unrecorded and MESSAGE_NOT_FOUND is returned with the other changes so that
+// the inbox retries the delivery.
+func (cws *ChannelWebhookService) ProcessStatusUpdates(statusUpdates []presentation.MessageStatusUpdate) ([]presentation.MessageStatusNotification, error) {
+	var notifications []presentation.MessageStatusNotification
+	var missingMessageIds []string
+
+	for _, statusUpdate := range statusUpdates {
+		_, err := cws.messageStatusRepo.GetMessageStatus(statusUpdate.MessageId, statusUpdate.Status)
+		if err == nil {
+			continue
+
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		messageStatus := entity.MessageStatus{
+			MetaMessageId:   statusUpdate.MessageId,
+			RecipientId:     statusUpdate.RecipientId,
+			Status:          statusUpdate.Status,
+			ErrorCode:       statusUpdate.ErrorCode,
+			ErrorTitle:      statusUpdate.ErrorTitle,
+			ErrorMessage:    statusUpdate.ErrorMessage,
+			StatusTimestamp: statusUpdate.StatusTimestamp,
+		}
+
+		message, err := cws.messageRepo.GetMessageByMetaMessageId(statusUpdate.MessageId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			missingMessageIds = append(missingMessageIds, statusUpdate.MessageId)
+			continue
+
+		} else if err != nil {
+			return nil, err
+		}
+		messageStatus.MessageId = message.ID
+
+		if messageDeliveryStatusRank(statusUpdate.Status) > messageDeliveryStatusRank(message.DeliveryStatus) {
+			message.DeliveryStatus = statusUpdate.Status
+			message.DeliveryStatusAt = &messageStatus.StatusTimestamp
+			if statusUpdate.Status == enum.FAILED {
+				message.DeliveryError = fmt.Sprintf("%d: %s", statusUpdate.ErrorCode, statusUpdate.ErrorMessage)
+			}
+
+			err = cws.messageRepo.UpdateMessageDeliveryStatus(message)
+			if err != nil {
+				return nil, err
+			}
+
+			notifications = append(notifications, presentation.MessageStatusNotification{
+				MessageId:       message.ID,
+				MetaMessageId:   message.MetaMessageId,
+				InteractionId:   message.InteractionId,
+				Status:          statusUpdate.Status,
+				ErrorCode:       statusUpdate.ErrorCode,
+				ErrorMessage:    statusUpdate.ErrorMessage,
+				StatusTimestamp: statusUpdate.StatusTimestamp,
+			})
+		}
+
+		// recorded last so that a retried delivery still updates the message
+		_, err = cws.messageStatusRepo.CreateMessageStatus(&messageStatus)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missingMessageIds) > 0 {
+		return notifications, fmt.Errorf("%w: %s", enum.MESSAGE_NOT_FOUND, strings.Join(missingMessageIds, ", "))
+	}
+
+	return notifications, nil
+}
+
+// WebsocketMessageStatusService pushes the status changes to the room of their
+// interaction, where the agent handling it is connected.
+func (cws *ChannelWebhookService) WebsocketMessageStatusService(notifications []presentation.MessageStatusNotification) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	for _, notification := range notifications {
+		connectionId := fmt.Sprintf("status-%d", notification.InteractionId)
+		query := fmt.Sprintf("user_id=%s&room_id=%d", connectionId, notification.InteractionId)
+		client, err := NewWebSocketClient(host, channel, connectionId, query)
+		if err != nil {
+			return err
+		}
+
+		send := MessageStatusToSend{
+			Action: "message-status",
+			Status: notification,
+		}
+		err = client.Write(send)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cws *ChannelWebhookService) recordRejectedWebhook(platform string, delivery *presentation.WebhookDelivery, reason error) {
+	logger.Info(fmt.Sprintf("[Webhook Security] Rejected %s delivery from %s: %+v", platform, delivery.RemoteAddr, reason))
+
+	rejectedWebhook := entity.RejectedWebhook{
+		Platform:   platform,
+		PlatformId: delivery.PlatformId,
+		Reason:     reason.Error(),
+		Signature:  delivery.Signature,
+		RemoteAddr: delivery.RemoteAddr,
+		Payload:    string(delivery.Body),
+	}
+
+	_, err := cws.rejectedWebhookRepo.CreateRejectedWebhook(&rejectedWebhook)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Webhook Security] Record rejected delivery: %+v", err))
+	}
+}
+
+// ProcessInboundMessages stores the parsed messages of every channel the same
+// way: the reporter and its ongoing interaction (or the mentioned post) are
+// looked up or created, then the message is stored once per message id.
+func (cws *ChannelWebhookService) ProcessInboundMessages(platform string, inboundMessages []presentation.InboundMessage) (map[string]interface{}, []entity.Message, error) {
+	result := make(map[string]interface{})
+	var resStructList []entity.Message
+	var messageIds []string
+	var interactionIds []uint
+	var reporterIds []uint
+
+	adapter, err := cws.channelAdapterRegistry.GetAdapter(platform)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, inboundMessage := range inboundMessages {
+		// redelivered messages are not pushed nor answered again
+		existingMessage, err := cws.messageRepo.GetMessageByMetaMessageId(inboundMessage.MessageId)
+		if existingMessage != nil && err == nil {
+			messageIds = append(messageIds, existingMessage.MetaMessageId)
+			interactionIds = append(interactionIds, existingMessage.InteractionId)
+			continue
+
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+
+		if inboundMessage.MediaId != "" && adapter.Capabilities().Media {
+			err = adapter.FetchMedia(&inboundMessage)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		var interaction *entity.Interaction
+		var reporter *entity.Reporter
+
+		if inboundMessage.InteractionType == enum.MENTION {
+			interaction, err = cws.getMentionInteraction(platform, &inboundMessage)
+		} else {
+			reporter, err = cws.getInboundReporter(&inboundMessage)
+			if err != nil {
+				return nil, nil, err
+			}
+			reporterIds = append(reporterIds, reporter.ID)
+
+			interaction, err = cws.getReporterInteraction(platform, &inboundMessage, reporter.ID)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		interactionIds = append(interactionIds, interaction.ID)
+
+		newMessage := entity.Message{
+			InteractionId:    interaction.ID,
+			RecipientId:      inboundMessage.PlatformId,
+			MetaMessageId:    inboundMessage.MessageId,
+			Message:          inboundMessage.Message,
+			MessageTimestamp: inboundMessage.MessageTimestamp,
+			SentBy:           enum.REPORTER,
+			IsRead:           false,
+			AttachmentType:   inboundMessage.AttachmentType,
+			AttachmentUrl:    inboundMessage.AttachmentUrl,
+		}
+		if reporter != nil {
+			newMessage.SenderId = inboundMessage.MetaReporterId
+		}
+
+		// a shared location is the geotag of the interaction, set before the
+		// message is stored so a retried delivery does not skip it
+		if newMessage.AttachmentType == enum.LOCATION {
+			latitude, longitude, ok := parseLocation(newMessage)
+			if ok {
+				_, err = cws.interactionRepo.UpdateInteraction(interaction.ID, &entity.Interaction{Latitude: latitude, Longitude: longitude})
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+
+		message, err := cws.messageRepo.CreateMessage(&newMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		messageIds = append(messageIds, message.MetaMessageId)
+		resStructList = append(resStructList, *message)
+	}
+
+	result["messageIds"] = messageIds
+	result["interactionIds"] = interactionIds
+	result["reporterIds"] = reporterIds
+
+	return result, resStructList, nil
+}
+
+func (cws *ChannelWebhookService) getInboundReporter(inboundMessage *presentation.InboundMessage) (*entity.Reporter, error) {
+	existingReporter, err := cws.reporterRepo.GetReporterByMetaReporterId(inboundMessage.MetaReporterId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		newReporter := entity.Reporter{
+			MetaReporterId:   inboundMessage.MetaReporterId,
+			Name:             inboundMessage.ReporterName,
+			PlatformUsername: inboundMessage.ReporterUsername,
+		}
+		return cws.reporterRepo.CreateReporter(&newReporter)
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return existingReporter, nil
+}
+
+func (cws *ChannelWebhookService) getReporterInteraction(platform string, inboundMessage *presentation.InboundMessage, reporterId uint) (*entity.Interaction, error) {
+	ongoingInteraction, err := cws.interactionRepo.GetOngoingInteractionByReporterId(reporterId)
+	if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		ongoingInteraction, err = answerCsatSurvey(cws.csatSurveyRepo, cws.interactionRepo, reporterId, inboundMessage.Message)
+	}
+	if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		newInteraction := entity.Interaction{
+			PlatformId:      inboundMessage.PlatformId,
+			ReporterId:      reporterId,
+			Status:          enum.UNCLAIMED,
+			Platform:        platform,
+			InteractionType: enum.PESAN,
+		}
+
+		interaction, err := cws.interactionRepo.CreateInteraction(&newInteraction)
+		if err != nil {
+			return nil, err
+		}
+
+		return assignNewInteraction(cws.routingService, interaction), nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return resumeWaitingInteraction(cws.interactionRepo, ongoingInteraction), nil
+}
+
+func (cws *ChannelWebhookService) getMentionInteraction(platform string, inboundMessage *presentation.InboundMessage) (*entity.Interaction, error) {
+	ongoingInteraction, err := cws.interactionRepo.GetOngoingInteractionByMentionMediaId(inboundMessage.MentionMediaId)
+	if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		newInteraction := entity.Interaction{
+			PlatformId:      inboundMessage.PlatformId,
+			MentionMediaId:  inboundMessage.MentionMediaId,
+			MentionMediaUrl: inboundMessage.MentionMediaUrl,
+			Status:          enum.UNCLAIMED,
+			Platform:        platform,
+			InteractionType: enum.MENTION,
+		}
+
+		interaction, err := cws.interactionRepo.CreateInteraction(&newInteraction)
+		if err != nil {
+			return nil, err
+		}
+
+		return assignNewInteraction(cws.routingService, interaction), nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return resumeWaitingInteraction(cws.interactionRepo, ongoingInteraction), nil
+}
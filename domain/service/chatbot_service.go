@@ -0,0 +1,414 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type ChatbotService struct {
+	chatbotFlowRepo    repository.IChatbotFlowRepository
+	channelAccountRepo repository.IChannelAccountRepository
+	interactionRepo    repository.IinteractionRepository
+	reporterRepo       repository.IReporterRepository
+	routingService     IRoutingService
+	interactionService IInteractionService
+}
+
+type IChatbotService interface {
+	HandleReporterMessage(entity.Message) error
+	HandoverInteraction(*entity.Interaction) error
+
+	CreateChatbotFlow(*presentation.ChatbotFlowModel) (map[string]interface{}, error)
+	GetChatbotFlowList() (map[string]interface{}, error)
+	GetChatbotFlowById(uint) (map[string]interface{}, error)
+	UpdateChatbotFlow(*presentation.UpdateChatbotFlowModel) (map[string]interface{}, error)
+	DeleteChatbotFlowById(*presentation.DeleteChatbotFlowModel) (map[string]interface{}, error)
+}
+
+func NewChatbotService(chatbotFlowRepo repository.IChatbotFlowRepository, channelAccountRepo repository.IChannelAccountRepository, interactionRepo repository.IinteractionRepository, reporterRepo repository.IReporterRepository, routingService IRoutingService, interactionService IInteractionService) *ChatbotService {
+	chatbotService := ChatbotService{
+		chatbotFlowRepo:    chatbotFlowRepo,
+		channelAccountRepo: channelAccountRepo,
+		interactionRepo:    interactionRepo,
+		reporterRepo:       reporterRepo,
+		routingService:     routingService,
+		interactionService: interactionService,
+	}
+	return &chatbotService
+}
+
+func getChatbotStep(steps []presentation.ChatbotStep, key string) *presentation.ChatbotStep {
+	for i, v := range steps {
+		if v.Key == key {
+			return &steps[i]
+		}
+	}
+
+	return nil
+}
+
+func isHandoverKeyword(handoverKeywords string, text string) bool {
+	text = strings.ToLower(text)
+	for _, v := range strings.Split(handoverKeywords, ",") {
+		keyword := strings.ToLower(strings.TrimSpace(v))
+		if keyword != "" && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLocation reads a WhatsApp location message or a "latitude,longitude"
+// text reply.
+func parseLocation(message entity.Message) (string, string, bool) {
+	var location presentation.WhatsappLocation
+
+	if message.AttachmentType == enum.LOCATION {
+		err := json.Unmarshal([]byte(message.Message), &location)
+		if err != nil {
+			return "", "", false
+		}
+
+	} else {
+		coordinates := strings.Split(message.Message, ",")
+		if len(coordinates) != 2 {
+			return "", "", false
+		}
+
+		var err error
+		location.Latitude, err = strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+		if err != nil {
+			return "", "", false
+		}
+		location.Longitude, err = strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+		if err != nil {
+			return "", "", false
+		}
+	}
+
+	return strconv.FormatFloat(location.Latitude, 'f', -1, 64), strconv.FormatFloat(location.Longitude, 'f', -1, 64), true
+}
+
+// collectField stores the reply into the reporter or interaction record. It
+// returns false when the reply does not fit the field so the step is asked
+// again.
+func (cs *ChatbotService) collectField(interaction *entity.Interaction, field string, message entity.Message) (bool, error) {
+	var err error
+	text := strings.TrimSpace(message.Message)
+
+	switch field {
+	case enum.NAME:
+		if text == "" || message.AttachmentType != "" {
+			return false, nil
+		}
+		_, err = cs.reporterRepo.UpdateReporter(interaction.ReporterId, &entity.Reporter{Name: text})
+
+	case enum.ADDRESS:
+		if text == "" || message.AttachmentType != "" {
+			return false, nil
+		}
+		_, err = cs.reporterRepo.UpdateReporter(interaction.ReporterId, &entity.Reporter{Address: text})
+
+	case enum.LOCATION:
+		latitude, longitude, ok := parseLocation(message)
+		if !ok {
+			return false, nil
+		}
+		_, err = cs.interactionRepo.UpdateInteraction(interaction.ID, &entity.Interaction{Latitude: latitude, Longitude: longitude})
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// nextChatbotStep returns the key of the step that follows the reply, and
+// false when the reply matches none of the options of the step.
+func nextChatbotStep(step *presentation.ChatbotStep, text string) (string, bool) {
+	if len(step.Options) == 0 {
+		return step.Next, true
+	}
+
+	for _, v := range step.Options {
+		if strings.EqualFold(strings.TrimSpace(text), v.Keyword) {
+			return v.Next, true
+		}
+	}
+
+	return "", false
+}
+
+// handover ends the bot session and puts the interaction in the unclaimed
+// queue, where the routing engine picks it up like a new interaction.
+func (cs *ChatbotService) handover(interaction *entity.Interaction, session *entity.ChatbotSession, chatbotFlow *entity.ChatbotFlow, channelAccount *entity.ChannelAccount) error {
+	if chatbotFlow.HandoverMessage != "" {
+		err := sendSystemMessage(cs.interactionService, interaction, channelAccount, chatbotFlow.HandoverMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	session.IsCompleted = true
+	err := cs.chatbotFlowRepo.UpdateChatbotSession(session)
+	if err != nil {
+		return err
+	}
+
+	newInteraction := entity.Interaction{
+		Status: enum.UNCLAIMED,
+	}
+
+	unclaimedInteraction, err := cs.interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("[Chatbot] Interaction %d handed over to the unclaimed queue", interaction.ID))
+
+	assignNewInteraction(cs.routingService, unclaimedInteraction)
+
+	return nil
+}
+
+// HandoverInteraction ends the chatbot session of a BOT interaction early and
+// hands it over to a human, e.g. when the reporter stopped answering the bot.
+func (cs *ChatbotService) HandoverInteraction(interaction *entity.Interaction) error {
+	if interaction.Status != enum.BOT {
+		return nil
+	}
+
+	session, err := cs.chatbotFlowRepo.GetChatbotSessionByInteractionId(interaction.ID)
+	if err != nil {
+		return err
+	}
+
+	chatbotFlow, err := cs.chatbotFlowRepo.GetChatbotFlowById(session.ChatbotFlowId)
+	if err != nil {
+		return err
+	}
+
+	channelAccount, err := getInteractionChannelAccount(cs.channelAccountRepo, interaction)
+	if err != nil {
+		return err
+	}
+	if channelAccount == nil {
+		channelAccount = &entity.ChannelAccount{}
+	}
+
+	return cs.handover(interaction, session, chatbotFlow, channelAccount)
+}
+
+// HandleReporterMessage moves the chatbot session of a BOT interaction one
+// step forward. The first message of the reporter starts the flow, each later
+// reply is collected into the current step's field before the next step is
+// asked. A handover keyword or the end of the flow hands the interaction over
+// to a human.
+func (cs *ChatbotService) HandleReporterMessage(message entity.Message) error {
+	var steps []presentation.ChatbotStep
+
+	if message.SentBy != enum.REPORTER {
+		return nil
+	}
+
+	interaction, err := cs.interactionRepo.GetInteractionById(message.InteractionId)
+	if err != nil {
+		return err
+	}
+	if interaction.Status != enum.BOT {
+		return nil
+	}
+
+	session, err := cs.chatbotFlowRepo.GetChatbotSessionByInteractionId(interaction.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+
+	} else if err != nil {
+		return err
+	}
+
+	if session.IsCompleted || message.ID <= session.LastMessageId {
+		return nil
+	}
+	session.LastMessageId = message.ID
+
+	chatbotFlow, err := cs.chatbotFlowRepo.GetChatbotFlowById(session.ChatbotFlowId)
+	if err != nil {
+		return err
+	}
+
+	channelAccount, err := getInteractionChannelAccount(cs.channelAccountRepo, interaction)
+	if err != nil {
+		return err
+	}
+	if channelAccount == nil {
+		channelAccount = &entity.ChannelAccount{}
+	}
+
+	err = json.Unmarshal([]byte(chatbotFlow.Steps), &steps)
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 || isHandoverKeyword(chatbotFlow.HandoverKeywords, message.Message) {
+		return cs.handover(interaction, session, chatbotFlow, channelAccount)
+	}
+
+	nextStepKey := steps[0].Key
+	currentStep := getChatbotStep(steps, session.CurrentStep)
+	if currentStep != nil {
+		isCollected, err := cs.collectField(interaction, currentStep.Field, message)
+		if err != nil {
+			return err
+		}
+
+		var isMatched bool
+		nextStepKey, isMatched = nextChatbotStep(currentStep, message.Message)
+		if !isCollected || !isMatched {
+			nextStepKey = currentStep.Key
+		}
+	}
+
+	nextStep := getChatbotStep(steps, nextStepKey)
+	if nextStep == nil {
+		return cs.handover(interaction, session, chatbotFlow, channelAccount)
+	}
+
+	err = sendSystemMessage(cs.interactionService, interaction, channelAccount, nextStep.Message)
+	if err != nil {
+		return err
+	}
+
+	session.CurrentStep = nextStep.Key
+	return cs.chatbotFlowRepo.UpdateChatbotSession(session)
+}
+
+func (cs *ChatbotService) CreateChatbotFlow(cfm *presentation.ChatbotFlowModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	steps, err := json.Marshal(cfm.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	newChatbotFlow := entity.ChatbotFlow{
+		Name:             cfm.Name,
+		ChannelAccountId: cfm.ChannelAccountId,
+		Platform:         cfm.Platform,
+		Steps:            string(steps),
+		HandoverKeywords: strings.Join(cfm.HandoverKeywords, ","),
+		HandoverMessage:  cfm.HandoverMessage,
+		IsActive:         cfm.IsActive,
+	}
+
+	chatbotFlow, err := cs.chatbotFlowRepo.CreateChatbotFlow(&newChatbotFlow)
+	if err != nil {
+		return nil, err
+	}
+
+	result["chatbot_flow"] = chatbotFlow
+
+	return result, nil
+}
+
+func (cs *ChatbotService) GetChatbotFlowList() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	chatbotFlowList, err := cs.chatbotFlowRepo.GetChatbotFlowList()
+	if (chatbotFlowList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["chatbot_flow_list"] = chatbotFlowList
+
+	return result, nil
+}
+
+func (cs *ChatbotService) GetChatbotFlowById(chatbotFlowId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	chatbotFlow, err := cs.chatbotFlowRepo.GetChatbotFlowById(chatbotFlowId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["chatbot_flow"] = chatbotFlow
+
+	return result, nil
+}
+
+func (cs *ChatbotService) UpdateChatbotFlow(ucfm *presentation.UpdateChatbotFlowModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	currentChatbotFlow, err := cs.chatbotFlowRepo.GetChatbotFlowById(ucfm.ChatbotFlowId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	newChatbotFlow := entity.ChatbotFlow{
+		Name:             ucfm.Name,
+		Platform:         ucfm.Platform,
+		HandoverKeywords: strings.Join(ucfm.HandoverKeywords, ","),
+		HandoverMessage:  ucfm.HandoverMessage,
+		IsActive:         currentChatbotFlow.IsActive,
+	}
+
+	if len(ucfm.Steps) > 0 {
+		steps, err := json.Marshal(ucfm.Steps)
+		if err != nil {
+			return nil, err
+		}
+		newChatbotFlow.Steps = string(steps)
+	}
+
+	if ucfm.IsActive != nil {
+		newChatbotFlow.IsActive = *ucfm.IsActive
+	}
+
+	chatbotFlow, err := cs.chatbotFlowRepo.UpdateChatbotFlow(ucfm.ChatbotFlowId, &newChatbotFlow)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["chatbot_flow"] = chatbotFlow
+
+	return result, nil
+}
+
+func (cs *ChatbotService) DeleteChatbotFlowById(dcfm *presentation.DeleteChatbotFlowModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	err := cs.chatbotFlowRepo.DeleteChatbotFlow(dcfm.ChatbotFlowId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const defaultCsatPrompt = "Thank you for contacting us. How satisfied are you with our service? Reply with a number from 1 (very dissatisfied) to 5 (very satisfied)."
+
+type CsatService struct {
+	csatSurveyRepo     repository.ICsatSurveyRepository
+	channelAccountRepo repository.IChannelAccountRepository
+	interactionRepo    repository.IinteractionRepository
+	interactionService IInteractionService
+}
+
+type ICsatService interface {
+	SendCsatSurvey(*entity.Interaction) error
+	RecordCsatReply(uint, string) (*entity.Interaction, error)
+	GetCsatSurveyList(map[string]interface{}) (map[string]interface{}, error)
+}
+
+func NewCsatService(csatSurveyRepo repository.ICsatSurveyRepository, channelAccountRepo repository.IChannelAccountRepository, interactionRepo repository.IinteractionRepository, interactionService IInteractionService) *CsatService {
+	csatService := CsatService{
+		csatSurveyRepo:     csatSurveyRepo,
+		channelAccountRepo: channelAccountRepo,
+		interactionRepo:    interactionRepo,
+		interactionService: interactionService,
+	}
+	return &csatService
+}
+
+// getCsatReplyWindow reads Csat.ReplyWindowHours, the time a reporter has to
+// answer the survey, defaulting to one day.
+func getCsatReplyWindow() time.Duration {
+	hours := viper.GetInt64("Csat.ReplyWindowHours")
+	if hours <= 0 {
+		hours = 24
+	}
+
+	return time.Duration(hours) * time.Hour
+}
+
+// answerCsatSurvey records the reply of a reporter without an ongoing
+// interaction when it answers a pending survey, and returns the surveyed
+// interaction so the reply is stored there instead of opening a new one.
+// Returns nil when the message is not a survey answer.
+func answerCsatSurvey(csatSurveyRepo repository.ICsatSurveyRepository, interactionRepo repository.IinteractionRepository, reporterId uint, reply string) (*entity.Interaction, error) {
+	csatSurvey, err := csatSurveyRepo.GetPendingCsatSurveyByReporterId(reporterId, time.Now().Add(-getCsatReplyWindow()))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	score, ok := presentation.ParseCsatScore(reply)
+	if !ok {
+		return nil, nil
+	}
+
+	now := time.Now()
+	csatSurvey.Status = enum.ANSWERED
+	csatSurvey.Score = score
+	csatSurvey.Reply = reply
+	csatSurvey.AnsweredAt = &now
+
+	err = csatSurveyRepo.AnswerCsatSurvey(csatSurvey)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("[CSAT] Interaction %d rated %d", csatSurvey.InteractionId, score))
+
+	return interactionRepo.GetInteractionById(csatSurvey.InteractionId)
+}
+
+// SendCsatSurvey asks the reporter of a closed interaction to rate it on the
+// channel the interaction came from. Mentions are not surveyed.
+func (cs *CsatService) SendCsatSurvey(interaction *entity.Interaction) error {
+	if interaction.Status != enum.CLOSED || interaction.InteractionType == enum.MENTION {
+		return nil
+	}
+
+	channelAccount, err := getInteractionChannelAccount(cs.channelAccountRepo, interaction)
+	if err != nil {
+		return err
+	}
+	if channelAccount == nil {
+		channelAccount = &entity.ChannelAccount{}
+	}
+
+	prompt := viper.GetString("Csat.Prompt")
+	if prompt == "" {
+		prompt = defaultCsatPrompt
+	}
+
+	err = sendSystemMessage(cs.interactionService, interaction, channelAccount, prompt)
+	if err != nil {
+		return err
+	}
+
+	newCsatSurvey := entity.CsatSurvey{
+		InteractionId: interaction.ID,
+		AgentId:       interaction.AgentId,
+		ReporterId:    interaction.ReporterId,
+		Platform:      interaction.Platform,
+		Status:        enum.SENT,
+	}
+
+	_, err = cs.csatSurveyRepo.CreateCsatSurvey(&newCsatSurvey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordCsatReply is answerCsatSurvey for channels that store the reply
+// themselves, e.g. live chat.
+func (cs *CsatService) RecordCsatReply(reporterId uint, reply string) (*entity.Interaction, error) {
+	return answerCsatSurvey(cs.csatSurveyRepo, cs.interactionRepo, reporterId, reply)
+}
+
+func (cs *CsatService) GetCsatSurveyList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	csatSurveyList, err := cs.csatSurveyRepo.GetCsatSurveyList(filters)
+	if (csatSurveyList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["csat_survey_list"] = csatSurveyList
+
+	return result, nil
+}
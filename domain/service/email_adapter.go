@@ -0,0 +1,56 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"fmt"
+)
+
+// EmailAdapter only sends, inbound email comes from the Gmail push
+// notifications handled by EmailService.ProcessWebhook.
+type EmailAdapter struct {
+	emailService IEmailService
+}
+
+func NewEmailAdapter(emailService IEmailService) *EmailAdapter {
+	emailAdapter := EmailAdapter{
+		emailService: emailService,
+	}
+	return &emailAdapter
+}
+
+func (ea *EmailAdapter) Platform() string {
+	return enum.EMAIL
+}
+
+func (ea *EmailAdapter) Capabilities() ChannelCapabilities {
+	return ChannelCapabilities{
+		Attachments: true,
+	}
+}
+
+func (ea *EmailAdapter) VerifyWebhook(delivery *presentation.WebhookDelivery) error {
+	return enum.INVALID_PLATFORM
+}
+
+func (ea *EmailAdapter) ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error) {
+	return nil, enum.INVALID_PLATFORM
+}
+
+func (ea *EmailAdapter) FetchMedia(inboundMessage *presentation.InboundMessage) error {
+	return nil
+}
+
+func (ea *EmailAdapter) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	messageId, message, err := ea.emailService.SendEmail(msmr.InteractionId, outboundMessageText(msmr), outboundSentBy(msmr), msmr.Attachment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[EmailAdapter][SendMessage] Error when calling SendEmail, trace: %+v", err)
+	}
+
+	res := map[string]interface{}{
+		"message_id": messageId,
+	}
+
+	return res, message, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/utils"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// emailPipeline turns received emails into threads, reporters, interactions
+// and messages. It is shared by the email services of every mail provider, so
+// an email is threaded the same way whether it came from Gmail or IMAP.
+type emailPipeline struct {
+	interactionRepo repository.IinteractionRepository
+	messageRepo     repository.IMessageRepository
+	reporterRepo    repository.IReporterRepository
+	threadRepo      repository.ThreadRepository
+	routingService  IRoutingService
+	csatSurveyRepo  repository.ICsatSurveyRepository
+}
+
+// ingestEmail adds the email to the ongoing interaction of its thread, or
+// opens a new interaction for its sender. The message is not stored twice
+// when the same email is read again.
+func (pipeline *emailPipeline) ingestEmail(inboundEmail presentation.InboundEmail) error {
+	thread := entity.Thread{}
+	interaction := &entity.Interaction{}
+
+	ongoingInteraction, err := pipeline.interactionRepo.GetOngoingInteractionByConversationId(inboundEmail.ThreadId)
+	if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		ongoingInteraction, err = pipeline.answerEmailCsatSurvey(inboundEmail.From, inboundEmail.Message)
+	}
+	if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		existingThread, err := pipeline.threadRepo.GetThreadByID(inboundEmail.ThreadId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			newThread, err := pipeline.threadRepo.InsertThread(entity.Thread{
+				ID:        inboundEmail.ThreadId,
+				Subject:   inboundEmail.Subject,
+				EmailDate: inboundEmail.Date,
+				From:      inboundEmail.From,
+			})
+			if err != nil {
+				return fmt.Errorf("[EmailService][IngestEmail] error when calling InsertThread, error: %+v", err)
+			}
+
+			thread = newThread
+
+		} else if err != nil {
+			return fmt.Errorf("[EmailService][IngestEmail] error when calling GetThreadByID, error: %+v", err)
+
+		} else {
+			thread = existingThread
+		}
+
+		var reporterId uint
+		var name string
+		var email string
+
+		name, email = utils.ParseFromHeader(inboundEmail.From)
+		if email == "" {
+			email = inboundEmail.From
+		}
+
+		existingReporter, err := pipeline.reporterRepo.GetReporterByEmail(email)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			newReporter := entity.Reporter{
+				Name:  name,
+				Email: email,
+			}
+			reporter, err := pipeline.reporterRepo.CreateReporter(&newReporter)
+			if err != nil {
+				return fmt.Errorf("[EmailService][IngestEmail] error when calling InsertReporter, error: %+v", err)
+			}
+
+			reporterId = reporter.ID
+
+		} else if err != nil {
+			return fmt.Errorf("[EmailService][IngestEmail] error when calling GetReporterByEmail, error: %+v", err)
+
+		} else {
+			reporterId = existingReporter.ID
+		}
+
+		interaction = &entity.Interaction{
+			ReporterId:      reporterId,
+			Platform:        enum.EMAIL,
+			InteractionType: enum.PESAN,
+			Status:          enum.UNCLAIMED,
+			ConversationId:  thread.ID,
+			PlatformId:      inboundEmail.Mailbox,
+		}
+
+		interaction, err = pipeline.interactionRepo.CreateInteraction(interaction)
+		if err != nil {
+			return fmt.Errorf("[EmailService][IngestEmail] error when calling CreateInteraction, error: %+v", err)
+		}
+
+		interaction = assignNewInteraction(pipeline.routingService, interaction)
+
+	} else if err != nil {
+		return fmt.Errorf("[EmailService][IngestEmail] error when calling GetOngoingInteraction, error: %+v", err)
+
+	} else {
+		interaction = resumeWaitingInteraction(pipeline.interactionRepo, ongoingInteraction)
+	}
+
+	m, err := pipeline.messageRepo.GetMessageByMetaMessageId(inboundEmail.MessageId)
+	if m == nil { // No existing message so create new
+		newMessage := entity.Message{
+			InteractionId: interaction.ID,
+			Message:       inboundEmail.Message,
+			HtmlMessage:   inboundEmail.HtmlMessage,
+			SentBy:        enum.REPORTER,
+			RecipientId:   inboundEmail.Mailbox,
+			MetaMessageId: inboundEmail.MessageId,
+		}
+		if len(inboundEmail.Attachments) > 0 {
+			newMessage.AttachmentType = attachmentTypeByContentType(inboundEmail.Attachments[0].ContentType)
+			newMessage.AttachmentUrl = inboundEmail.Attachments[0].Url
+		}
+
+		message, err := pipeline.messageRepo.CreateMessage(&newMessage)
+		if err != nil {
+			return fmt.Errorf("[EmailService][IngestEmail] error when calling CreateMessage, error: %+v", err)
+		}
+
+		var messageAttachments []entity.MessageAttachment
+		for _, attachment := range inboundEmail.Attachments {
+			messageAttachments = append(messageAttachments, entity.MessageAttachment{
+				MessageId:     message.ID,
+				InteractionId: message.InteractionId,
+				Name:          attachment.Name,
+				ContentType:   attachment.ContentType,
+				ContentId:     attachment.ContentId,
+				Size:          attachment.Size,
+				Url:           attachment.Url,
+			})
+		}
+
+		err = pipeline.messageRepo.CreateMessageAttachments(messageAttachments)
+		if err != nil {
+			return fmt.Errorf("[EmailService][IngestEmail] error when calling CreateMessageAttachments, error: %+v", err)
+		}
+	}
+
+	return nil
+}
+
+// answerEmailCsatSurvey looks up the sender of an email that does not belong to
+// an ongoing thread and records it as a survey answer when it is one.
+func (pipeline *emailPipeline) answerEmailCsatSurvey(from string, emailMessage string) (*entity.Interaction, error) {
+	_, email := utils.ParseFromHeader(from)
+	if email == "" {
+		email = from
+	}
+
+	reporter, err := pipeline.reporterRepo.GetReporterByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return answerCsatSurvey(pipeline.csatSurveyRepo, pipeline.interactionRepo, reporter.ID, emailMessage)
+}
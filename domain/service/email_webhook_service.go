@@ -5,6 +5,7 @@ import (
 	"Omnichannel-CRM/domain/repository"
 	"Omnichannel-CRM/package/config"
 	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
 	"Omnichannel-CRM/package/utils"
 	"bytes"
 	"context"
@@ -14,9 +15,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -24,35 +28,112 @@ import (
 	"gorm.io/gorm"
 )
 
+// EmailService reads and sends the emails of Gmail mailboxes through the Gmail
+// API. The mailbox of the OAuth config is used for the addresses no channel
+// account has a refresh token for.
 type EmailService struct {
-	interactionRepo repository.IinteractionRepository
-	messageRepo     repository.IMessageRepository
-	reporterRepo    repository.IReporterRepository
-	emailRepo       repository.IEmailRepository
-	threadRepo      repository.ThreadRepository
+	emailPipeline
+	emailRepo          repository.IEmailRepository
+	channelAccountRepo repository.IChannelAccountRepository
+	attachmentStore    AttachmentStore
 }
 
 type IEmailService interface {
 	ProcessWebhook(rawMessage string, prevHistoryId int) (historyId uint64, err error)
-	SendEmail(interactionId uint, message string) (messageId string, res *entity.Message, err error)
+	SendEmail(interactionId uint, message string, sentBy string, attachment *presentation.OutboundAttachment) (messageId string, res *entity.Message, err error)
+	StartEmailThread(interactionId uint, subject string, message string) (messageId string, res *entity.Message, err error)
 }
 
 func init() {
 	config.GetConfig()
 }
 
-func NewEmailService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, emailRepo repository.IEmailRepository, threadRepo repository.ThreadRepository) *EmailService {
+func NewEmailService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, emailRepo repository.IEmailRepository, threadRepo repository.ThreadRepository, routingService IRoutingService, csatSurveyRepo repository.ICsatSurveyRepository, channelAccountRepo repository.IChannelAccountRepository, attachmentStore AttachmentStore) *EmailService {
 	emailService := EmailService{
-		interactionRepo: interactionRepo,
-		messageRepo:     messageRepo,
-		// userRepo:        userRepo,
-		reporterRepo: reporterRepo,
-		emailRepo:    emailRepo,
-		threadRepo:   threadRepo,
+		emailPipeline: emailPipeline{
+			interactionRepo: interactionRepo,
+			messageRepo:     messageRepo,
+			reporterRepo:    reporterRepo,
+			threadRepo:      threadRepo,
+			routingService:  routingService,
+			csatSurveyRepo:  csatSurveyRepo,
+		},
+		emailRepo:          emailRepo,
+		channelAccountRepo: channelAccountRepo,
+		attachmentStore:    attachmentStore,
 	}
 	return &emailService
 }
 
+// mailboxEmailRepo returns the repository of the mailbox of the email address.
+func (service *EmailService) mailboxEmailRepo(emailAddress string) (repository.IEmailRepository, error) {
+	if emailAddress == "" {
+		return service.emailRepo, nil
+	}
+
+	channelAccount, err := service.channelAccountRepo.GetChannelAccountByPlatformId(emailAddress)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service.emailRepo, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if channelAccount.GmailRefreshToken == "" {
+		return service.emailRepo, nil
+	}
+
+	return service.emailRepo.ForMailbox(channelAccount.GmailRefreshToken), nil
+}
+
+// gmailWatchTopic is the Pub/Sub topic the mailboxes push their changes to.
+func gmailWatchTopic() string {
+	topicName := viper.GetString("OAuth.Topic_Name")
+	if topicName == "" {
+		topicName = "projects/email-api-406816/topics/gmail-webhook"
+	}
+	return topicName
+}
+
+// GetMailboxAddresses returns the address of the mailbox of the OAuth config,
+// when it has a refresh token, and of every channel account mailbox.
+func (service *EmailService) GetMailboxAddresses() ([]string, error) {
+	var emailAddresses []string
+
+	if viper.GetString("OAuth.Refresh_Token") != "" {
+		profile, err := service.emailRepo.GetProfile()
+		if err != nil {
+			return nil, fmt.Errorf("[EmailService][GetMailboxAddresses] error when calling GetProfile, error: %+v", err)
+		}
+		emailAddresses = append(emailAddresses, profile.EmailAddress)
+	}
+
+	channelAccountList, err := service.channelAccountRepo.GetGmailChannelAccountList()
+	if err != nil {
+		return nil, fmt.Errorf("[EmailService][GetMailboxAddresses] error when calling GetGmailChannelAccountList, error: %+v", err)
+	}
+
+	for _, channelAccount := range channelAccountList {
+		if len(emailAddresses) > 0 && emailAddresses[0] == channelAccount.EmailAddress {
+			continue
+		}
+		emailAddresses = append(emailAddresses, channelAccount.EmailAddress)
+	}
+
+	return emailAddresses, nil
+}
+
+// WatchMailbox subscribes the mailbox to the Pub/Sub topic of the Gmail push
+// notifications, the watch lasts seven days.
+func (service *EmailService) WatchMailbox(emailAddress string) (*gmail.WatchResponse, error) {
+	emailRepo, err := service.mailboxEmailRepo(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return emailRepo.Watch(gmailWatchTopic())
+}
+
 type ResponseData struct {
 	AccessToken string `json:"access_token"`
 	ExpiresIn   int    `json:"expires_in"`
@@ -130,9 +211,11 @@ func NewGmailService() *gmail.Service {
 	return gmailService
 }
 
+// FindHeaders returns the value of the first header named key, header names
+// are not case sensitive.
 func FindHeaders(headers []*gmail.MessagePartHeader, key string) (value string) {
 	for _, elem := range headers {
-		if elem.Name == key {
+		if strings.EqualFold(elem.Name, key) {
 			return elem.Value
 		}
 	}
@@ -140,20 +223,11 @@ func FindHeaders(headers []*gmail.MessagePartHeader, key string) (value string)
 	return ""
 }
 
-func FindEmailMessage(part []*gmail.MessagePart) (textMessage string) {
-	for _, elem := range part {
-		if elem.MimeType == "text/plain" {
-			textMessage, err := utils.DecodeBase64(elem.Body.Data)
-			if err != nil {
-				fmt.Println(fmt.Sprintf("Error when decoding: %+v", elem.Body.Data))
-				return ""
-			}
-
-			return textMessage
-		}
-	}
-
-	return ""
+// emailHeaderText keeps a header value given by a user on a single line. A CR
+// or LF would end the header and let the rest of the value add headers, or
+// start the body, of its own.
+func emailHeaderText(value string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value))
 }
 
 func (service *EmailService) ProcessWebhook(rawMessage string, prevHistoryId int) (historyId uint64, err error) {
@@ -164,12 +238,7 @@ func (service *EmailService) ProcessWebhook(rawMessage string, prevHistoryId int
 
 	fmt.Println(fmt.Sprintf("===== json string %+v", jsonString))
 
-	type WebhookData struct {
-		EmailAddress string `json:"emailAddress"`
-		HistoryId    uint64 `json:"historyId"`
-	}
-
-	var data WebhookData
+	var data presentation.GmailNotificationData
 
 	err = json.Unmarshal([]byte(jsonString), &data)
 	if err != nil {
@@ -178,123 +247,34 @@ func (service *EmailService) ProcessWebhook(rawMessage string, prevHistoryId int
 
 	fmt.Println(fmt.Sprintf("===== data %+v", data))
 
-	// historyList, err := service.emailRepo.GetHistoryList(data.HistoryId)
-	historyList, err := service.emailRepo.GetHistoryList(uint64(prevHistoryId))
+	emailRepo, err := service.mailboxEmailRepo(data.EmailAddress)
 	if err != nil {
-		return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetHistoryList, history id: %+v, error: %+v", data.HistoryId, err)
+		return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling mailboxEmailRepo, email address: %+v, error: %+v", data.EmailAddress, err)
+	}
+
+	// historyList, err := service.emailRepo.GetHistoryList(data.HistoryId)
+	historyList, err := emailRepo.GetHistoryList(uint64(prevHistoryId))
+	if errors.Is(err, enum.GMAIL_HISTORY_EXPIRED) {
+		return historyId, err
+
+	} else if err != nil {
+		return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetHistoryList, history id: %+v, error: %+v", prevHistoryId, err)
 	}
 
 	fmt.Println(fmt.Sprintf("===== history list %+v", historyList))
 
-	profile, err := service.emailRepo.GetProfile()
+	profile, err := emailRepo.GetProfile()
 	if err != nil {
 		return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetProfile, error: %+v", err)
 	}
 	fmt.Println(fmt.Sprintf("===== profile %+v", profile))
 
 	for _, history := range historyList.History {
-		if len(history.Messages) > 0 {
-			message, err := service.emailRepo.GetMessageById(history.Messages[0].Id)
+		for _, historyMessage := range history.Messages {
+			err = service.ingestGmailMessage(emailRepo, profile.EmailAddress, historyMessage.Id)
 			if err != nil {
-				return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetMessageById, message id: %+v, error: %+v", history.Messages[9].Id, err)
-			}
-
-			threadId := message.ThreadId
-			subject := FindHeaders(message.Payload.Headers, "Subject")
-			from := FindHeaders(message.Payload.Headers, "From")
-			date := FindHeaders(message.Payload.Headers, "Date")
-			emailMessage := FindEmailMessage(message.Payload.Parts)
-
-			thread := entity.Thread{}
-			interaction := &entity.Interaction{}
-
-			ongoingInteraction, err := service.interactionRepo.GetOngoingInteractionByConversationId(threadId)
-			if (ongoingInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
-				existingThread, err := service.threadRepo.GetThreadByID(threadId)
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					newThread, err := service.threadRepo.InsertThread(entity.Thread{
-						ID:        threadId,
-						Subject:   subject,
-						EmailDate: date,
-						From:      from,
-					})
-					if err != nil {
-						return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling InsertThread, error: %+v", err)
-					}
-
-					thread = newThread
-
-				} else if err != nil {
-					return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetThreadByID, error: %+v", err)
-
-				} else {
-					thread = existingThread
-				}
-
-				var reporterId uint
-				var name string
-				var email string
-
-				name, email = utils.ParseFromHeader(from)
-				if email == "" {
-					email = from
-				}
-
-				existingReporter, err := service.reporterRepo.GetReporterByEmail(email)
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					newReporter := entity.Reporter{
-						Name:  name,
-						Email: email,
-					}
-					reporter, err := service.reporterRepo.CreateReporter(&newReporter)
-					if err != nil {
-						return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling InsertReporter, error: %+v", err)
-					}
-
-					reporterId = reporter.ID
-
-				} else if err != nil {
-					return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetReporterByEmail, error: %+v", err)
-
-				} else {
-					reporterId = existingReporter.ID
-				}
-
-				interaction = &entity.Interaction{
-					ReporterId:      reporterId,
-					Platform:        enum.EMAIL,
-					InteractionType: enum.PESAN,
-					Status:          enum.UNCLAIMED,
-					ConversationId:  thread.ID,
-					PlatformId:      profile.EmailAddress,
-				}
-
-				interaction, err = service.interactionRepo.CreateInteraction(interaction)
-				if err != nil {
-					return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling CreateInteraction, error: %+v", err)
-				}
-
-			} else if err != nil {
-				return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling GetOngoingInteraction, error: %+v", err)
-
-			} else {
-				interaction = ongoingInteraction
-			}
-
-			m, err := service.messageRepo.GetMessageByMetaMessageId(history.Messages[0].Id)
-			if m == nil { // No existing message so create new
-				_, err = service.messageRepo.CreateMessage(&entity.Message{
-					InteractionId: interaction.ID,
-					Message:       emailMessage,
-					SentBy:        enum.REPORTER,
-					RecipientId:   profile.EmailAddress,
-					MetaMessageId: history.Messages[0].Id,
-				})
-				if err != nil {
-					return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling CreateMessage, error: %+v", err)
-				}
+				return historyId, fmt.Errorf("[EmailService][ProcessWebhook] error when calling ingestGmailMessage, message id: %+v, error: %+v", historyMessage.Id, err)
 			}
-
 		}
 	}
 
@@ -302,17 +282,90 @@ func (service *EmailService) ProcessWebhook(rawMessage string, prevHistoryId int
 	return historyList.HistoryId, nil
 }
 
-func (service *EmailService) SendEmail(interactionId uint, message string) (messageId string, res *entity.Message, err error) {
-	profile, err := service.emailRepo.GetProfile()
+// ingestGmailMessage reads the Gmail message and hands it to the email
+// pipeline. A message that is already stored, which happens when it shows up
+// in several history records or is read again by a backfill, is skipped.
+func (service *EmailService) ingestGmailMessage(emailRepo repository.IEmailRepository, mailbox string, gmailMessageId string) error {
+	storedMessage, err := service.messageRepo.GetMessageByMetaMessageId(gmailMessageId)
+	if storedMessage != nil {
+		return nil
+
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	message, err := emailRepo.GetMessageById(gmailMessageId)
 	if err != nil {
-		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling GetProfile, error: %+v", err)
+		return err
+	}
+
+	inboundEmail := presentation.InboundEmail{
+		MessageId: gmailMessageId,
+		ThreadId:  message.ThreadId,
+		Subject:   FindHeaders(message.Payload.Headers, "Subject"),
+		From:      FindHeaders(message.Payload.Headers, "From"),
+		Date:      FindHeaders(message.Payload.Headers, "Date"),
+		Mailbox:   mailbox,
+	}
+
+	err = service.readGmailEmail(emailRepo, message, &inboundEmail)
+	if err != nil {
+		return err
+	}
+
+	return service.ingestEmail(inboundEmail)
+}
+
+// BackfillMailbox reads the inbox emails of the mailbox received between after
+// and before, oldest first, to recover from a history id Gmail no longer
+// keeps. It returns the history id of the mailbox taken before the emails were
+// listed, so the history picks up everything received during the backfill.
+func (service *EmailService) BackfillMailbox(emailAddress string, after time.Time, before time.Time) (historyId uint64, err error) {
+	emailRepo, err := service.mailboxEmailRepo(emailAddress)
+	if err != nil {
+		return historyId, fmt.Errorf("[EmailService][BackfillMailbox] error when calling mailboxEmailRepo, email address: %+v, error: %+v", emailAddress, err)
+	}
+
+	profile, err := emailRepo.GetProfile()
+	if err != nil {
+		return historyId, fmt.Errorf("[EmailService][BackfillMailbox] error when calling GetProfile, error: %+v", err)
+	}
+
+	messageIds, err := emailRepo.ListMessageIds(fmt.Sprintf("in:inbox after:%d before:%d", after.Unix(), before.Unix()))
+	if err != nil {
+		return historyId, fmt.Errorf("[EmailService][BackfillMailbox] error when calling ListMessageIds, error: %+v", err)
 	}
 
+	// Gmail lists the newest emails first
+	for i := len(messageIds) - 1; i >= 0; i-- {
+		err = service.ingestGmailMessage(emailRepo, profile.EmailAddress, messageIds[i])
+		if err != nil {
+			return historyId, fmt.Errorf("[EmailService][BackfillMailbox] error when calling ingestGmailMessage, message id: %+v, error: %+v", messageIds[i], err)
+		}
+	}
+
+	return profile.HistoryId, nil
+}
+
+// SendEmail replies in the thread of the interaction, the attachment is added
+// as a MIME part when there is one. The message is stored as sent by sentBy,
+// SYSTEM for the automatic ones such as the CSAT prompt.
+func (service *EmailService) SendEmail(interactionId uint, message string, sentBy string, attachment *presentation.OutboundAttachment) (messageId string, res *entity.Message, err error) {
 	interaction, err := service.interactionRepo.GetInteractionById(interactionId)
 	if err != nil {
 		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling GetInteractionById, error: %+v", err)
 	}
 
+	emailRepo, err := service.mailboxEmailRepo(interaction.PlatformId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling mailboxEmailRepo, error: %+v", err)
+	}
+
+	profile, err := emailRepo.GetProfile()
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling GetProfile, error: %+v", err)
+	}
+
 	thread, err := service.threadRepo.GetThreadByID(interaction.ConversationId)
 	if err != nil {
 		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling GetThreadByID, error: %+v", err)
@@ -334,27 +387,135 @@ func (service *EmailService) SendEmail(interactionId uint, message string) (mess
 		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling GetLatestMessageofInteraction, error: %+v", err)
 	}
 
-	formattedMessage := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nReferences: %s\r\nIn-Reply-To: %s\r\n\r\n%s", profile.EmailAddress, destination, thread.Subject, latestMessage.MetaMessageId, latestMessage.MetaMessageId, message)
+	formattedMessage := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nReferences: %s\r\nIn-Reply-To: %s\r\n", profile.EmailAddress, destination, thread.Subject, latestMessage.MetaMessageId, latestMessage.MetaMessageId)
+	if attachment == nil {
+		formattedMessage += "\r\n" + message
+	} else {
+		formattedMessage += formatEmailAttachmentBody(message, attachment)
+	}
 
 	gmailMessage := &gmail.Message{
 		ThreadId: thread.ID,
 		Raw:      base64.StdEncoding.EncodeToString([]byte(formattedMessage)),
 	}
 
-	gmailMessage, err = service.emailRepo.SendEmail(gmailMessage)
+	gmailMessage, err = emailRepo.SendEmail(gmailMessage)
 	if err != nil {
 		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling SendEmail, error: %+v", err)
 	}
 
-	res, err = service.messageRepo.CreateMessage(&entity.Message{
+	newMessage := entity.Message{
 		InteractionId: interaction.ID,
 		Message:       message,
-		SentBy:        enum.AGENT,
+		SentBy:        sentBy,
 		RecipientId:   profile.EmailAddress,
-	})
+		MetaMessageId: gmailMessage.Id,
+	}
+	if attachment != nil {
+		newMessage.AttachmentType = attachmentTypeByContentType(attachment.ContentType)
+		newMessage.AttachmentUrl = attachment.Url
+	}
+
+	res, err = service.messageRepo.CreateMessage(&newMessage)
 	if err != nil {
 		return messageId, nil, fmt.Errorf("[EmailService][SendEmail] error when calling CreateMessage, error: %+v", err)
 	}
 
 	return res.MetaMessageId, res, nil
 }
+
+// formatEmailAttachmentBody returns the MIME headers and multipart/mixed body of
+// an email with the message as its text part and the attachment in base64.
+func formatEmailAttachmentBody(message string, attachment *presentation.OutboundAttachment) string {
+	boundary := fmt.Sprintf("omnichannel-%d", time.Now().UnixNano())
+
+	encodedContent := base64.StdEncoding.EncodeToString(attachment.Content)
+	var wrappedContent strings.Builder
+	for len(encodedContent) > 76 {
+		wrappedContent.WriteString(encodedContent[:76] + "\r\n")
+		encodedContent = encodedContent[76:]
+	}
+	wrappedContent.WriteString(encodedContent)
+
+	fileName := mime.QEncoding.Encode("utf-8", attachment.Name)
+
+	return fmt.Sprintf("MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%q\r\n\r\n"+
+		"--%s\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s\r\n"+
+		"--%s\r\nContent-Type: %s; name=%q\r\nContent-Disposition: attachment; filename=%q\r\nContent-Transfer-Encoding: base64\r\n\r\n%s\r\n"+
+		"--%s--",
+		boundary, boundary, message, boundary, attachment.ContentType, fileName, fileName, wrappedContent.String(), boundary)
+}
+
+// StartEmailThread sends the first email of an interaction opened by an agent,
+// from the mailbox of the platform id of the interaction when it has one.
+// The new Gmail thread becomes the conversation of the interaction, its From is
+// the reporter so SendEmail replies to them, and the Gmail id of the message
+// keeps ProcessWebhook from storing it again when it shows up in the history.
+func (service *EmailService) StartEmailThread(interactionId uint, subject string, message string) (messageId string, res *entity.Message, err error) {
+	interaction, err := service.interactionRepo.GetInteractionById(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling GetInteractionById, error: %+v", err)
+	}
+
+	emailRepo, err := service.mailboxEmailRepo(interaction.PlatformId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling mailboxEmailRepo, error: %+v", err)
+	}
+
+	profile, err := emailRepo.GetProfile()
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling GetProfile, error: %+v", err)
+	}
+
+	reporter, err := service.reporterRepo.GetReporterByReporterId(interaction.ReporterId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling GetReporterByReporterId, error: %+v", err)
+	}
+
+	// the subject is typed by the agent, it is encoded like the one of
+	// sendSmtpEmail so it may hold any character but a line break
+	subject = emailHeaderText(subject)
+	formattedMessage := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s",
+		profile.EmailAddress, emailHeaderText(reporter.Email), mime.QEncoding.Encode("utf-8", subject), message)
+
+	gmailMessage := &gmail.Message{
+		Raw: base64.StdEncoding.EncodeToString([]byte(formattedMessage)),
+	}
+
+	gmailMessage, err = emailRepo.SendEmail(gmailMessage)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling SendEmail, error: %+v", err)
+	}
+
+	_, err = service.threadRepo.InsertThread(entity.Thread{
+		ID:        gmailMessage.ThreadId,
+		Subject:   subject,
+		EmailDate: time.Now().Format(time.RFC1123Z),
+		From:      fmt.Sprintf("%s <%s>", reporter.Name, reporter.Email),
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling InsertThread, error: %+v", err)
+	}
+
+	_, err = service.interactionRepo.UpdateInteraction(interaction.ID, &entity.Interaction{
+		ConversationId: gmailMessage.ThreadId,
+		PlatformId:     profile.EmailAddress,
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling UpdateInteraction, error: %+v", err)
+	}
+
+	res, err = service.messageRepo.CreateMessage(&entity.Message{
+		InteractionId:    interaction.ID,
+		Message:          message,
+		SentBy:           enum.AGENT,
+		RecipientId:      reporter.Email,
+		MetaMessageId:    gmailMessage.Id,
+		MessageTimestamp: time.Now(),
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[EmailService][StartEmailThread] error when calling CreateMessage, error: %+v", err)
+	}
+
+	return res.MetaMessageId, res, nil
+}
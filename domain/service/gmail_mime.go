@@ -0,0 +1,170 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/utils"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+	"google.golang.org/api/gmail/v1"
+)
+
+// gmailMessageParts are the parts of a Gmail message the email is read from:
+// the first text/plain and text/html bodies and every attachment.
+type gmailMessageParts struct {
+	text        *gmail.MessagePart
+	html        *gmail.MessagePart
+	attachments []*gmail.MessagePart
+}
+
+// walkGmailMessagePart goes down the multipart tree of the message. A single
+// part message is its own body, multipart/alternative gives both bodies and
+// multipart/mixed or related add the attachments and inline images.
+func walkGmailMessagePart(part *gmail.MessagePart, parts *gmailMessageParts) {
+	if part == nil {
+		return
+	}
+
+	disposition, _, _ := mime.ParseMediaType(FindHeaders(part.Headers, "Content-Disposition"))
+	switch {
+	case part.Filename != "" || disposition == "attachment":
+		parts.attachments = append(parts.attachments, part)
+
+	case strings.HasPrefix(part.MimeType, "multipart/") || part.MimeType == "message/rfc822":
+		for _, childPart := range part.Parts {
+			walkGmailMessagePart(childPart, parts)
+		}
+
+	case part.MimeType == "text/plain":
+		if parts.text == nil {
+			parts.text = part
+		}
+
+	case part.MimeType == "text/html":
+		if parts.html == nil {
+			parts.html = part
+		}
+
+	case part.Body != nil && (part.Body.AttachmentId != "" || part.Body.Data != ""):
+		parts.attachments = append(parts.attachments, part)
+	}
+}
+
+// readGmailPartBody returns the decoded body of the part, downloading it when
+// Gmail keeps it apart from the message.
+func readGmailPartBody(emailRepo repository.IEmailRepository, messageId string, part *gmail.MessagePart) ([]byte, error) {
+	if part.Body == nil {
+		return nil, nil
+	}
+
+	if part.Body.AttachmentId != "" {
+		return emailRepo.GetAttachment(messageId, part.Body.AttachmentId)
+	}
+
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(part.Body.Data, "="))
+}
+
+// readGmailPartText returns the text of a text/plain or text/html part in
+// UTF-8, whatever the charset of its Content-Type.
+func readGmailPartText(emailRepo repository.IEmailRepository, messageId string, part *gmail.MessagePart) (string, error) {
+	body, err := readGmailPartBody(emailRepo, messageId, part)
+	if err != nil {
+		return "", err
+	}
+
+	_, params, _ := mime.ParseMediaType(FindHeaders(part.Headers, "Content-Type"))
+	if params["charset"] != "" {
+		reader, err := charset.NewReaderLabel(params["charset"], bytes.NewReader(body))
+		if err == nil {
+			decoded, err := io.ReadAll(reader)
+			if err == nil {
+				body = decoded
+			}
+		}
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// readGmailEmail fills the bodies and the attachments of the inbound email from
+// the Gmail message. The HTML body is sanitized and, when the email has no
+// text/plain part, turned into the plain-text message. An attachment that
+// cannot be kept is left out, the email is still ingested without it.
+func (service *EmailService) readGmailEmail(emailRepo repository.IEmailRepository, message *gmail.Message, inboundEmail *presentation.InboundEmail) error {
+	var parts gmailMessageParts
+	walkGmailMessagePart(message.Payload, &parts)
+
+	inlineUrls := make(map[string]string)
+	for _, part := range parts.attachments {
+		attachment, err := service.saveGmailAttachment(emailRepo, message.Id, part)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Gmail Attachment] Save part %s of message %s: %+v", part.PartId, message.Id, err))
+			continue
+		}
+
+		if attachment.ContentId != "" {
+			inlineUrls[attachment.ContentId] = attachment.Url
+		}
+		inboundEmail.Attachments = append(inboundEmail.Attachments, *attachment)
+	}
+
+	if parts.html != nil {
+		html, err := readGmailPartText(emailRepo, message.Id, parts.html)
+		if err != nil {
+			return err
+		}
+		inboundEmail.HtmlMessage = utils.SanitizeHtml(html, inlineUrls)
+
+		if parts.text == nil {
+			inboundEmail.Message = utils.HtmlToText(html)
+		}
+	}
+
+	if parts.text != nil {
+		text, err := readGmailPartText(emailRepo, message.Id, parts.text)
+		if err != nil {
+			return err
+		}
+		inboundEmail.Message = text
+	}
+
+	return nil
+}
+
+// saveGmailAttachment keeps the part in the attachment store. The file is
+// named after the message and the part, so two emails sending the same file
+// name do not overwrite each other.
+func (service *EmailService) saveGmailAttachment(emailRepo repository.IEmailRepository, messageId string, part *gmail.MessagePart) (*presentation.InboundEmailAttachment, error) {
+	content, err := readGmailPartBody(emailRepo, messageId, part)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s-%s", messageId, strings.ReplaceAll(part.PartId, ".", "_"))
+	if part.Filename != "" {
+		name += "-" + part.Filename
+	}
+
+	attachmentUrl, err := service.attachmentStore.SaveAttachment(enum.EMAIL, name, part.MimeType, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := presentation.InboundEmailAttachment{
+		Name:        part.Filename,
+		ContentType: part.MimeType,
+		ContentId:   strings.Trim(FindHeaders(part.Headers, "Content-Id"), "<>"),
+		Size:        int64(len(content)),
+		Url:         attachmentUrl,
+	}
+
+	return &attachment, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/config"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// GmailWatchService keeps the Gmail push subscriptions alive. A watch expires
+// after seven days and Gmail stops pushing without any notice, so it is renewed
+// ahead of its expiration.
+type GmailWatchService struct {
+	emailService     *EmailService
+	gmailMailboxRepo repository.IGmailMailboxRepository
+}
+
+type IGmailWatchService interface {
+	RenewGmailWatches(bool) error
+	StartGmailWatchScheduler()
+	GetEmailSubscriptionHealth() (map[string]interface{}, error)
+}
+
+func NewGmailWatchService(emailService *EmailService, gmailMailboxRepo repository.IGmailMailboxRepository) *GmailWatchService {
+	gmailWatchService := GmailWatchService{
+		emailService:     emailService,
+		gmailMailboxRepo: gmailMailboxRepo,
+	}
+	return &gmailWatchService
+}
+
+// RenewGmailWatches watches every mailbox whose watch expires within
+// Gmail.WatchRenewBefore (default one day), was never made or failed last
+// time. force renews all of them, which is done once at boot.
+func (gws *GmailWatchService) RenewGmailWatches(force bool) error {
+	renewBefore := viper.GetDuration("Gmail.WatchRenewBefore")
+	if renewBefore <= 0 {
+		renewBefore = 24 * time.Hour
+	}
+
+	emailAddresses, err := gws.emailService.GetMailboxAddresses()
+	if err != nil {
+		return fmt.Errorf("[GmailWatchService][RenewGmailWatches] error when calling GetMailboxAddresses, error: %+v", err)
+	}
+
+	for _, emailAddress := range emailAddresses {
+		var watchExpiration *time.Time
+
+		gmailMailbox, err := gws.gmailMailboxRepo.GetGmailMailboxByEmailAddress(emailAddress)
+		if err == nil {
+			watchExpiration = gmailMailbox.WatchExpiration
+			if !force && gmailMailbox.WatchError == "" && watchExpiration != nil && time.Until(*watchExpiration) > renewBefore {
+				continue
+			}
+		}
+
+		watchRes, err := gws.emailService.WatchMailbox(emailAddress)
+		if err != nil {
+			gws.reportWatchFailure(emailAddress, watchExpiration, err)
+			continue
+		}
+
+		_, err = gws.gmailMailboxRepo.SaveGmailMailboxWatch(emailAddress, watchRes.HistoryId, time.UnixMilli(watchRes.Expiration))
+		if err != nil {
+			logger.Error(fmt.Sprintf("[FAILED][Gmail Watch] Save watch of %s: %+v", emailAddress, err))
+		}
+	}
+
+	return nil
+}
+
+// reportWatchFailure keeps the error on the mailbox for the health check and
+// alerts the supervisors, the last watch still runs until its expiration.
+func (gws *GmailWatchService) reportWatchFailure(emailAddress string, watchExpiration *time.Time, watchErr error) {
+	logger.Error(fmt.Sprintf("[FAILED][Gmail Watch] Watch %s: %+v", emailAddress, watchErr))
+
+	_, err := gws.gmailMailboxRepo.SaveGmailMailboxWatchError(emailAddress, watchErr.Error())
+	if err != nil {
+		logger.Error(fmt.Sprintf("[FAILED][Gmail Watch] Save watch error of %s: %+v", emailAddress, err))
+	}
+
+	err = gws.WebsocketEmailSubscriptionAlertService(presentation.EmailSubscriptionAlert{
+		EmailAddress:    emailAddress,
+		Error:           watchErr.Error(),
+		WatchExpiration: watchExpiration,
+	})
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Gmail Watch] Notify watch failure of %s: %+v", emailAddress, err))
+	}
+}
+
+// StartGmailWatchScheduler runs RenewGmailWatches every Gmail.WatchCheckInterval
+// (default one hour). It blocks, so it is meant to be started in its own
+// goroutine.
+func (gws *GmailWatchService) StartGmailWatchScheduler() {
+	interval := viper.GetDuration("Gmail.WatchCheckInterval")
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := gws.RenewGmailWatches(false)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Gmail Watch Scheduler] Renew Gmail watches: %+v", err))
+		}
+	}
+}
+
+func gmailWatchStatus(gmailMailbox entity.GmailMailbox) string {
+	switch {
+	case gmailMailbox.WatchExpiration == nil:
+		return enum.NOT_WATCHED
+	case !gmailMailbox.WatchExpiration.After(time.Now()):
+		return enum.EXPIRED
+	case gmailMailbox.WatchError != "":
+		return enum.FAILED
+	default:
+		return enum.ACTIVE
+	}
+}
+
+// GetEmailSubscriptionHealth returns the watch of every known mailbox. The
+// result comes with EMAIL_SUBSCRIPTION_UNHEALTHY when one of them is not
+// active.
+func (gws *GmailWatchService) GetEmailSubscriptionHealth() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	var mailboxes []presentation.EmailSubscriptionHealth
+
+	gmailMailboxList, err := gws.gmailMailboxRepo.GetGmailMailboxList()
+	if err != nil {
+		return nil, fmt.Errorf("[GmailWatchService][GetEmailSubscriptionHealth] error when calling GetGmailMailboxList, error: %+v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, gmailMailbox := range gmailMailboxList {
+		known[gmailMailbox.EmailAddress] = true
+	}
+
+	// mailboxes that were never watched have no row yet
+	emailAddresses, err := gws.emailService.GetMailboxAddresses()
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Email Subscription Health] Get mailbox addresses: %+v", err))
+	}
+	for _, emailAddress := range emailAddresses {
+		if !known[emailAddress] {
+			gmailMailboxList = append(gmailMailboxList, entity.GmailMailbox{EmailAddress: emailAddress})
+		}
+	}
+
+	healthy := true
+	for _, gmailMailbox := range gmailMailboxList {
+		status := gmailWatchStatus(gmailMailbox)
+		if status != enum.ACTIVE {
+			healthy = false
+		}
+
+		mailboxes = append(mailboxes, presentation.EmailSubscriptionHealth{
+			EmailAddress:    gmailMailbox.EmailAddress,
+			Status:          status,
+			HistoryId:       gmailMailbox.HistoryId,
+			LastSyncedAt:    gmailMailbox.LastSyncedAt,
+			WatchExpiration: gmailMailbox.WatchExpiration,
+			WatchRenewedAt:  gmailMailbox.WatchRenewedAt,
+			WatchError:      gmailMailbox.WatchError,
+		})
+	}
+
+	result["healthy"] = healthy
+	result["mailboxes"] = mailboxes
+
+	if !healthy {
+		return result, enum.EMAIL_SUBSCRIPTION_UNHEALTHY
+	}
+
+	return result, nil
+}
+
+type EmailSubscriptionAlertToSend struct {
+	Action  string                              `json:"action"`
+	Mailbox presentation.EmailSubscriptionAlert `json:"mailbox"`
+}
+
+func (gws *GmailWatchService) WebsocketEmailSubscriptionAlertService(alert presentation.EmailSubscriptionAlert) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	connectionId := fmt.Sprintf("gmail-watch-%s", alert.EmailAddress)
+	query := fmt.Sprintf("user_id=%s&room_id=%s", url.QueryEscape(connectionId), url.QueryEscape(alert.EmailAddress))
+	client, err := NewWebSocketClient(host, channel, connectionId, query)
+	if err != nil {
+		return err
+	}
+
+	send := EmailSubscriptionAlertToSend{
+		Action:  "email-subscription-alert",
+		Mailbox: alert,
+	}
+	err = client.Write(send)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// GmailWebhookProcessor processes the queued Gmail push notifications. Every
+// mailbox has its own history cursor kept in GmailMailbox, the notifications
+// are processed one at a time so a cursor is never read while it is moved.
+type GmailWebhookProcessor struct {
+	emailService     *EmailService
+	gmailMailboxRepo repository.IGmailMailboxRepository
+	lock             sync.Mutex
+}
+
+func NewGmailWebhookProcessor(emailService *EmailService, gmailMailboxRepo repository.IGmailMailboxRepository) *GmailWebhookProcessor {
+	gmailWebhookProcessor := GmailWebhookProcessor{
+		emailService:     emailService,
+		gmailMailboxRepo: gmailMailboxRepo,
+	}
+	return &gmailWebhookProcessor
+}
+
+func (gwp *GmailWebhookProcessor) ProcessWebhookInbox(webhookInbox *entity.WebhookInbox) error {
+	var req presentation.GmailInteractionRequest
+	var data presentation.GmailNotificationData
+
+	err := json.Unmarshal([]byte(webhookInbox.Payload), &req)
+	if err != nil {
+		return err
+	}
+
+	jsonString, err := utils.DecodeBase64(req.Message.Data)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal([]byte(jsonString), &data)
+	if err != nil {
+		return err
+	}
+
+	gwp.lock.Lock()
+	defer gwp.lock.Unlock()
+
+	// the backfill of an expired cursor starts from the last sync, or from
+	// Gmail.BackfillWindow (default one day) ago for a mailbox never synced
+	backfillWindow := viper.GetDuration("Gmail.BackfillWindow")
+	if backfillWindow <= 0 {
+		backfillWindow = 24 * time.Hour
+	}
+	backfillAfter := time.Now().Add(-backfillWindow)
+
+	// a mailbox that was never read starts from the history id of its watch
+	var prevHistoryId int
+	gmailMailbox, err := gwp.gmailMailboxRepo.GetGmailMailboxByEmailAddress(data.EmailAddress)
+	if err == nil {
+		prevHistoryId = int(gmailMailbox.HistoryId)
+		if prevHistoryId == 0 {
+			prevHistoryId = int(gmailMailbox.WatchHistoryId)
+		}
+		if gmailMailbox.LastSyncedAt != nil {
+			backfillAfter = *gmailMailbox.LastSyncedAt
+		}
+
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var historyId uint64
+	if prevHistoryId == 0 {
+		err = enum.GMAIL_HISTORY_EXPIRED
+	} else {
+		historyId, err = gwp.emailService.ProcessWebhook(req.Message.Data, prevHistoryId)
+	}
+	if errors.Is(err, enum.GMAIL_HISTORY_EXPIRED) {
+		logger.Info(fmt.Sprintf("[Gmail Webhook] History %d of %s expired, backfilling since %s", prevHistoryId, data.EmailAddress, backfillAfter.Format(time.RFC3339)))
+		historyId, err = gwp.emailService.BackfillMailbox(data.EmailAddress, backfillAfter, time.Now().Add(time.Minute))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = gwp.gmailMailboxRepo.SaveGmailMailboxHistoryId(data.EmailAddress, historyId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
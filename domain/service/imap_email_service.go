@@ -0,0 +1,681 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/utils"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ImapEmailService reads the mailboxes of the channel accounts that have an
+// IMAP server and sends their emails through SMTP, for mail servers other than
+// Gmail. The cursor of every mailbox is the UID of the last email read, saved
+// in ImapMailbox.
+type ImapEmailService struct {
+	emailPipeline
+	channelAccountRepo repository.IChannelAccountRepository
+	imapMailboxRepo    repository.IImapMailboxRepository
+	webhookInboxRepo   repository.IWebhookInboxRepository
+
+	// channel accounts whose mailbox has a running ingester
+	ingesters     map[uint]bool
+	ingestersLock sync.Mutex
+}
+
+func NewImapEmailService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, threadRepo repository.ThreadRepository, routingService IRoutingService, csatSurveyRepo repository.ICsatSurveyRepository, channelAccountRepo repository.IChannelAccountRepository, imapMailboxRepo repository.IImapMailboxRepository, webhookInboxRepo repository.IWebhookInboxRepository) *ImapEmailService {
+	imapEmailService := ImapEmailService{
+		emailPipeline: emailPipeline{
+			interactionRepo: interactionRepo,
+			messageRepo:     messageRepo,
+			reporterRepo:    reporterRepo,
+			threadRepo:      threadRepo,
+			routingService:  routingService,
+			csatSurveyRepo:  csatSurveyRepo,
+		},
+		channelAccountRepo: channelAccountRepo,
+		imapMailboxRepo:    imapMailboxRepo,
+		webhookInboxRepo:   webhookInboxRepo,
+		ingesters:          make(map[uint]bool),
+	}
+	return &imapEmailService
+}
+
+// isImapChannelAccount tells whether the mailbox of the channel account is read
+// over IMAP instead of the Gmail API.
+func isImapChannelAccount(channelAccount *entity.ChannelAccount) bool {
+	return channelAccount.EmailAddress != "" && channelAccount.ImapAddress != ""
+}
+
+// imapChannelAccount returns the channel account of the IMAP mailbox with the
+// given email address.
+func (service *ImapEmailService) imapChannelAccount(emailAddress string) (*entity.ChannelAccount, error) {
+	channelAccount, err := service.channelAccountRepo.GetChannelAccountByPlatformId(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+	if !isImapChannelAccount(channelAccount) {
+		return nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	}
+
+	return channelAccount, nil
+}
+
+// imapMailbox is the folder read in every mailbox, Email.Imap.Mailbox or INBOX.
+func imapMailbox() string {
+	mailbox := viper.GetString("Email.Imap.Mailbox")
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	return mailbox
+}
+
+// dialImap connects to the IMAP server of the channel account with TLS unless
+// Email.Imap.TLS is false and logs in, with the email address when the account
+// has no IMAP username.
+func dialImap(channelAccount *entity.ChannelAccount, updates chan client.Update) (*client.Client, error) {
+	var imapClient *client.Client
+	var err error
+
+	if viper.IsSet("Email.Imap.TLS") && !viper.GetBool("Email.Imap.TLS") {
+		imapClient, err = client.Dial(channelAccount.ImapAddress)
+	} else {
+		imapClient, err = client.DialTLS(channelAccount.ImapAddress, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	imapClient.Updates = updates
+
+	username := channelAccount.ImapUsername
+	if username == "" {
+		username = channelAccount.EmailAddress
+	}
+
+	err = imapClient.Login(username, channelAccount.ImapPassword)
+	if err != nil {
+		imapClient.Logout()
+		return nil, err
+	}
+
+	return imapClient, nil
+}
+
+// SyncImapMailbox reads the emails of the mailbox of the channel account
+// received after the cursor saved in ImapMailbox and returns the new cursor.
+// The very first sync only saves the current last UID so the emails already in
+// the mailbox are not turned into interactions. When the UIDVALIDITY of the
+// mailbox changed the UIDs were renumbered and the whole mailbox is read again,
+// the emails already ingested are known by their Message-ID.
+func (service *ImapEmailService) SyncImapMailbox(channelAccount *entity.ChannelAccount) (historyId uint64, err error) {
+	emailAddress := channelAccount.EmailAddress
+
+	savedMailbox, err := service.imapMailboxRepo.GetImapMailboxByEmailAddress(emailAddress)
+	firstSync := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !firstSync {
+		return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling GetImapMailboxByEmailAddress, error: %+v", err)
+	}
+
+	imapClient, err := dialImap(channelAccount, nil)
+	if err != nil {
+		return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling dialImap, error: %+v", err)
+	}
+	defer imapClient.Logout()
+
+	mailboxStatus, err := imapClient.Select(imapMailbox(), true)
+	if err != nil {
+		return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling Select, error: %+v", err)
+	}
+	uidValidity := mailboxStatus.UidValidity
+
+	if firstSync {
+		var lastUid uint32
+		if mailboxStatus.UidNext > 0 {
+			lastUid = mailboxStatus.UidNext - 1
+		}
+
+		_, err = service.imapMailboxRepo.SaveImapMailboxCursor(emailAddress, uidValidity, lastUid)
+		if err != nil {
+			return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling SaveImapMailboxCursor, error: %+v", err)
+		}
+		return uint64(lastUid), nil
+	}
+
+	if savedMailbox.UidValidity == uidValidity {
+		historyId = uint64(savedMailbox.LastUid)
+	} else {
+		logger.Info(fmt.Sprintf("[Imap Email] UIDVALIDITY of %s changed from %d to %d, reading the mailbox again", emailAddress, savedMailbox.UidValidity, uidValidity))
+	}
+
+	if mailboxStatus.UidNext > 0 && uint64(mailboxStatus.UidNext) <= historyId+1 {
+		return historyId, nil
+	}
+
+	seqSet := new(imap.SeqSet)
+	seqSet.AddRange(uint32(historyId)+1, 0)
+
+	section := &imap.BodySectionName{Peek: true}
+	imapMessages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- imapClient.UidFetch(seqSet, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, imapMessages)
+	}()
+
+	var fetchedMessages []*imap.Message
+	for imapMessage := range imapMessages {
+		// UID n:* always returns the last email, even when its UID is below n
+		if uint64(imapMessage.Uid) > historyId {
+			fetchedMessages = append(fetchedMessages, imapMessage)
+		}
+	}
+
+	err = <-done
+	if err != nil {
+		return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling UidFetch, error: %+v", err)
+	}
+
+	sort.Slice(fetchedMessages, func(i, j int) bool {
+		return fetchedMessages[i].Uid < fetchedMessages[j].Uid
+	})
+
+	for _, imapMessage := range fetchedMessages {
+		// a missing body is read again on the next sync
+		body := imapMessage.GetBody(section)
+		if body == nil {
+			return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] no body for UID %d", imapMessage.Uid)
+		}
+
+		rawEmail, err := io.ReadAll(body)
+		if err != nil {
+			return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when reading UID %d, error: %+v", imapMessage.Uid, err)
+		}
+
+		err = service.ingestImapEmail(emailAddress, rawEmail, fmt.Sprintf("imap-%d-%d", uidValidity, imapMessage.Uid))
+		if errors.Is(err, errImapEmailUnreadable) {
+			// the email would fail on every sync, it is kept as a dead
+			// letter to be replayed once the parser handles it
+			err = service.deadLetterImapEmail(emailAddress, rawEmail, err)
+		}
+		if err != nil {
+			return historyId, err
+		}
+
+		_, err = service.imapMailboxRepo.SaveImapMailboxCursor(emailAddress, uidValidity, imapMessage.Uid)
+		if err != nil {
+			return historyId, fmt.Errorf("[ImapEmailService][SyncImapMailbox] error when calling SaveImapMailboxCursor, error: %+v", err)
+		}
+		historyId = uint64(imapMessage.Uid)
+	}
+
+	return historyId, nil
+}
+
+// errImapEmailUnreadable wraps the parse errors of an email, which are not
+// fixed by reading the email again.
+var errImapEmailUnreadable = errors.New("IMAP_EMAIL_UNREADABLE")
+
+// ingestImapEmail parses the raw email received on the mailbox and hands it to
+// the email pipeline, defaultMessageId is the Message-ID of an email that has
+// none.
+func (service *ImapEmailService) ingestImapEmail(emailAddress string, rawEmail []byte, defaultMessageId string) error {
+	inboundEmail, err := parseImapEmail(emailAddress, bytes.NewReader(rawEmail))
+	if err != nil {
+		return fmt.Errorf("%w: %+v", errImapEmailUnreadable, err)
+	}
+	if inboundEmail.MessageId == "" {
+		inboundEmail.MessageId = defaultMessageId
+	}
+	if inboundEmail.ThreadId == "" {
+		inboundEmail.ThreadId = inboundEmail.MessageId
+	}
+
+	return service.ingestEmail(*inboundEmail)
+}
+
+// deadLetterImapEmail keeps an email that can not be read in the webhook inbox
+// as DEAD_LETTER, so it shows up with the other failed deliveries and can be
+// replayed. The raw email is kept in base64 since it may not be valid UTF-8.
+func (service *ImapEmailService) deadLetterImapEmail(emailAddress string, rawEmail []byte, parseErr error) error {
+	logger.Error(fmt.Sprintf("[FAILED][Imap Email] Read email of %s: %+v", emailAddress, parseErr))
+
+	payload := base64.StdEncoding.EncodeToString(rawEmail)
+	payloadHash := webhookPayloadHash(enum.IMAP, emailAddress, []byte(payload))
+
+	_, err := service.webhookInboxRepo.GetWebhookInboxByPayloadHash(payloadHash)
+	if err == nil {
+		return nil
+
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("[ImapEmailService][deadLetterImapEmail] error when calling GetWebhookInboxByPayloadHash, error: %+v", err)
+	}
+
+	_, err = service.webhookInboxRepo.CreateWebhookInbox(&entity.WebhookInbox{
+		Platform:      enum.IMAP,
+		PlatformId:    emailAddress,
+		PayloadHash:   payloadHash,
+		Payload:       payload,
+		Status:        enum.DEAD_LETTER,
+		Attempts:      1,
+		LastError:     parseErr.Error(),
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("[ImapEmailService][deadLetterImapEmail] error when calling CreateWebhookInbox, error: %+v", err)
+	}
+
+	return nil
+}
+
+// ProcessWebhookInbox ingests a replayed dead letter of deadLetterImapEmail.
+func (service *ImapEmailService) ProcessWebhookInbox(webhookInbox *entity.WebhookInbox) error {
+	rawEmail, err := base64.StdEncoding.DecodeString(webhookInbox.Payload)
+	if err != nil {
+		return err
+	}
+
+	return service.ingestImapEmail(webhookInbox.PlatformId, rawEmail, fmt.Sprintf("imap-dead-letter-%d", webhookInbox.ID))
+}
+
+// trimMessageId removes the angle brackets around a Message-ID.
+func trimMessageId(messageId string) string {
+	return strings.Trim(strings.TrimSpace(messageId), "<>")
+}
+
+// parseImapEmail reads the headers and the text of a raw email received on the
+// mailbox. The thread of the email is the first Message-ID of its References,
+// which is the email that started the conversation, or the email it replies to.
+func parseImapEmail(mailbox string, body io.Reader) (*presentation.InboundEmail, error) {
+	message, err := mail.ReadMessage(body)
+	if err != nil {
+		return nil, err
+	}
+
+	wordDecoder := new(mime.WordDecoder)
+	decodeHeader := func(key string) string {
+		value, err := wordDecoder.DecodeHeader(message.Header.Get(key))
+		if err != nil {
+			return message.Header.Get(key)
+		}
+		return value
+	}
+
+	inboundEmail := presentation.InboundEmail{
+		MessageId: trimMessageId(message.Header.Get("Message-Id")),
+		Subject:   decodeHeader("Subject"),
+		From:      decodeHeader("From"),
+		Date:      message.Header.Get("Date"),
+		Mailbox:   mailbox,
+	}
+
+	references := strings.Fields(message.Header.Get("References"))
+	if len(references) > 0 {
+		inboundEmail.ThreadId = trimMessageId(references[0])
+	} else {
+		inboundEmail.ThreadId = trimMessageId(message.Header.Get("In-Reply-To"))
+	}
+
+	inboundEmail.Message = findImapEmailText(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), message.Body)
+
+	return &inboundEmail, nil
+}
+
+// findImapEmailText returns the first text/plain part of the email, decoded
+// from base64 or quoted-printable.
+func findImapEmailText(contentType string, transferEncoding string, body io.Reader) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		multipartReader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := multipartReader.NextPart()
+			if err != nil {
+				return ""
+			}
+
+			text := findImapEmailText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if text != "" {
+				return text
+			}
+		}
+	}
+
+	if mediaType != "text/plain" {
+		return ""
+	}
+
+	switch strings.ToLower(transferEncoding) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	text, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(text))
+}
+
+// sendSmtpEmail sends the email from the mailbox of the channel account
+// through its SMTP server, with STARTTLS when the server offers it, and
+// returns the Message-ID it was given.
+func sendSmtpEmail(channelAccount *entity.ChannelAccount, to string, subject string, headers string, message string, attachment *presentation.OutboundAttachment) (string, error) {
+	fromAddress := channelAccount.EmailAddress
+	from := (&mail.Address{Name: channelAccount.Name, Address: fromAddress}).String()
+
+	domain := fromAddress[strings.LastIndex(fromAddress, "@")+1:]
+	messageId := fmt.Sprintf("%s@%s", uuid.New().String(), domain)
+
+	formattedMessage := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMessage-ID: <%s>\r\n%s",
+		from, emailHeaderText(to), mime.QEncoding.Encode("utf-8", emailHeaderText(subject)), time.Now().Format(time.RFC1123Z), messageId, headers)
+	if attachment == nil {
+		formattedMessage += "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n" + message
+	} else {
+		formattedMessage += formatEmailAttachmentBody(message, attachment)
+	}
+
+	address := channelAccount.SmtpAddress
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	var auth smtp.Auth
+	if channelAccount.SmtpUsername != "" {
+		auth = smtp.PlainAuth("", channelAccount.SmtpUsername, channelAccount.SmtpPassword, host)
+	}
+
+	err = smtp.SendMail(address, auth, fromAddress, []string{to}, []byte(formattedMessage))
+	if err != nil {
+		return "", err
+	}
+
+	return messageId, nil
+}
+
+// SendEmail replies in the thread of the interaction from the mailbox it was
+// received on. The reply references the first email of the thread and the
+// latest one so mail clients keep it in the conversation, and SyncImapMailbox
+// threads the answer of the reporter back.
+func (service *ImapEmailService) SendEmail(interactionId uint, message string, sentBy string, attachment *presentation.OutboundAttachment) (messageId string, res *entity.Message, err error) {
+	interaction, err := service.interactionRepo.GetInteractionById(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling GetInteractionById, error: %+v", err)
+	}
+
+	channelAccount, err := service.imapChannelAccount(interaction.PlatformId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling imapChannelAccount, error: %+v", err)
+	}
+
+	thread, err := service.threadRepo.GetThreadByID(interaction.ConversationId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling GetThreadByID, error: %+v", err)
+	}
+
+	_, destination := utils.ParseFromHeader(thread.From)
+	if destination == "" {
+		destination = thread.From
+	}
+
+	latestMessage, err := service.messageRepo.GetLatestMessageofInteraction(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling GetLatestMessageofInteraction, error: %+v", err)
+	}
+
+	references := fmt.Sprintf("<%s>", thread.ID)
+	headers := ""
+	if latestMessage.MetaMessageId != "" && !strings.HasPrefix(latestMessage.MetaMessageId, "imap-") {
+		headers = fmt.Sprintf("In-Reply-To: <%s>\r\n", latestMessage.MetaMessageId)
+		if latestMessage.MetaMessageId != thread.ID {
+			references += fmt.Sprintf(" <%s>", latestMessage.MetaMessageId)
+		}
+	}
+	headers += fmt.Sprintf("References: %s\r\n", references)
+
+	subject := thread.Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+
+	messageId, err = sendSmtpEmail(channelAccount, destination, subject, headers, message, attachment)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling sendSmtpEmail, error: %+v", err)
+	}
+
+	newMessage := entity.Message{
+		InteractionId:    interaction.ID,
+		Message:          message,
+		SentBy:           sentBy,
+		RecipientId:      destination,
+		MetaMessageId:    messageId,
+		MessageTimestamp: time.Now(),
+	}
+	if attachment != nil {
+		newMessage.AttachmentType = attachmentTypeByContentType(attachment.ContentType)
+		newMessage.AttachmentUrl = attachment.Url
+	}
+
+	res, err = service.messageRepo.CreateMessage(&newMessage)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][SendEmail] error when calling CreateMessage, error: %+v", err)
+	}
+
+	return res.MetaMessageId, res, nil
+}
+
+// StartEmailThread sends the first email of an interaction opened by an agent
+// from the mailbox of its platform id, its Message-ID becomes the thread of the
+// interaction.
+func (service *ImapEmailService) StartEmailThread(interactionId uint, subject string, message string) (messageId string, res *entity.Message, err error) {
+	interaction, err := service.interactionRepo.GetInteractionById(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling GetInteractionById, error: %+v", err)
+	}
+
+	channelAccount, err := service.imapChannelAccount(interaction.PlatformId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling imapChannelAccount, error: %+v", err)
+	}
+
+	reporter, err := service.reporterRepo.GetReporterByReporterId(interaction.ReporterId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling GetReporterByReporterId, error: %+v", err)
+	}
+
+	subject = emailHeaderText(subject)
+	messageId, err = sendSmtpEmail(channelAccount, reporter.Email, subject, "", message, nil)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling sendSmtpEmail, error: %+v", err)
+	}
+
+	_, err = service.threadRepo.InsertThread(entity.Thread{
+		ID:        messageId,
+		Subject:   subject,
+		EmailDate: time.Now().Format(time.RFC1123Z),
+		From:      fmt.Sprintf("%s <%s>", reporter.Name, reporter.Email),
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling InsertThread, error: %+v", err)
+	}
+
+	_, err = service.interactionRepo.UpdateInteraction(interaction.ID, &entity.Interaction{
+		ConversationId: messageId,
+		PlatformId:     channelAccount.EmailAddress,
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling UpdateInteraction, error: %+v", err)
+	}
+
+	res, err = service.messageRepo.CreateMessage(&entity.Message{
+		InteractionId:    interaction.ID,
+		Message:          message,
+		SentBy:           enum.AGENT,
+		RecipientId:      reporter.Email,
+		MetaMessageId:    messageId,
+		MessageTimestamp: time.Now(),
+	})
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[ImapEmailService][StartEmailThread] error when calling CreateMessage, error: %+v", err)
+	}
+
+	return res.MetaMessageId, res, nil
+}
+
+// waitForImapEmail returns when the mailbox of the channel account got a new
+// email, with IMAP IDLE when Email.Imap.Idle is set, or after pollInterval.
+func waitForImapEmail(channelAccount *entity.ChannelAccount, pollInterval time.Duration) {
+	if !viper.GetBool("Email.Imap.Idle") {
+		time.Sleep(pollInterval)
+		return
+	}
+
+	updates := make(chan client.Update, 10)
+	imapClient, err := dialImap(channelAccount, updates)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] Connect for IDLE: %+v", err))
+		time.Sleep(pollInterval)
+		return
+	}
+	defer imapClient.Logout()
+
+	// the client blocks until its updates are read, so they are read until logout
+	newEmail := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case update := <-updates:
+				if _, ok := update.(*client.MailboxUpdate); ok {
+					select {
+					case newEmail <- struct{}{}:
+					default:
+					}
+				}
+			case <-imapClient.LoggedOut():
+				return
+			}
+		}
+	}()
+
+	_, err = imapClient.Select(imapMailbox(), true)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] Select mailbox for IDLE: %+v", err))
+		time.Sleep(pollInterval)
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- imapClient.Idle(stop, nil)
+	}()
+
+	select {
+	case <-newEmail:
+		close(stop)
+		err = <-done
+	case <-time.After(pollInterval):
+		close(stop)
+		err = <-done
+	case err = <-done:
+	}
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] IDLE: %+v", err))
+	}
+}
+
+func imapPollInterval() time.Duration {
+	pollInterval := viper.GetDuration("Email.Imap.PollInterval")
+	if pollInterval <= 0 {
+		pollInterval = time.Minute
+	}
+	return pollInterval
+}
+
+// runImapIngester reads the new emails of the mailbox of the channel account
+// and waits for the next ones, until the channel account is deleted or no
+// longer has an IMAP server.
+func (service *ImapEmailService) runImapIngester(channelAccountId uint) {
+	defer func() {
+		service.ingestersLock.Lock()
+		delete(service.ingesters, channelAccountId)
+		service.ingestersLock.Unlock()
+	}()
+
+	for {
+		channelAccount, err := service.channelAccountRepo.GetChannelAccountById(channelAccountId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+
+		} else if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] Load channel account %d: %+v", channelAccountId, err))
+			time.Sleep(imapPollInterval())
+			continue
+		}
+		if !isImapChannelAccount(channelAccount) {
+			return
+		}
+
+		_, err = service.SyncImapMailbox(channelAccount)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] Process mailbox %s: %+v", channelAccount.EmailAddress, err))
+		}
+
+		waitForImapEmail(channelAccount, imapPollInterval())
+	}
+}
+
+// StartImapIngester runs an ingester for the mailbox of every channel account
+// with an IMAP server, each reads Email.Imap.Mailbox (default INBOX) and waits
+// at most Email.Imap.PollInterval (default one minute) for new emails. The
+// channel accounts are listed again at the same interval so new mailboxes are
+// picked up. It blocks, so it is meant to be started in its own goroutine.
+func (service *ImapEmailService) StartImapIngester() {
+	for {
+		channelAccountList, err := service.channelAccountRepo.GetImapChannelAccountList()
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Imap Ingester] List mailboxes: %+v", err))
+		}
+
+		service.ingestersLock.Lock()
+		for _, channelAccount := range channelAccountList {
+			if service.ingesters[channelAccount.ID] {
+				continue
+			}
+
+			service.ingesters[channelAccount.ID] = true
+			go service.runImapIngester(channelAccount.ID)
+		}
+		service.ingestersLock.Unlock()
+
+		time.Sleep(imapPollInterval())
+	}
+}
@@ -8,12 +8,18 @@ import (
 	"Omnichannel-CRM/package/logger"
 	"Omnichannel-CRM/package/presentation"
 	"Omnichannel-CRM/package/request"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	//"sort"
 	"time"
@@ -29,29 +35,40 @@ type InteractionService struct {
 	reporterRepo    repository.IReporterRepository
 	emailService    IEmailService
 	threadRepo      repository.IThreadRepository
+	routingService  IRoutingService
+	capacityService ICapacityService
+
+	channelAccountRepo      repository.IChannelAccountRepository
+	interactionTransferRepo repository.IInteractionTransferRepository
+	slaService              ISlaService
+	channelAdapterRegistry  IChannelAdapterRegistry
+	attachmentStore         AttachmentStore
 }
 
 type IInteractionService interface {
+	ClaimInteraction(*presentation.ClaimInteractionRequest, string) (*entity.Interaction, error)
 	UpdateInteractionStatusByAgent(*presentation.ClaimInteractionRequest, string, string) (*entity.Interaction, error)
+	TransferInteraction(*presentation.TransferInteractionRequest, string, int) (map[string]interface{}, error)
+	GetInteractionTransferList(uint) (map[string]interface{}, error)
 	GetInteractionList(map[string]interface{}, *entity.ChannelAccount) (map[string]interface{}, error)
 	GetInteractionMessages(uint) (map[string]interface{}, error)
 	GetAgentInteractions(string, map[string]interface{}) (map[string]interface{}, error)
 
-	MessengerSendMessagetoMeta(*presentation.MetaSendMessageRequest, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
-	WhatsappSendMessagetoMeta(*presentation.MetaSendMessageRequest, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
-	LiveChatSendMessage(*presentation.MetaSendMessageRequest) (map[string]interface{}, *entity.Message, error)
+	SendMessage(*presentation.MetaSendMessageRequest, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
+	LiveChatSendMessage(*presentation.MetaSendMessageRequest, string) (map[string]interface{}, *entity.Message, error)
+	GetChannelCapabilities(string) (ChannelCapabilities, error)
 
 	GetClosedInteractionsData() (map[string]interface{}, error)
 	SendClosedInteractionData(*entity.Interaction) error
-	SendMessageToEmail(req presentation.MessengerSendEmailRequest) (map[string]interface{}, *entity.Message, error)
 	CreateLiveChatInteraction(*presentation.CreateLiveChatInteractionRequest) (map[string]interface{}, error)
 
 	GetGeotagInformation(uint, presentation.GetGeotagInformation) (entity.GeotagInformation, error)
 
 	WebsocketSendService(messages entity.Message) error
+	WebsocketTransferService(entity.InteractionTransfer) error
 }
 
-func NewInteractionService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, userRepo repository.IUserRepository, reporterRepo repository.IReporterRepository, emailService IEmailService, threadRepo repository.IThreadRepository) *InteractionService {
+func NewInteractionService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, userRepo repository.IUserRepository, reporterRepo repository.IReporterRepository, channelAccountRepo repository.IChannelAccountRepository, emailService IEmailService, threadRepo repository.IThreadRepository, routingService IRoutingService, capacityService ICapacityService, interactionTransferRepo repository.IInteractionTransferRepository, slaService ISlaService, channelAdapterRegistry IChannelAdapterRegistry, attachmentStore AttachmentStore) *InteractionService {
 	interactionService := InteractionService{
 		interactionRepo: interactionRepo,
 		messageRepo:     messageRepo,
@@ -59,6 +76,14 @@ func NewInteractionService(interactionRepo repository.IinteractionRepository, me
 		reporterRepo:    reporterRepo,
 		emailService:    emailService,
 		threadRepo:      threadRepo,
+		routingService:  routingService,
+		capacityService: capacityService,
+
+		channelAccountRepo:      channelAccountRepo,
+		interactionTransferRepo: interactionTransferRepo,
+		slaService:              slaService,
+		channelAdapterRegistry:  channelAdapterRegistry,
+		attachmentStore:         attachmentStore,
 	}
 	return &interactionService
 }
@@ -146,6 +171,8 @@ func (mws *InteractionService) WebsocketSendService(message entity.Message) erro
 			MetaMessageId:    message.MetaMessageId,
 			Message:          message.Message,
 			MessageTimestamp: message.MessageTimestamp,
+			AttachmentType:   message.AttachmentType,
+			AttachmentUrl:    message.AttachmentUrl,
 			SentBy:           message.SentBy,
 			IsRead:           message.IsRead,
 			IsDeleted:        message.IsDeleted,
@@ -159,6 +186,46 @@ func (mws *InteractionService) WebsocketSendService(message entity.Message) erro
 	return nil
 }
 
+type TransferToSend struct {
+	Action   string                                       `json:"action"`
+	Transfer presentation.InteractionTransferNotification `json:"transfer"`
+}
+
+// WebsocketTransferService notifies the receiving agent of a transfer. The
+// websocket server forwards it to the connection of transfer.ToAgentId only.
+func (is *InteractionService) WebsocketTransferService(transfer entity.InteractionTransfer) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	connectionId := fmt.Sprintf("transfer-%d", transfer.InteractionId)
+	query := fmt.Sprintf("user_id=%s&room_id=%d", connectionId, transfer.InteractionId)
+	client, err := NewWebSocketClient(host, channel, connectionId, query)
+	if err != nil {
+		return err
+	}
+
+	send := TransferToSend{
+		Action: "transfer-interaction",
+		Transfer: presentation.InteractionTransferNotification{
+			TransferId:    transfer.ID,
+			InteractionId: transfer.InteractionId,
+			FromAgentId:   transfer.FromAgentId,
+			ToAgentId:     transfer.ToAgentId,
+			TransferredBy: transfer.TransferredBy,
+			Note:          transfer.Note,
+			TransferredAt: transfer.CreatedAt,
+		},
+	}
+	err = client.Write(send)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (is *InteractionService) GetInteractionList(filters map[string]interface{}, channelAccount *entity.ChannelAccount) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
@@ -169,6 +236,11 @@ func (is *InteractionService) GetInteractionList(filters map[string]interface{},
 	var count int64
 	checkDuplicateAgentIds := make(map[string]bool)
 
+	channelAccount, err := loadChannelAccount(is.channelAccountRepo, channelAccount)
+	if err != nil {
+		return nil, fmt.Errorf("[InteractionService][GetInteractionList] error when calling loadChannelAccount, error: %+v", err)
+	}
+
 	interactionList, count, err := is.interactionRepo.GetInteractionList(filters, channelAccount)
 	if (interactionList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
 		result["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
@@ -199,6 +271,14 @@ func (is *InteractionService) GetInteractionList(filters map[string]interface{},
 		return result, err
 	}
 
+	slaList, err := is.slaService.GetInteractionSla(interactionList)
+	if err != nil {
+		result["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		result["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		return result, err
+	}
+
+	now := time.Now()
 	for _, v := range interactionList {
 		dild := presentation.DashboardInteractionList{
 			InteractionId:   v.ID,
@@ -210,7 +290,10 @@ func (is *InteractionService) GetInteractionList(filters map[string]interface{},
 			Status:          v.Status,
 			Platform:        v.Platform,
 			InteractionType: v.InteractionType,
-			Duration:        v.Duration,
+			RoutingStrategy: v.RoutingStrategy,
+			Sla:             slaList[v.ID],
+
+			InteractionDurations: presentation.GetInteractionDurations(v, now),
 		}
 		for _, w := range agentList {
 			if w.ID == v.AgentId {
@@ -228,6 +311,25 @@ func (is *InteractionService) GetInteractionList(filters map[string]interface{},
 	return result, nil
 }
 
+func (is *InteractionService) ClaimInteraction(cir *presentation.ClaimInteractionRequest, userId string) (*entity.Interaction, error) {
+	interaction, err := is.interactionRepo.GetInteractionById(cir.InteractionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if interaction.AgentId != userId || interaction.Status != enum.IN_PROGRESS {
+		err = is.capacityService.CheckAgentCapacity(userId, interaction.Platform)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return is.UpdateInteractionStatusByAgent(cir, userId, enum.IN_PROGRESS)
+}
+
 func (is *InteractionService) UpdateInteractionStatusByAgent(cir *presentation.ClaimInteractionRequest, userId string, status string) (*entity.Interaction, error) {
 	interactionId := cir.InteractionId
 	newInteraction := entity.Interaction{
@@ -246,247 +348,268 @@ func (is *InteractionService) UpdateInteractionStatusByAgent(cir *presentation.C
 	return interaction, nil
 }
 
-func (is *InteractionService) LiveChatSendMessage(msmr *presentation.MetaSendMessageRequest) (map[string]interface{}, *entity.Message, error) {
+func isAdminRole(role int) bool {
+	return role == enum.ROLE_ADMIN_PUSAT || role == enum.ROLE_ADMIN_PROVINSI || role == enum.ROLE_ADMIN_KOTA
+}
+
+// TransferInteraction hands an IN_PROGRESS interaction to another agent, or back
+// to the unclaimed queue when no target agent is given. Only the handling agent
+// or an admin may transfer it.
+func (is *InteractionService) TransferInteraction(tir *presentation.TransferInteractionRequest, userId string, role int) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
-	interaction, err := is.interactionRepo.GetInteractionById(msmr.InteractionId)
+	interaction, err := is.interactionRepo.GetInteractionById(tir.InteractionId)
 	if (interaction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
-		result["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
-		result["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
-		return result, nil, err
+		return nil, enum.ERROR_DATA_NOT_FOUND
 
 	} else if err != nil {
-		result["errorStatus"] = enum.SYSTEM_BUSY_STATUS
-		result["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
-		return result, nil, err
+		return nil, err
 	}
 
-	var senderId string
-	var recipientId string
-
-	if msmr.SentBy == enum.AGENT {
-		senderId = msmr.PlatformId
-		recipientId = fmt.Sprint(msmr.ReporterId)
-	} else {
-		senderId = fmt.Sprint(msmr.ReporterId)
-		recipientId = msmr.PlatformId
+	if interaction.Status != enum.IN_PROGRESS {
+		return nil, enum.INTERACTION_NOT_IN_PROGRESS
 	}
 
-	sendedMessage := entity.Message{
-		InteractionId:    msmr.InteractionId,
-		SenderId:         senderId,
-		RecipientId:      recipientId,
-		Message:          msmr.Message,
-		MessageTimestamp: time.Now(),
-		SentBy:           msmr.SentBy,
-		IsRead:           false,
+	if interaction.AgentId != userId && !isAdminRole(role) {
+		return nil, enum.INTERACTION_NOT_OWNED
 	}
 
-	message, err := is.messageRepo.CreateMessage(&sendedMessage)
-	if err != nil {
-		return nil, nil, err
+	if tir.ToAgentId == interaction.AgentId {
+		return nil, enum.INVALID_TRANSFER_TARGET
 	}
 
-	result["message_id"] = message.ID
-
-	return result, message, nil
-}
+	var transferredInteraction *entity.Interaction
+	if tir.ToAgentId == "" {
+		transferredInteraction, err = is.interactionRepo.ReleaseInteraction(interaction.ID)
+	} else {
+		err = is.capacityService.CheckAgentCapacity(tir.ToAgentId, interaction.Platform)
+		if errors.Is(err, enum.ERROR_DATA_NOT_FOUND) {
+			return nil, enum.INVALID_TRANSFER_TARGET
 
-func (is *InteractionService) MessengerSendMessagetoMeta(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
-	result := make(map[string]interface{})
-	var access_token string
+		} else if err != nil {
+			return nil, err
+		}
 
-	if channelAccount.ID == 0 {
-		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+		newInteraction := entity.Interaction{
+			AgentId: tir.ToAgentId,
+			Status:  enum.IN_PROGRESS,
+		}
+		transferredInteraction, err = is.interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if channelAccount.FaceboookPageId == "" {
-		return nil, nil, enum.PLATFORM_ID_NOT_SET
+	newTransfer := entity.InteractionTransfer{
+		InteractionId: interaction.ID,
+		FromAgentId:   interaction.AgentId,
+		ToAgentId:     tir.ToAgentId,
+		TransferredBy: userId,
+		Note:          tir.Note,
 	}
 
-	reporter, err := is.reporterRepo.GetReporterByReporterId(msmr.ReporterId)
+	transfer, err := is.interactionTransferRepo.CreateInteractionTransfer(&newTransfer)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	platform := msmr.Platform
-
-	if platform == enum.FACEBOOK {
-		if msmr.PlatformId != channelAccount.FaceboookPageId {
-			return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
-		}
-		if channelAccount.FacebookAccessToken == "" {
-			return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
-		}
-		access_token = channelAccount.FacebookAccessToken
-	} else {
-		if channelAccount.InstagramId == "" {
-			return nil, nil, enum.PLATFORM_ID_NOT_SET
-		}
-
-		if msmr.PlatformId != channelAccount.InstagramId {
-			return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
-		}
-		if channelAccount.InstagramAccessToken == "" {
-			return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	if transfer.ToAgentId != "" {
+		err = is.WebsocketTransferService(*transfer)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Transfer Interaction] Notify agent %s: %+v", transfer.ToAgentId, err))
 		}
-		access_token = channelAccount.InstagramAccessToken
 	}
 
-	path := fmt.Sprintf("/%s/%s/messages", viper.GetString("Meta.API_VERSION"), channelAccount.FaceboookPageId)
-	params := url.Values{}
-	params.Add("access_token", access_token)
+	result["interaction_id"] = transferredInteraction.ID
+	result["agent_id"] = transferredInteraction.AgentId
+	result["interaction_status"] = transferredInteraction.Status
+	result["transfer"] = transfer
 
-	reqUrl := url.URL{
-		Scheme:   "https",
-		Host:     "graph.facebook.com",
-		Path:     path,
-		RawQuery: params.Encode(),
-	}
+	return result, nil
+}
 
-	body := presentation.MessengerSendMessageMetaRequest{
-		Recipient:     presentation.IdField{Id: reporter.MetaReporterId},
-		MessagingType: "MESSAGE_TAG",
-		Tag:           "HUMAN_AGENT",
-		Message:       presentation.MessageSendMetaField{MessageText: msmr.Message},
-	}
+func (is *InteractionService) GetInteractionTransferList(interactionId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
 
-	response, err := request.PostRequest(reqUrl, body, "")
-	if err != nil {
-		return nil, nil, err
+	interactionTransferList, err := is.interactionTransferRepo.GetInteractionTransferListByInteractionId(interactionId)
+	if (interactionTransferList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
 	}
 
-	defer response.Body.Close()
+	result["interaction_transfer_list"] = interactionTransferList
 
-	if response.StatusCode == http.StatusOK {
-		messageData := &presentation.MessengerSendMessageMetaResponse{}
-		err = json.NewDecoder(response.Body).Decode(messageData)
-		if err != nil {
-			return nil, nil, err
-		}
+	return result, nil
+}
 
-		sendedMessage := entity.Message{
-			InteractionId:    msmr.InteractionId,
-			SenderId:         msmr.PlatformId,
-			RecipientId:      messageData.ReporterId,
-			MetaMessageId:    messageData.MessageId,
-			Message:          msmr.Message,
-			MessageTimestamp: time.Now(),
-			SentBy:           enum.AGENT,
-			IsRead:           false,
-		}
+// attachmentTypeByContentType maps the content type of an uploaded file to the
+// attachment type of the message, files that are not media are documents.
+func attachmentTypeByContentType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return enum.IMAGE
+	case strings.HasPrefix(contentType, "video/"):
+		return enum.VIDEO
+	case strings.HasPrefix(contentType, "audio/"):
+		return enum.AUDIO
+	}
+	return enum.DOCUMENT
+}
 
-		message, err := is.messageRepo.CreateMessage(&sendedMessage)
+// prepareOutboundAttachment keeps the uploaded file in the attachment store and
+// turns a location pin into the JSON message of an inbound location, so the
+// stored message looks the same whatever the platform does with it.
+func (is *InteractionService) prepareOutboundAttachment(adapter ChannelAdapter, msmr *presentation.MetaSendMessageRequest) error {
+	if msmr.Attachment == nil && msmr.Location == nil {
+		return nil
+	}
+
+	if !adapter.Capabilities().Attachments {
+		return enum.ATTACHMENT_NOT_SUPPORTED
+	}
+
+	if msmr.Location != nil {
+		location, err := json.Marshal(msmr.Location)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
 
-		result["message_id"] = message.MetaMessageId
+		msmr.Message = string(location)
+		msmr.AttachmentType = enum.LOCATION
+		return nil
+	}
 
-		return result, message, nil
+	_, allowed := attachmentExtension(msmr.Attachment.ContentType)
+	if !allowed {
+		return enum.ATTACHMENT_TYPE_NOT_ALLOWED
 	}
 
-	bodyBytes, err := io.ReadAll(response.Body)
+	// uploads may share a name, the prefix keeps them apart in the store
+	name := fmt.Sprintf("%d-%s", time.Now().UnixNano(), msmr.Attachment.Name)
+	attachmentUrl, err := is.attachmentStore.SaveAttachment(msmr.Platform, name, msmr.Attachment.ContentType, bytes.NewReader(msmr.Attachment.Content))
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
-	bodyString := string(bodyBytes)
-	logger.Info(response.Status)
-	logger.Info(bodyString)
-	return nil, nil, errors.New("meta response not 200")
-}
 
-func (is *InteractionService) WhatsappSendMessagetoMeta(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
-	result := make(map[string]interface{})
+	msmr.Attachment.Url = attachmentUrl
+	msmr.AttachmentType = attachmentTypeByContentType(msmr.Attachment.ContentType)
+	msmr.AttachmentUrl = attachmentUrl
 
-	if channelAccount.ID == 0 {
-		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
-	}
+	return nil
+}
 
-	if channelAccount.WhatsappNumId == "" {
-		return nil, nil, enum.PLATFORM_ID_NOT_SET
+// outboundMessageText is the text sent for the message on platforms that have
+// no location message, the location pin becomes a maps link.
+func outboundMessageText(msmr *presentation.MetaSendMessageRequest) string {
+	if msmr.Location == nil {
+		return msmr.Message
 	}
 
-	if channelAccount.WhatsappBusinessId == "" {
-		return nil, nil, enum.PLATFORM_ID_NOT_SET
-	}
+	mapsLink := fmt.Sprintf("https://maps.google.com/?q=%s,%s", strconv.FormatFloat(msmr.Location.Latitude, 'f', -1, 64), strconv.FormatFloat(msmr.Location.Longitude, 'f', -1, 64))
 
-	if msmr.PlatformId != channelAccount.WhatsappBusinessId {
-		return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+	var labels []string
+	if msmr.Location.Name != "" {
+		labels = append(labels, msmr.Location.Name)
 	}
-
-	if channelAccount.WhatsappAccessToken == "" {
-		return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	if msmr.Location.Address != "" {
+		labels = append(labels, msmr.Location.Address)
+	}
+	if len(labels) == 0 {
+		return mapsLink
 	}
-	access_token := channelAccount.WhatsappAccessToken
 
-	reporter, err := is.reporterRepo.GetReporterByReporterId(msmr.ReporterId)
+	return strings.Join(labels, ", ") + "\n" + mapsLink
+}
+
+// SendMessage sends the message through the adapter of msmr.Platform.
+func (is *InteractionService) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	adapter, err := is.channelAdapterRegistry.GetAdapter(msmr.Platform)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	path := fmt.Sprintf("/%s/%s/messages", viper.GetString("Meta.WA_API_VERSION"), channelAccount.WhatsappNumId)
-
-	reqUrl := url.URL{
-		Scheme: "https",
-		Host:   "graph.facebook.com",
-		Path:   path,
-	}
-
-	body := presentation.WhatsappSendMessageMetaRequest{
-		MessagingProduct: "whatsapp",
-		RecipientType:    "individual",
-		Recipient:        reporter.MetaReporterId,
-		MessageType:      "text",
-		Text: presentation.WhatsappMessageMetaField{
-			PreviewUrl: false,
-			Body:       msmr.Message,
-		},
+	err = is.prepareOutboundAttachment(adapter, msmr)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	response, err := request.PostRequest(reqUrl, body, access_token)
+	return adapter.SendMessage(msmr, channelAccount)
+}
+
+// newLiveChatToken returns the secret of a new live chat session.
+func newLiveChatToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
 	if err != nil {
-		return nil, nil, err
+		return "", err
 	}
 
-	defer response.Body.Close()
+	return hex.EncodeToString(token), nil
+}
 
-	if response.StatusCode == http.StatusOK {
-		messageData := &presentation.WhatsappSendMessageMetaResponse{}
-		err = json.NewDecoder(response.Body).Decode(messageData)
-		if err != nil {
-			return nil, nil, err
-		}
+// checkLiveChatSession tells if liveChatToken is the one of the live chat
+// interaction of the message. The reporter of the message is taken from the
+// interaction, not from the request.
+func (is *InteractionService) checkLiveChatSession(msmr *presentation.MetaSendMessageRequest, liveChatToken string) error {
+	interaction, err := is.interactionRepo.GetInteractionById(msmr.InteractionId)
+	if (interaction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return enum.LIVE_CHAT_SESSION_INVALID
 
-		sendedMessage := entity.Message{
-			InteractionId:    msmr.InteractionId,
-			SenderId:         msmr.PlatformId,
-			RecipientId:      messageData.Contacts[0].WaId,
-			MetaMessageId:    messageData.Messages[0].Id,
-			Message:          msmr.Message,
-			MessageTimestamp: time.Now(),
-			SentBy:           enum.AGENT,
-			IsRead:           false,
-		}
+	} else if err != nil {
+		return err
+	}
+
+	if interaction.Platform != enum.LIVE_CHAT || interaction.LiveChatToken == "" || subtle.ConstantTimeCompare([]byte(interaction.LiveChatToken), []byte(liveChatToken)) != 1 {
+		return enum.LIVE_CHAT_SESSION_INVALID
+	}
+
+	msmr.ReporterId = interaction.ReporterId
+	return nil
+}
 
-		message, err := is.messageRepo.CreateMessage(&sendedMessage)
+// LiveChatSendMessage stores a live chat message of either the reporter or the
+// agent, live chat has no channel account to send through. A message of the
+// reporter needs the token of its live chat session.
+func (is *InteractionService) LiveChatSendMessage(msmr *presentation.MetaSendMessageRequest, liveChatToken string) (map[string]interface{}, *entity.Message, error) {
+	if msmr.SentBy == enum.REPORTER {
+		err := is.checkLiveChatSession(msmr, liveChatToken)
 		if err != nil {
 			return nil, nil, err
 		}
+	}
 
-		result["message_id"] = message.MetaMessageId
-
-		return result, message, nil
+	adapter, err := is.channelAdapterRegistry.GetAdapter(enum.LIVE_CHAT)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	bodyBytes, err := io.ReadAll(response.Body)
+	err = is.prepareOutboundAttachment(adapter, msmr)
 	if err != nil {
 		return nil, nil, err
 	}
-	bodyString := string(bodyBytes)
-	logger.Info(response.Status)
-	logger.Info(bodyString)
-	return nil, nil, errors.New("meta response not 200")
+
+	return adapter.SendMessage(msmr, &entity.ChannelAccount{})
+}
+
+func (is *InteractionService) GetChannelCapabilities(platform string) (ChannelCapabilities, error) {
+	adapter, err := is.channelAdapterRegistry.GetAdapter(platform)
+	if err != nil {
+		return ChannelCapabilities{}, err
+	}
+
+	return adapter.Capabilities(), nil
+}
+
+// outboundSentBy keeps SYSTEM for automatic replies (e.g. out of hours) so
+// they are not counted as the agent's first response.
+func outboundSentBy(msmr *presentation.MetaSendMessageRequest) string {
+	if msmr.SentBy == enum.SYSTEM {
+		return enum.SYSTEM
+	}
+
+	return enum.AGENT
 }
 
 func (is *InteractionService) GetInteractionMessages(interactionId uint) (map[string]interface{}, error) {
@@ -525,6 +648,15 @@ func (is *InteractionService) GetInteractionMessages(interactionId uint) (map[st
 		}
 
 		result["threadInfo"] = thread
+
+		attachments, err := is.messageRepo.GetMessageAttachmentsofInteraction(interactionId)
+		if err != nil {
+			result["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+			result["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+			return result, err
+		}
+
+		result["attachments"] = attachments
 	}
 
 	result["interaction"] = interaction
@@ -548,6 +680,31 @@ func (is *InteractionService) GetAgentInteractions(userId string, filters map[st
 		return result, err
 	}
 
+	var slaInteractions []entity.Interaction
+	for _, v := range interactions {
+		slaInteraction := entity.Interaction{
+			Status:          v.Status,
+			Platform:        v.Platform,
+			InteractionType: v.InteractionType,
+		}
+		slaInteraction.ID = v.InteractionId
+		slaInteraction.CreatedAt = v.InteractionCreatedAt
+		slaInteraction.UpdatedAt = v.InteractionUpdatedAt
+		slaInteraction.ClosedAt = v.InteractionClosedAt
+		slaInteractions = append(slaInteractions, slaInteraction)
+	}
+
+	slaList, err := is.slaService.GetInteractionSla(slaInteractions)
+	if err != nil {
+		result["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		result["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		return result, err
+	}
+
+	for i, v := range interactions {
+		interactions[i].Sla = slaList[v.InteractionId]
+	}
+
 	result["interaction_list"] = interactions
 
 	return result, nil
@@ -558,9 +715,8 @@ func (is *InteractionService) GetClosedInteractionsData() (map[string]interface{
 	filter := make(map[string]interface{})
 	filter["status"] = []string{enum.CLOSED}
 	dataList := []presentation.InteractionReporterData{}
-	channelAccount := entity.ChannelAccount{}
 
-	interactionList, _, err := is.interactionRepo.GetInteractionList(filter, &channelAccount)
+	interactionList, _, err := is.interactionRepo.GetInteractionList(filter, nil)
 	if (interactionList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, enum.ERROR_DATA_NOT_FOUND
 
@@ -624,7 +780,12 @@ func (is *InteractionService) SendClosedInteractionData(interaction *entity.Inte
 	body.Status = interaction.Status
 	body.Platform = interaction.Platform
 	body.InteractionType = interaction.InteractionType
-	body.Duration = interaction.Duration
+	body.ClaimedAt = interaction.ClaimedAt
+	body.FirstResponseAt = interaction.FirstResponseAt
+	body.ClosedAt = interaction.ClosedAt
+	body.Duration = interaction.TotalOpenTimeSeconds
+	body.HandleTime = interaction.HandleTimeSeconds
+	body.WaitTime = interaction.WaitTimeSeconds
 	body.Latitude = interaction.Latitude
 	body.Longitude = interaction.Longitude
 
@@ -661,19 +822,6 @@ func (is *InteractionService) SendClosedInteractionData(interaction *entity.Inte
 	return nil
 }
 
-func (is *InteractionService) SendMessageToEmail(req presentation.MessengerSendEmailRequest) (map[string]interface{}, *entity.Message, error) {
-	messageId, message, err := is.emailService.SendEmail(req.InteractionId, req.Message)
-	if err != nil {
-		return nil, nil, fmt.Errorf("[InteractionService][SendMessageToEmail] Error when calling SendEmail, trace: %+v", err)
-	}
-
-	res := map[string]interface{}{
-		"message_id": messageId,
-	}
-
-	return res, message, nil
-}
-
 func (is *InteractionService) CreateLiveChatInteraction(clcir *presentation.CreateLiveChatInteractionRequest) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
@@ -688,11 +836,17 @@ func (is *InteractionService) CreateLiveChatInteraction(clcir *presentation.Crea
 		return nil, err
 	}
 
+	liveChatToken, err := newLiveChatToken()
+	if err != nil {
+		return nil, err
+	}
+
 	newInteraction := entity.Interaction{
 		ReporterId:      reporter.ID,
 		Status:          enum.UNCLAIMED,
 		Platform:        enum.LIVE_CHAT,
 		InteractionType: enum.PESAN,
+		LiveChatToken:   liveChatToken,
 	}
 
 	interaction, err := is.interactionRepo.CreateInteraction(&newInteraction)
@@ -700,8 +854,11 @@ func (is *InteractionService) CreateLiveChatInteraction(clcir *presentation.Crea
 		return nil, err
 	}
 
+	interaction = assignNewInteraction(is.routingService, interaction)
+
 	result["reporter"] = reporter
 	result["interaction"] = interaction
+	result["live_chat_token"] = liveChatToken
 
 	return result, nil
 }
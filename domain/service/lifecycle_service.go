@@ -0,0 +1,248 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type LifecycleService struct {
+	interactionRepo    repository.IinteractionRepository
+	messageRepo        repository.IMessageRepository
+	interactionService IInteractionService
+	chatbotService     IChatbotService
+}
+
+type ILifecycleService interface {
+	ProcessInteractionLifecycle() error
+	StartLifecycleScheduler()
+}
+
+func NewLifecycleService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, interactionService IInteractionService, chatbotService IChatbotService) *LifecycleService {
+	lifecycleService := LifecycleService{
+		interactionRepo:    interactionRepo,
+		messageRepo:        messageRepo,
+		interactionService: interactionService,
+		chatbotService:     chatbotService,
+	}
+	return &lifecycleService
+}
+
+// getLifecycleThreshold reads Lifecycle.<PLATFORM>.<key> in minutes and falls
+// back to Lifecycle.Default.<key> when the platform does not set it. A
+// threshold of 0 disables the transition, also when a default is set.
+func getLifecycleThreshold(platform string, key string) time.Duration {
+	platformKey := fmt.Sprintf("Lifecycle.%s.%s", platform, key)
+
+	minutes := viper.GetInt64(fmt.Sprintf("Lifecycle.Default.%s", key))
+	if viper.IsSet(platformKey) {
+		minutes = viper.GetInt64(platformKey)
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// resumeWaitingInteraction moves a WAITING interaction back to IN_PROGRESS once
+// the reporter writes again. Failures are logged so ingestion is not blocked.
+func resumeWaitingInteraction(interactionRepo repository.IinteractionRepository, interaction *entity.Interaction) *entity.Interaction {
+	if interaction.Status != enum.WAITING {
+		return interaction
+	}
+
+	newInteraction := entity.Interaction{
+		Status: enum.IN_PROGRESS,
+	}
+
+	resumedInteraction, err := interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Resume Interaction] Interaction %d stays waiting: %+v", interaction.ID, err))
+		return interaction
+	}
+
+	return resumedInteraction
+}
+
+func messageTime(message *entity.Message) time.Time {
+	if message.MessageTimestamp.IsZero() {
+		return message.CreatedAt
+	}
+
+	return message.MessageTimestamp
+}
+
+// isReporterSilent reports whether the last message of the interaction was
+// sent by the agent longer than threshold ago.
+func (ls *LifecycleService) isReporterSilent(interaction entity.Interaction, threshold time.Duration, now time.Time) (bool, error) {
+	latestMessage, err := ls.interactionLatestMessage(interaction.ID)
+	if latestMessage == nil || err != nil {
+		return false, err
+	}
+
+	if latestMessage.SentBy != enum.AGENT {
+		return false, nil
+	}
+
+	return now.Sub(messageTime(latestMessage)) > threshold, nil
+}
+
+func (ls *LifecycleService) interactionLatestMessage(interactionId uint) (*entity.Message, error) {
+	latestMessage, err := ls.messageRepo.GetLatestMessageofInteraction(interactionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return latestMessage, nil
+}
+
+func (ls *LifecycleService) updateInteractionStatus(interaction entity.Interaction, status string) (*entity.Interaction, error) {
+	newInteraction := entity.Interaction{
+		Status: status,
+	}
+
+	updatedInteraction, err := ls.interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("[Lifecycle] Interaction %d moved from %s to %s", interaction.ID, interaction.Status, status))
+
+	return updatedInteraction, nil
+}
+
+// isBotIdle reports whether nothing was said in the BOT interaction for longer
+// than threshold. Without messages the interaction is idle since it was moved
+// to BOT.
+func (ls *LifecycleService) isBotIdle(interaction entity.Interaction, threshold time.Duration, now time.Time) (bool, error) {
+	latestMessage, err := ls.interactionLatestMessage(interaction.ID)
+	if err != nil {
+		return false, err
+	}
+
+	idleSince := interaction.UpdatedAt
+	if latestMessage != nil && messageTime(latestMessage).After(idleSince) {
+		idleSince = messageTime(latestMessage)
+	}
+
+	return now.Sub(idleSince) > threshold, nil
+}
+
+// closeInteraction closes the interaction and pushes it to the CRM like an
+// agent close does, reverting to its previous status when the CRM rejects it.
+func (ls *LifecycleService) closeInteraction(interaction entity.Interaction) error {
+	closedInteraction, err := ls.updateInteractionStatus(interaction, enum.CLOSED)
+	if err != nil {
+		return err
+	}
+
+	err = ls.interactionService.SendClosedInteractionData(closedInteraction)
+	if err != nil {
+		_, revertErr := ls.updateInteractionStatus(*closedInteraction, interaction.Status)
+		if revertErr != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Lifecycle] Revert interaction %d to %s: %+v", interaction.ID, interaction.Status, revertErr))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ProcessInteractionLifecycle applies the timeouts: an idle BOT interaction is
+// closed or handed over, UNCLAIMED without agent becomes MISSED, IN_PROGRESS
+// with a silent reporter becomes WAITING, and WAITING for long enough becomes
+// CLOSED. UNCLAIMED is measured from the last status change, so an interaction
+// handed over by the bot gets the full time in the queue.
+func (ls *LifecycleService) ProcessInteractionLifecycle() error {
+	now := time.Now()
+	status := []string{enum.BOT, enum.UNCLAIMED, enum.IN_PROGRESS, enum.WAITING}
+
+	interactionList, err := ls.interactionRepo.GetInteractionListByStatus(status)
+	if len(interactionList) == 0 || err != nil {
+		return err
+	}
+
+	for _, v := range interactionList {
+		switch v.Status {
+		case enum.BOT:
+			closeThreshold := getLifecycleThreshold(v.Platform, "BotCloseAfterMinutes")
+			handoverThreshold := getLifecycleThreshold(v.Platform, "BotHandoverAfterMinutes")
+
+			var isIdle bool
+			if closeThreshold != 0 {
+				isIdle, err = ls.isBotIdle(v, closeThreshold, now)
+				if isIdle && err == nil {
+					err = ls.closeInteraction(v)
+					break
+				}
+			}
+			if handoverThreshold != 0 && err == nil {
+				isIdle, err = ls.isBotIdle(v, handoverThreshold, now)
+				if isIdle && err == nil {
+					err = ls.chatbotService.HandoverInteraction(&v)
+				}
+			}
+
+		case enum.UNCLAIMED:
+			threshold := getLifecycleThreshold(v.Platform, "MissedAfterMinutes")
+			if threshold == 0 || v.AgentId != "" || now.Sub(v.UpdatedAt) <= threshold {
+				continue
+			}
+
+			_, err = ls.updateInteractionStatus(v, enum.MISSED)
+
+		case enum.IN_PROGRESS:
+			threshold := getLifecycleThreshold(v.Platform, "WaitingAfterMinutes")
+			if threshold == 0 {
+				continue
+			}
+
+			var isSilent bool
+			isSilent, err = ls.isReporterSilent(v, threshold, now)
+			if isSilent && err == nil {
+				_, err = ls.updateInteractionStatus(v, enum.WAITING)
+			}
+
+		case enum.WAITING:
+			threshold := getLifecycleThreshold(v.Platform, "CloseAfterMinutes")
+			if threshold == 0 || now.Sub(v.UpdatedAt) <= threshold {
+				continue
+			}
+
+			err = ls.closeInteraction(v)
+		}
+
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Lifecycle] Interaction %d with status %s: %+v", v.ID, v.Status, err))
+		}
+	}
+
+	return nil
+}
+
+// StartLifecycleScheduler runs ProcessInteractionLifecycle every
+// Lifecycle.CheckInterval (default one minute) and blocks.
+func (ls *LifecycleService) StartLifecycleScheduler() {
+	interval := viper.GetDuration("Lifecycle.CheckInterval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := ls.ProcessInteractionLifecycle()
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Lifecycle Scheduler] Process interaction lifecycle: %+v", err))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LiveChatAdapter stores the messages of both sides of a live chat, the
+// widget reads them through the websocket so nothing is sent to a platform.
+type LiveChatAdapter struct {
+	interactionRepo repository.IinteractionRepository
+	messageRepo     repository.IMessageRepository
+}
+
+func NewLiveChatAdapter(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository) *LiveChatAdapter {
+	liveChatAdapter := LiveChatAdapter{
+		interactionRepo: interactionRepo,
+		messageRepo:     messageRepo,
+	}
+	return &liveChatAdapter
+}
+
+func (lca *LiveChatAdapter) Platform() string {
+	return enum.LIVE_CHAT
+}
+
+func (lca *LiveChatAdapter) Capabilities() ChannelCapabilities {
+	return ChannelCapabilities{
+		AutoReply: true,
+		Realtime:  true,
+
+		Attachments: true,
+	}
+}
+
+func (lca *LiveChatAdapter) VerifyWebhook(delivery *presentation.WebhookDelivery) error {
+	return enum.INVALID_PLATFORM
+}
+
+func (lca *LiveChatAdapter) ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error) {
+	return nil, enum.INVALID_PLATFORM
+}
+
+func (lca *LiveChatAdapter) FetchMedia(inboundMessage *presentation.InboundMessage) error {
+	return nil
+}
+
+func (lca *LiveChatAdapter) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	result := make(map[string]interface{})
+
+	interaction, err := lca.interactionRepo.GetInteractionById(msmr.InteractionId)
+	if (interaction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		result["errorStatus"] = enum.DATA_NOT_FOUND_STATUS
+		result["errorMessage"] = enum.DATA_NOT_FOUND_MESSAGE
+		return result, nil, err
+
+	} else if err != nil {
+		result["errorStatus"] = enum.SYSTEM_BUSY_STATUS
+		result["errorMessage"] = enum.SYSTEM_BUSY_MESSAGE
+		return result, nil, err
+	}
+
+	var senderId string
+	var recipientId string
+
+	if msmr.SentBy == enum.AGENT || msmr.SentBy == enum.SYSTEM {
+		senderId = msmr.PlatformId
+		recipientId = fmt.Sprint(msmr.ReporterId)
+	} else {
+		senderId = fmt.Sprint(msmr.ReporterId)
+		recipientId = msmr.PlatformId
+		resumeWaitingInteraction(lca.interactionRepo, interaction)
+	}
+
+	sendedMessage := entity.Message{
+		InteractionId:    msmr.InteractionId,
+		SenderId:         senderId,
+		RecipientId:      recipientId,
+		Message:          msmr.Message,
+		MessageTimestamp: time.Now(),
+		SentBy:           msmr.SentBy,
+		IsRead:           false,
+		AttachmentType:   msmr.AttachmentType,
+		AttachmentUrl:    msmr.AttachmentUrl,
+	}
+
+	message, err := lca.messageRepo.CreateMessage(&sendedMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result["message_id"] = message.ID
+
+	return result, message, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MailboxEmailService sends the emails of an interaction through the provider
+// of the mailbox of its platform id: IMAP and SMTP for the channel accounts
+// with an IMAP server, the Gmail API for the others. Only Gmail pushes the
+// changes of its mailboxes, so ProcessWebhook is the one of Gmail.
+type MailboxEmailService struct {
+	gmailEmailService  *EmailService
+	imapEmailService   *ImapEmailService
+	interactionRepo    repository.IinteractionRepository
+	channelAccountRepo repository.IChannelAccountRepository
+}
+
+func NewMailboxEmailService(gmailEmailService *EmailService, imapEmailService *ImapEmailService, interactionRepo repository.IinteractionRepository, channelAccountRepo repository.IChannelAccountRepository) *MailboxEmailService {
+	mailboxEmailService := MailboxEmailService{
+		gmailEmailService:  gmailEmailService,
+		imapEmailService:   imapEmailService,
+		interactionRepo:    interactionRepo,
+		channelAccountRepo: channelAccountRepo,
+	}
+	return &mailboxEmailService
+}
+
+// isImapInteraction tells whether the mailbox of the interaction is read over
+// IMAP. An interaction without a known mailbox uses Gmail, like before the
+// mailboxes had a provider.
+func (service *MailboxEmailService) isImapInteraction(interactionId uint) (bool, error) {
+	interaction, err := service.interactionRepo.GetInteractionById(interactionId)
+	if err != nil {
+		return false, err
+	}
+	if interaction.PlatformId == "" {
+		return false, nil
+	}
+
+	channelAccount, err := service.channelAccountRepo.GetChannelAccountByPlatformId(interaction.PlatformId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+
+	} else if err != nil {
+		return false, err
+	}
+
+	return isImapChannelAccount(channelAccount), nil
+}
+
+func (service *MailboxEmailService) ProcessWebhook(rawMessage string, prevHistoryId int) (historyId uint64, err error) {
+	return service.gmailEmailService.ProcessWebhook(rawMessage, prevHistoryId)
+}
+
+func (service *MailboxEmailService) SendEmail(interactionId uint, message string, sentBy string, attachment *presentation.OutboundAttachment) (messageId string, res *entity.Message, err error) {
+	isImap, err := service.isImapInteraction(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[MailboxEmailService][SendEmail] error when calling isImapInteraction, error: %+v", err)
+	}
+
+	if isImap {
+		return service.imapEmailService.SendEmail(interactionId, message, sentBy, attachment)
+	}
+
+	return service.gmailEmailService.SendEmail(interactionId, message, sentBy, attachment)
+}
+
+func (service *MailboxEmailService) StartEmailThread(interactionId uint, subject string, message string) (messageId string, res *entity.Message, err error) {
+	isImap, err := service.isImapInteraction(interactionId)
+	if err != nil {
+		return messageId, nil, fmt.Errorf("[MailboxEmailService][StartEmailThread] error when calling isImapInteraction, error: %+v", err)
+	}
+
+	if isImap {
+		return service.imapEmailService.StartEmailThread(interactionId, subject, message)
+	}
+
+	return service.gmailEmailService.StartEmailThread(interactionId, subject, message)
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// MessengerAdapter serves both Facebook and Instagram, which share the
+// Messenger send API and most of the webhook format.
+type MessengerAdapter struct {
+	platform           string
+	channelAccountRepo repository.IChannelAccountRepository
+	messageRepo        repository.IMessageRepository
+	reporterRepo       repository.IReporterRepository
+}
+
+func NewMessengerAdapter(platform string, channelAccountRepo repository.IChannelAccountRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository) *MessengerAdapter {
+	messengerAdapter := MessengerAdapter{
+		platform:           platform,
+		channelAccountRepo: channelAccountRepo,
+		messageRepo:        messageRepo,
+		reporterRepo:       reporterRepo,
+	}
+	return &messengerAdapter
+}
+
+func (ma *MessengerAdapter) Platform() string {
+	return ma.platform
+}
+
+func (ma *MessengerAdapter) Capabilities() ChannelCapabilities {
+	return ChannelCapabilities{
+		Webhook:   true,
+		Media:     ma.platform == enum.IG,
+		AutoReply: true,
+		Realtime:  true,
+
+		Attachments: true,
+	}
+}
+
+func (ma *MessengerAdapter) VerifyWebhook(delivery *presentation.WebhookDelivery) error {
+	return verifyMetaSignature(ma.channelAccountRepo, delivery)
+}
+
+func parseMessagingField(platformId string, messaging presentation.MessagingField) (presentation.InboundMessage, bool) {
+	metaReporterId := messaging.ReporterId.Id
+	if platformId == metaReporterId {
+		return presentation.InboundMessage{}, false
+	}
+
+	inboundMessage := presentation.InboundMessage{
+		PlatformId:      platformId,
+		InteractionType: enum.PESAN,
+		MetaReporterId:  metaReporterId,
+		MessageId:       messaging.Message.MessageId,
+		Message:         messaging.Message.MessageText,
+	}
+
+	attachments := messaging.Message.Attachments
+	if len(attachments) > 0 {
+		attachmentType := attachments[0].AttachmentType
+
+		if attachmentType == "image" || attachmentType == "video" {
+			if attachmentType == "image" {
+				inboundMessage.AttachmentType = enum.IMAGE
+			} else if attachmentType == "video" {
+				inboundMessage.AttachmentType = enum.VIDEO
+			}
+			inboundMessage.AttachmentUrl = attachments[0].AttachmentPayload.AttachmentUrl
+		}
+	}
+
+	return inboundMessage, true
+}
+
+func (ma *MessengerAdapter) ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error) {
+	if ma.platform == enum.IG {
+		return ma.parseInstagramInbound(body)
+	}
+
+	var fwr presentation.FacebookWebhookRequest
+	var inboundMessages []presentation.InboundMessage
+
+	err := json.Unmarshal(body, &fwr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range fwr.Entry {
+		if len(v.Messaging) > 0 {
+			inboundMessage, ok := parseMessagingField(v.PlatformId, v.Messaging[0])
+			if !ok {
+				continue
+			}
+			inboundMessages = append(inboundMessages, inboundMessage)
+
+		} else if len(v.Changes) > 0 {
+			postId := v.Changes[0].Value.PostId
+			commentId := v.Changes[0].Value.CommentId
+
+			inboundMessage := presentation.InboundMessage{
+				PlatformId:      v.PlatformId,
+				InteractionType: enum.MENTION,
+				MentionMediaId:  postId,
+				MentionMediaUrl: fmt.Sprintf("https://www.facebook.com/%s", postId),
+				MessageId:       postId,
+				Message:         v.Changes[0].Value.Message,
+			}
+			if commentId != "" {
+				inboundMessage.MessageId = commentId
+			}
+
+			inboundMessages = append(inboundMessages, inboundMessage)
+		}
+	}
+
+	return inboundMessages, nil
+}
+
+// parseInstagramInbound leaves the text and permalink of mentions to
+// FetchMedia, Instagram only sends their ids.
+func (ma *MessengerAdapter) parseInstagramInbound(body []byte) ([]presentation.InboundMessage, error) {
+	var iwr presentation.InstagramWebhookRequest
+	var inboundMessages []presentation.InboundMessage
+
+	err := json.Unmarshal(body, &iwr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range iwr.Entry {
+		if len(v.Messaging) > 0 {
+			inboundMessage, ok := parseMessagingField(v.PlatformId, v.Messaging[0])
+			if !ok {
+				continue
+			}
+			inboundMessages = append(inboundMessages, inboundMessage)
+
+		} else if len(v.Changes) > 0 {
+			mediaId := v.Changes[0].Value.MediaId
+			commentId := v.Changes[0].Value.CommentId
+
+			inboundMessage := presentation.InboundMessage{
+				PlatformId:      v.PlatformId,
+				InteractionType: enum.MENTION,
+				MentionMediaId:  mediaId,
+				MessageId:       mediaId,
+				MediaId:         mediaId,
+			}
+			if commentId != "" {
+				inboundMessage.MessageId = commentId
+			}
+
+			inboundMessages = append(inboundMessages, inboundMessage)
+		}
+	}
+
+	return inboundMessages, nil
+}
+
+func (ma *MessengerAdapter) FetchMedia(inboundMessage *presentation.InboundMessage) error {
+	if ma.platform != enum.IG || inboundMessage.InteractionType != enum.MENTION {
+		return nil
+	}
+
+	isComment := inboundMessage.MessageId != inboundMessage.MediaId
+
+	path := fmt.Sprintf("/%s/%s", viper.GetString("Meta.API_VERSION"), inboundMessage.PlatformId)
+	params := url.Values{}
+	if isComment {
+		params.Add("fields", fmt.Sprintf("mentioned_comment.comment_id(%s){text,media{id,permalink}}", inboundMessage.MessageId))
+	} else {
+		params.Add("fields", fmt.Sprintf("mentioned_media.media_id(%s){caption,permalink}", inboundMessage.MediaId))
+	}
+	params.Add("access_token", viper.GetString("Meta.IG_ACCESS_TOKEN"))
+
+	reqUrl := url.URL{
+		Scheme:   "https",
+		Host:     "graph.facebook.com",
+		Path:     path,
+		RawQuery: params.Encode(),
+	}
+
+	response, err := request.GetRequest(reqUrl, "")
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		bodyBytes, err := io.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		bodyString := string(bodyBytes)
+		logger.Info(response.Status)
+		logger.Info(bodyString)
+		return errors.New("Meta response not 200")
+	}
+
+	if isComment {
+		mentionCommentData := &presentation.GetMentionCommentDetailResp{}
+		err = json.NewDecoder(response.Body).Decode(mentionCommentData)
+		if err != nil {
+			return err
+		}
+
+		inboundMessage.MentionMediaUrl = mentionCommentData.MentionedComment.Media.Permalink
+		inboundMessage.Message = mentionCommentData.MentionedComment.Text
+
+	} else {
+		mentionMediaData := &presentation.GetMentionMediaDetailResp{}
+		err = json.NewDecoder(response.Body).Decode(mentionMediaData)
+		if err != nil {
+			return err
+		}
+
+		inboundMessage.MentionMediaUrl = mentionMediaData.MentionedMedia.Permalink
+		inboundMessage.Message = mentionMediaData.MentionedMedia.Caption
+	}
+
+	return nil
+}
+
+func (ma *MessengerAdapter) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	result := make(map[string]interface{})
+	var access_token string
+
+	if channelAccount.ID == 0 {
+		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+	}
+
+	if channelAccount.FaceboookPageId == "" {
+		return nil, nil, enum.PLATFORM_ID_NOT_SET
+	}
+
+	reporter, err := ma.reporterRepo.GetReporterByReporterId(msmr.ReporterId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ma.platform == enum.FACEBOOK {
+		if msmr.PlatformId != channelAccount.FaceboookPageId {
+			return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+		}
+		if channelAccount.FacebookAccessToken == "" {
+			return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+		}
+		access_token = channelAccount.FacebookAccessToken
+	} else {
+		if channelAccount.InstagramId == "" {
+			return nil, nil, enum.PLATFORM_ID_NOT_SET
+		}
+
+		if msmr.PlatformId != channelAccount.InstagramId {
+			return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+		}
+		if channelAccount.InstagramAccessToken == "" {
+			return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+		}
+		access_token = channelAccount.InstagramAccessToken
+	}
+
+	path := fmt.Sprintf("/%s/%s/messages", viper.GetString("Meta.API_VERSION"), channelAccount.FaceboookPageId)
+	params := url.Values{}
+	params.Add("access_token", access_token)
+
+	reqUrl := url.URL{
+		Scheme:   "https",
+		Host:     "graph.facebook.com",
+		Path:     path,
+		RawQuery: params.Encode(),
+	}
+
+	// a message is either a text or an attachment, the text of a message with
+	// an attachment goes first so it reads as its caption
+	var messageData *presentation.MessengerSendMessageMetaResponse
+
+	text := outboundMessageText(msmr)
+	if text != "" || msmr.Attachment == nil {
+		body := presentation.MessengerSendMessageMetaRequest{
+			Recipient:     presentation.IdField{Id: reporter.MetaReporterId},
+			MessagingType: "MESSAGE_TAG",
+			Tag:           "HUMAN_AGENT",
+			Message:       presentation.MessageSendMetaField{MessageText: text},
+		}
+
+		response, err := request.PostRequest(reqUrl, body, "")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		messageData, err = decodeMessengerSendResponse(response)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if msmr.Attachment != nil {
+		messageData, err = ma.sendAttachment(reqUrl, reporter.MetaReporterId, msmr)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sendedMessage := entity.Message{
+		InteractionId:    msmr.InteractionId,
+		SenderId:         msmr.PlatformId,
+		RecipientId:      messageData.ReporterId,
+		MetaMessageId:    messageData.MessageId,
+		Message:          msmr.Message,
+		MessageTimestamp: time.Now(),
+		SentBy:           outboundSentBy(msmr),
+		IsRead:           false,
+		AttachmentType:   msmr.AttachmentType,
+		AttachmentUrl:    msmr.AttachmentUrl,
+	}
+
+	message, err := ma.messageRepo.CreateMessage(&sendedMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result["message_id"] = message.MetaMessageId
+
+	return result, message, nil
+}
+
+// messengerAttachmentType is the attachment type of the Send API, files that
+// are not media are sent as file.
+func messengerAttachmentType(attachmentType string) string {
+	switch attachmentType {
+	case enum.IMAGE:
+		return "image"
+	case enum.VIDEO:
+		return "video"
+	case enum.AUDIO:
+		return "audio"
+	}
+	return "file"
+}
+
+// sendAttachment uploads the file together with the message through the
+// attachment upload of the Send API.
+func (ma *MessengerAdapter) sendAttachment(reqUrl url.URL, recipientId string, msmr *presentation.MetaSendMessageRequest) (*presentation.MessengerSendMessageMetaResponse, error) {
+	recipient, err := json.Marshal(presentation.IdField{Id: recipientId})
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentMessage, err := json.Marshal(presentation.MessengerAttachmentMetaField{
+		Attachment: presentation.MessengerAttachmentTypeField{
+			Type: messengerAttachmentType(msmr.AttachmentType),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{
+		"recipient":      string(recipient),
+		"messaging_type": "MESSAGE_TAG",
+		"tag":            "HUMAN_AGENT",
+		"message":        string(attachmentMessage),
+	}
+
+	response, err := request.PostMultipartRequest(reqUrl, fields, "filedata", msmr.Attachment.Name, msmr.Attachment.ContentType, msmr.Attachment.Content, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMessengerSendResponse(response)
+}
+
+func decodeMessengerSendResponse(response *http.Response) (*presentation.MessengerSendMessageMetaResponse, error) {
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, metaResponseError(response)
+	}
+
+	messageData := &presentation.MessengerSendMessageMetaResponse{}
+	err := json.NewDecoder(response.Body).Decode(messageData)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageData, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type metaWebhookEntries struct {
+	Entry []struct {
+		PlatformId string `json:"id"`
+	} `json:"entry"`
+}
+
+// getMetaAppSecret returns the app secret the delivery is signed with, the one
+// of the channel account owning its entries or Meta.APP_SECRET for accounts
+// that have none. The body is not verified yet, so every entry is looked up:
+// a delivery whose entries belong to accounts with different secrets is
+// refused, otherwise an account could sign entries of another one with its
+// own secret.
+func getMetaAppSecret(channelAccountRepo repository.IChannelAccountRepository, body []byte) (string, error) {
+	var entries metaWebhookEntries
+	appSecret := viper.GetString("Meta.APP_SECRET")
+
+	err := json.Unmarshal(body, &entries)
+	if err != nil {
+		return appSecret, nil
+	}
+
+	// ids of the accounts with their own secret, 0 for Meta.APP_SECRET
+	secretOwners := make(map[uint]bool)
+	for _, entry := range entries.Entry {
+		var secretOwner uint
+
+		if entry.PlatformId != "" {
+			channelAccount, err := channelAccountRepo.GetChannelAccountByPlatformId(entry.PlatformId)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", err
+			}
+
+			if err == nil && channelAccount.MetaAppSecret != "" {
+				secretOwner = channelAccount.ID
+				appSecret = channelAccount.MetaAppSecret
+			}
+		}
+
+		secretOwners[secretOwner] = true
+	}
+
+	if len(secretOwners) > 1 {
+		return "", enum.WEBHOOK_ENTRIES_NOT_MATCH
+	}
+
+	return appSecret, nil
+}
+
+// verifyMetaSignature checks the X-Hub-Signature-256 header, the HMAC-SHA256
+// of the raw body keyed with the app secret, sent as "sha256=<hex>".
+func verifyMetaSignature(channelAccountRepo repository.IChannelAccountRepository, delivery *presentation.WebhookDelivery) error {
+	if delivery.Signature == "" {
+		return enum.WEBHOOK_SIGNATURE_MISSING
+	}
+
+	appSecret, err := getMetaAppSecret(channelAccountRepo, delivery.Body)
+	if err != nil {
+		return err
+	}
+	if appSecret == "" {
+		return enum.WEBHOOK_SECRET_NOT_SET
+	}
+
+	if !strings.HasPrefix(delivery.Signature, "sha256=") {
+		return enum.INVALID_WEBHOOK_SIGNATURE
+	}
+
+	signatureBytes, err := hex.DecodeString(strings.TrimPrefix(delivery.Signature, "sha256="))
+	if err != nil {
+		return enum.INVALID_WEBHOOK_SIGNATURE
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(delivery.Body)
+	if !hmac.Equal(signatureBytes, mac.Sum(nil)) {
+		return enum.INVALID_WEBHOOK_SIGNATURE
+	}
+
+	return nil
+}
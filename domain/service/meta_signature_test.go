@@ -0,0 +1,155 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// channelAccountRepoByPlatformId knows the channel accounts of its map, keyed
+// by page, Instagram or WhatsApp business id.
+type channelAccountRepoByPlatformId struct {
+	repository.IChannelAccountRepository
+	channelAccounts map[string]*entity.ChannelAccount
+}
+
+func (r channelAccountRepoByPlatformId) GetChannelAccountByPlatformId(platformId string) (*entity.ChannelAccount, error) {
+	channelAccount, found := r.channelAccounts[platformId]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return channelAccount, nil
+}
+
+func metaSignature(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyMetaSignature(t *testing.T) {
+	viper.Set("Meta.APP_SECRET", "app-secret")
+	defer viper.Set("Meta.APP_SECRET", nil)
+
+	channelAccountRepo := channelAccountRepoByPlatformId{channelAccounts: map[string]*entity.ChannelAccount{
+		"page-a":   {Model: gorm.Model{ID: 1}, MetaAppSecret: "secret-a"},
+		"page-a2":  {Model: gorm.Model{ID: 1}, MetaAppSecret: "secret-a"},
+		"page-b":   {Model: gorm.Model{ID: 2}, MetaAppSecret: "secret-b"},
+		"page-c":   {Model: gorm.Model{ID: 3}},
+		"waba-d":   {Model: gorm.Model{ID: 4}},
+		"page-a-2": {Model: gorm.Model{ID: 5}, MetaAppSecret: "secret-a"},
+	}}
+
+	tests := []struct {
+		name       string
+		body       string
+		secret     string
+		wantSecret string
+		wantErr    error
+	}{
+		{
+			name:       "account with its own secret",
+			body:       `{"entry":[{"id":"page-a"}]}`,
+			secret:     "secret-a",
+			wantSecret: "secret-a",
+		},
+		{
+			name:       "account without secret uses the app secret",
+			body:       `{"entry":[{"id":"page-c"}]}`,
+			secret:     "app-secret",
+			wantSecret: "app-secret",
+		},
+		{
+			name:       "unknown id uses the app secret",
+			body:       `{"entry":[{"id":"page-unknown"}]}`,
+			secret:     "app-secret",
+			wantSecret: "app-secret",
+		},
+		{
+			name:       "entries of the same account",
+			body:       `{"entry":[{"id":"page-a"},{"id":"page-a2"}]}`,
+			secret:     "secret-a",
+			wantSecret: "secret-a",
+		},
+		{
+			name:       "entries of accounts without secret",
+			body:       `{"entry":[{"id":"page-c"},{"id":"waba-d"},{"id":"page-unknown"}]}`,
+			secret:     "app-secret",
+			wantSecret: "app-secret",
+		},
+		{
+			name:    "entry of another account signed with the own secret",
+			body:    `{"entry":[{"id":"page-a"},{"id":"page-b"}]}`,
+			secret:  "secret-a",
+			wantErr: enum.WEBHOOK_ENTRIES_NOT_MATCH,
+		},
+		{
+			name:    "entry of an account without secret signed with an account secret",
+			body:    `{"entry":[{"id":"page-a"},{"id":"page-c"}]}`,
+			secret:  "secret-a",
+			wantErr: enum.WEBHOOK_ENTRIES_NOT_MATCH,
+		},
+		{
+			name:    "entry of an account sharing the secret of another one",
+			body:    `{"entry":[{"id":"page-a"},{"id":"page-a-2"}]}`,
+			secret:  "secret-a",
+			wantErr: enum.WEBHOOK_ENTRIES_NOT_MATCH,
+		},
+		{
+			name:    "signed with the secret of another account",
+			body:    `{"entry":[{"id":"page-b"}]}`,
+			secret:  "secret-a",
+			wantErr: enum.INVALID_WEBHOOK_SIGNATURE,
+		},
+		{
+			name:    "signed with the app secret for an account with its own",
+			body:    `{"entry":[{"id":"page-a"}]}`,
+			secret:  "app-secret",
+			wantErr: enum.INVALID_WEBHOOK_SIGNATURE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil || errors.Is(tt.wantErr, enum.INVALID_WEBHOOK_SIGNATURE) {
+				secret, err := getMetaAppSecret(channelAccountRepo, []byte(tt.body))
+				if err != nil {
+					t.Fatalf("getMetaAppSecret error = %+v", err)
+				}
+				if tt.wantSecret != "" && secret != tt.wantSecret {
+					t.Errorf("getMetaAppSecret = %q, want %q", secret, tt.wantSecret)
+				}
+			}
+
+			delivery := presentation.WebhookDelivery{
+				Signature: metaSignature(tt.secret, tt.body),
+				Body:      []byte(tt.body),
+			}
+
+			err := verifyMetaSignature(channelAccountRepo, &delivery)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyMetaSignature error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyMetaSignatureWithoutSignature(t *testing.T) {
+	delivery := presentation.WebhookDelivery{
+		Body: []byte(`{"entry":[{"id":"page-a"}]}`),
+	}
+
+	err := verifyMetaSignature(channelAccountRepoByPlatformId{}, &delivery)
+	if !errors.Is(err, enum.WEBHOOK_SIGNATURE_MISSING) {
+		t.Errorf("verifyMetaSignature error = %v, want %v", err, enum.WEBHOOK_SIGNATURE_MISSING)
+	}
+}
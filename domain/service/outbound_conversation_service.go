@@ -0,0 +1,262 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// messengerHumanAgentWindow is how long the HUMAN_AGENT tag used to send on
+// Messenger and Instagram is accepted after the last message of the reporter.
+const messengerHumanAgentWindow = 7 * 24 * time.Hour
+
+type OutboundConversationService struct {
+	interactionRepo         repository.IinteractionRepository
+	messageRepo             repository.IMessageRepository
+	reporterRepo            repository.IReporterRepository
+	channelAccountRepo      repository.IChannelAccountRepository
+	capacityService         ICapacityService
+	emailService            IEmailService
+	interactionService      IInteractionService
+	whatsappTemplateService IWhatsappTemplateService
+}
+
+type IOutboundConversationService interface {
+	StartOutboundConversation(*presentation.StartOutboundConversationRequest, string, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
+}
+
+func NewOutboundConversationService(interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, channelAccountRepo repository.IChannelAccountRepository, capacityService ICapacityService, emailService IEmailService, interactionService IInteractionService, whatsappTemplateService IWhatsappTemplateService) *OutboundConversationService {
+	outboundConversationService := OutboundConversationService{
+		interactionRepo:         interactionRepo,
+		messageRepo:             messageRepo,
+		reporterRepo:            reporterRepo,
+		channelAccountRepo:      channelAccountRepo,
+		capacityService:         capacityService,
+		emailService:            emailService,
+		interactionService:      interactionService,
+		whatsappTemplateService: whatsappTemplateService,
+	}
+	return &outboundConversationService
+}
+
+func channelAccountPlatformId(channelAccount *entity.ChannelAccount, platform string) string {
+	switch platform {
+	case enum.WA:
+		return channelAccount.WhatsappBusinessId
+	case enum.FACEBOOK:
+		return channelAccount.FaceboookPageId
+	case enum.IG:
+		return channelAccount.InstagramId
+	case enum.TELEGRAM:
+		return channelAccount.TelegramBotId
+	case enum.EMAIL:
+		return channelAccount.EmailAddress
+	}
+	return ""
+}
+
+// outboundChannelAccount checks that the conversation can be started from the
+// agent's own channel account, the only one its replies go through. The
+// reporter must have last talked to its page, number or bot on the platform,
+// except on WhatsApp where a reporter that never wrote to us can be reached
+// too, the other platforms only allow messaging users who did.
+func (ocs *OutboundConversationService) outboundChannelAccount(reporter *entity.Reporter, platform string, channelAccount *entity.ChannelAccount) (*entity.ChannelAccount, error) {
+	if reporter.MetaReporterId == "" {
+		return nil, enum.REPORTER_NOT_REACHABLE
+	}
+
+	if channelAccount.ID == 0 {
+		return nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+	}
+
+	lastInteraction, err := ocs.interactionRepo.GetLatestInteractionByReporterIdAndPlatform(reporter.ID, platform)
+	if (lastInteraction == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		if platform != enum.WA {
+			return nil, enum.REPORTER_NOT_REACHABLE
+		}
+
+		return channelAccount, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the reporter is known to another channel account, which is out of the
+	// agent's reach
+	if channelAccountPlatformId(channelAccount, platform) != lastInteraction.PlatformId {
+		return nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+	}
+
+	return channelAccount, nil
+}
+
+// checkOutboundMessagingWindow refuses free text once the platform stopped
+// accepting it, WhatsApp templates can be sent at any time. The window is kept
+// per business account, so only the messages the reporter sent to platformId
+// count.
+func (ocs *OutboundConversationService) checkOutboundMessagingWindow(socr *presentation.StartOutboundConversationRequest, reporter *entity.Reporter, platformId string) error {
+	var window time.Duration
+	windowErr := enum.MESSAGING_WINDOW_EXPIRED
+
+	switch socr.Platform {
+	case enum.WA:
+		if socr.TemplateName != "" {
+			return nil
+		}
+		window = whatsappCustomerServiceWindow
+		windowErr = enum.WHATSAPP_WINDOW_EXPIRED
+	case enum.FACEBOOK, enum.IG:
+		window = messengerHumanAgentWindow
+	default:
+		return nil
+	}
+
+	lastMessage, err := ocs.messageRepo.GetLatestReporterMessage(reporter.MetaReporterId, platformId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return windowErr
+
+	} else if err != nil {
+		return err
+	}
+
+	if time.Since(lastMessage.MessageTimestamp) > window {
+		return windowErr
+	}
+
+	return nil
+}
+
+// StartOutboundConversation opens an interaction with a known reporter that is
+// already claimed by the agent and sends its first message. Everything the
+// platform could refuse is checked before the interaction is created, and the
+// interaction is removed again when the first message still fails.
+func (ocs *OutboundConversationService) StartOutboundConversation(socr *presentation.StartOutboundConversationRequest, userId string, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	channelAccount, err := loadChannelAccount(ocs.channelAccountRepo, channelAccount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[OutboundConversationService][StartOutboundConversation] error when calling loadChannelAccount, error: %+v", err)
+	}
+
+	reporter, err := ocs.reporterRepo.GetReporterByReporterId(socr.ReporterId)
+	if (reporter == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var platformId string
+	switch socr.Platform {
+	case enum.EMAIL:
+		if reporter.Email == "" {
+			return nil, nil, enum.REPORTER_NOT_REACHABLE
+		}
+
+		// the email is sent from the mailbox of the agent's channel account
+		platformId = channelAccount.EmailAddress
+
+	case enum.WA, enum.FACEBOOK, enum.IG, enum.TELEGRAM:
+		channelAccount, err = ocs.outboundChannelAccount(reporter, socr.Platform, channelAccount)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		platformId = channelAccountPlatformId(channelAccount, socr.Platform)
+		if platformId == "" {
+			return nil, nil, enum.PLATFORM_ID_NOT_SET
+		}
+
+	default:
+		return nil, nil, enum.INVALID_PLATFORM
+	}
+
+	ongoingInteraction, err := ocs.interactionRepo.GetOngoingInteractionByReporterId(reporter.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	if ongoingInteraction != nil {
+		return nil, nil, enum.INTERACTION_ALREADY_ONGOING
+	}
+
+	err = ocs.capacityService.CheckAgentCapacity(userId, socr.Platform)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = ocs.checkOutboundMessagingWindow(socr, reporter, platformId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	newInteraction := entity.Interaction{
+		PlatformId:      platformId,
+		ReporterId:      reporter.ID,
+		AgentId:         userId,
+		Status:          enum.IN_PROGRESS,
+		Platform:        socr.Platform,
+		InteractionType: enum.PESAN,
+		ClaimedAt:       &now,
+	}
+
+	interaction, err := ocs.interactionRepo.CreateInteraction(&newInteraction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result map[string]interface{}
+	var message *entity.Message
+
+	switch {
+	case socr.Platform == enum.EMAIL:
+		var messageId string
+		messageId, message, err = ocs.emailService.StartEmailThread(interaction.ID, socr.Subject, socr.Message)
+		result = map[string]interface{}{
+			"message_id": messageId,
+		}
+
+	case socr.TemplateName != "":
+		swtr := presentation.SendWhatsappTemplateRequest{
+			InteractionId: interaction.ID,
+			PlatformId:    platformId,
+			ReporterId:    reporter.ID,
+			TemplateName:  socr.TemplateName,
+			Language:      socr.Language,
+			Parameters:    socr.Parameters,
+		}
+		result, message, err = ocs.whatsappTemplateService.SendWhatsappTemplate(&swtr, channelAccount)
+
+	default:
+		msmr := presentation.MetaSendMessageRequest{
+			InteractionId: interaction.ID,
+			PlatformId:    platformId,
+			ReporterId:    reporter.ID,
+			Message:       socr.Message,
+			Platform:      socr.Platform,
+			SentBy:        enum.AGENT,
+		}
+		result, message, err = ocs.interactionService.SendMessage(&msmr, channelAccount)
+	}
+	if err != nil {
+		deleteErr := ocs.interactionRepo.DeleteInteraction(interaction.ID)
+		if deleteErr != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Start Outbound Conversation] Delete interaction %d: %+v", interaction.ID, deleteErr))
+		}
+		return nil, nil, err
+	}
+
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	result["interaction_id"] = interaction.ID
+	result["agent_id"] = interaction.AgentId
+	result["interaction_status"] = interaction.Status
+
+	return result, message, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RejectedWebhookService struct {
+	rejectedWebhookRepo repository.IRejectedWebhookRepository
+}
+
+type IRejectedWebhookService interface {
+	GetRejectedWebhookList(map[string]interface{}) (map[string]interface{}, error)
+}
+
+func NewRejectedWebhookService(rejectedWebhookRepo repository.IRejectedWebhookRepository) *RejectedWebhookService {
+	rejectedWebhookService := RejectedWebhookService{
+		rejectedWebhookRepo: rejectedWebhookRepo,
+	}
+	return &rejectedWebhookService
+}
+
+func (rws *RejectedWebhookService) GetRejectedWebhookList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	rejectedWebhookList, err := rws.rejectedWebhookRepo.GetRejectedWebhookList(filters)
+	if (rejectedWebhookList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["rejected_webhook_list"] = rejectedWebhookList
+
+	return result, nil
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/config"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type RoutingService struct {
+	routingPolicyRepo  repository.IRoutingPolicyRepository
+	channelAccountRepo repository.IChannelAccountRepository
+	interactionRepo    repository.IinteractionRepository
+	userRepo           repository.IUserRepository
+	chatbotFlowRepo    repository.IChatbotFlowRepository
+	capacityService    ICapacityService
+}
+
+type IRoutingService interface {
+	AssignInteraction(*entity.Interaction) (*entity.Interaction, error)
+
+	CreateRoutingPolicy(*presentation.RoutingPolicyModel) (map[string]interface{}, error)
+	GetRoutingPolicyList() (map[string]interface{}, error)
+	GetRoutingPolicyById(uint) (map[string]interface{}, error)
+	UpdateRoutingPolicy(*presentation.UpdateRoutingPolicyModel) (map[string]interface{}, error)
+	DeleteRoutingPolicyById(*presentation.DeleteRoutingPolicyModel) (map[string]interface{}, error)
+}
+
+func NewRoutingService(routingPolicyRepo repository.IRoutingPolicyRepository, channelAccountRepo repository.IChannelAccountRepository, interactionRepo repository.IinteractionRepository, userRepo repository.IUserRepository, chatbotFlowRepo repository.IChatbotFlowRepository, capacityService ICapacityService) *RoutingService {
+	routingService := RoutingService{
+		routingPolicyRepo:  routingPolicyRepo,
+		channelAccountRepo: channelAccountRepo,
+		interactionRepo:    interactionRepo,
+		userRepo:           userRepo,
+		chatbotFlowRepo:    chatbotFlowRepo,
+		capacityService:    capacityService,
+	}
+	return &routingService
+}
+
+// AssignInteraction picks an agent for a freshly created UNCLAIMED interaction
+// using the routing policy of the channel account that owns it. When the
+// channel account has an active chatbot flow the interaction goes to the bot
+// first instead. Interactions without an applicable policy or candidate agent
+// are returned untouched.
+func (rs *RoutingService) AssignInteraction(interaction *entity.Interaction) (*entity.Interaction, error) {
+	if interaction.Status != enum.UNCLAIMED || interaction.AgentId != "" {
+		return interaction, nil
+	}
+
+	botInteraction, err := rs.startChatbotFlow(interaction)
+	if err != nil {
+		return interaction, err
+	}
+	if botInteraction != nil {
+		return botInteraction, nil
+	}
+
+	policy, err := rs.getRoutingPolicy(interaction)
+	if policy == nil || err != nil {
+		return interaction, err
+	}
+
+	agentIds, err := rs.getCandidateAgentIds(policy, interaction.Platform)
+	if len(agentIds) == 0 || err != nil {
+		return interaction, err
+	}
+
+	var agentId string
+	switch policy.Strategy {
+	case enum.ROUND_ROBIN:
+		agentId = pickRoundRobinAgent(agentIds, policy.LastAssignedAgentId)
+	case enum.LEAST_LOADED:
+		agentId, err = rs.pickLeastLoadedAgent(agentIds)
+	case enum.STICKY:
+		agentId, err = rs.pickStickyAgent(interaction, agentIds)
+	}
+	if agentId == "" || err != nil {
+		return interaction, err
+	}
+
+	newInteraction := entity.Interaction{
+		AgentId:         agentId,
+		Status:          enum.IN_PROGRESS,
+		RoutingStrategy: policy.Strategy,
+	}
+
+	assignedInteraction, err := rs.interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+	if err != nil {
+		return interaction, err
+	}
+
+	if policy.Strategy == enum.ROUND_ROBIN {
+		err = rs.routingPolicyRepo.UpdateLastAssignedAgentId(policy.ID, agentId)
+		if err != nil {
+			return assignedInteraction, err
+		}
+	}
+
+	return assignedInteraction, nil
+}
+
+// assignNewInteraction runs the routing engine for a newly created interaction.
+// A routing failure must not break ingestion, so the interaction simply stays
+// UNCLAIMED and the error is logged. The agent it is routed to is notified.
+func assignNewInteraction(routingService IRoutingService, interaction *entity.Interaction) *entity.Interaction {
+	assignedInteraction, err := routingService.AssignInteraction(interaction)
+	if err != nil {
+		logger.Info(fmt.Sprintf("[FAILED][Assign Interaction] Interaction %d stays unclaimed: %+v", interaction.ID, err))
+	}
+
+	if assignedInteraction.AgentId != "" && assignedInteraction.AgentId != interaction.AgentId {
+		err = WebsocketAssignmentService(*assignedInteraction)
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Assign Interaction] Notify agent %s of interaction %d: %+v", assignedInteraction.AgentId, assignedInteraction.ID, err))
+		}
+	}
+
+	return assignedInteraction
+}
+
+type AssignmentToSend struct {
+	Action     string                                         `json:"action"`
+	Assignment presentation.InteractionAssignmentNotification `json:"assignment"`
+}
+
+// WebsocketAssignmentService notifies the agent an interaction was routed to.
+// The websocket server forwards it to the connection of the agent only.
+func WebsocketAssignmentService(interaction entity.Interaction) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	connectionId := fmt.Sprintf("assignment-%d", interaction.ID)
+	query := fmt.Sprintf("user_id=%s&room_id=%d", connectionId, interaction.ID)
+	client, err := NewWebSocketClient(host, channel, connectionId, query)
+	if err != nil {
+		return err
+	}
+
+	send := AssignmentToSend{
+		Action: "interaction-assigned",
+		Assignment: presentation.InteractionAssignmentNotification{
+			InteractionId:   interaction.ID,
+			AgentId:         interaction.AgentId,
+			Platform:        interaction.Platform,
+			RoutingStrategy: interaction.RoutingStrategy,
+			AssignedAt:      interaction.UpdatedAt,
+		},
+	}
+	err = client.Write(send)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getInteractionChannelAccount resolves the channel account that owns the
+// interaction, nil when it has none. Email interactions are owned by the
+// channel account of the mailbox they were received on.
+func getInteractionChannelAccount(channelAccountRepo repository.IChannelAccountRepository, interaction *entity.Interaction) (*entity.ChannelAccount, error) {
+	var channelAccount *entity.ChannelAccount
+	var err error
+
+	if interaction.Platform == enum.LIVE_CHAT {
+		channelAccount, err = channelAccountRepo.GetLiveChatChannelAccount()
+	} else if interaction.PlatformId != "" {
+		channelAccount, err = channelAccountRepo.GetChannelAccountByPlatformId(interaction.PlatformId)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return channelAccount, nil
+}
+
+// startChatbotFlow hands a new WhatsApp or live chat message interaction to the
+// active chatbot flow of its channel account by moving it to BOT. The bot runs
+// once per interaction, so an interaction handed back by the bot is routed
+// normally. Returns nil when no flow applies.
+func (rs *RoutingService) startChatbotFlow(interaction *entity.Interaction) (*entity.Interaction, error) {
+	if interaction.InteractionType != enum.PESAN || (interaction.Platform != enum.WA && interaction.Platform != enum.LIVE_CHAT) {
+		return nil, nil
+	}
+
+	_, err := rs.chatbotFlowRepo.GetChatbotSessionByInteractionId(interaction.ID)
+	if err == nil {
+		return nil, nil
+
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	channelAccount, err := getInteractionChannelAccount(rs.channelAccountRepo, interaction)
+	if channelAccount == nil || err != nil {
+		return nil, err
+	}
+
+	chatbotFlow, err := rs.chatbotFlowRepo.GetActiveChatbotFlow(channelAccount.ID, interaction.Platform)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		chatbotFlow, err = rs.chatbotFlowRepo.GetActiveChatbotFlow(0, interaction.Platform)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	newChatbotSession := entity.ChatbotSession{
+		InteractionId: interaction.ID,
+		ChatbotFlowId: chatbotFlow.ID,
+	}
+
+	_, err = rs.chatbotFlowRepo.CreateChatbotSession(&newChatbotSession)
+	if err != nil {
+		return nil, err
+	}
+
+	newInteraction := entity.Interaction{
+		Status: enum.BOT,
+	}
+
+	return rs.interactionRepo.UpdateInteraction(interaction.ID, &newInteraction)
+}
+
+// getRoutingPolicy returns the routing policy of the channel account of the
+// interaction, falling back to the global policy (channel account 0).
+func (rs *RoutingService) getRoutingPolicy(interaction *entity.Interaction) (*entity.RoutingPolicy, error) {
+	var channelAccountId uint
+
+	channelAccount, err := getInteractionChannelAccount(rs.channelAccountRepo, interaction)
+	if err != nil {
+		return nil, err
+	}
+	if channelAccount != nil {
+		channelAccountId = channelAccount.ID
+	}
+
+	policy, err := rs.routingPolicyRepo.GetRoutingPolicyByChannelAccountId(channelAccountId)
+	if errors.Is(err, gorm.ErrRecordNotFound) && channelAccountId != 0 {
+		policy, err = rs.routingPolicyRepo.GetRoutingPolicyByChannelAccountId(0)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// getCandidateAgentIds lists the agents of the policy that still have capacity
+// left for the platform of the interaction.
+func (rs *RoutingService) getCandidateAgentIds(policy *entity.RoutingPolicy, platform string) ([]string, error) {
+	var agentIds []string
+	filters := make(map[string]interface{})
+
+	if policy.AgentIds != "" {
+		filters["agent_ids"] = strings.Split(policy.AgentIds, ",")
+	}
+
+	agentList, err := rs.userRepo.GetAgentList(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range agentList {
+		err = rs.capacityService.CheckAgentCapacity(v.ID, platform)
+		if errors.Is(err, enum.AGENT_CAPACITY_EXCEEDED) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		agentIds = append(agentIds, v.ID)
+	}
+	sort.Strings(agentIds)
+
+	return agentIds, nil
+}
+
+func pickRoundRobinAgent(agentIds []string, lastAssignedAgentId string) string {
+	for i, v := range agentIds {
+		if v > lastAssignedAgentId {
+			return agentIds[i]
+		}
+	}
+
+	return agentIds[0]
+}
+
+func (rs *RoutingService) pickLeastLoadedAgent(agentIds []string) (string, error) {
+	var selectedAgentId string
+	var lowestCount int64
+
+	for _, v := range agentIds {
+		activeInteractionCount, err := rs.interactionRepo.GetActiveInteractionCount(v)
+		if err != nil {
+			return "", err
+		}
+
+		if selectedAgentId == "" || activeInteractionCount < lowestCount {
+			selectedAgentId = v
+			lowestCount = activeInteractionCount
+		}
+	}
+
+	return selectedAgentId, nil
+}
+
+func (rs *RoutingService) pickStickyAgent(interaction *entity.Interaction, agentIds []string) (string, error) {
+	if interaction.ReporterId != 0 {
+		previousInteraction, err := rs.interactionRepo.GetLatestAssignedInteractionByReporterId(interaction.ReporterId)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+
+		if previousInteraction != nil {
+			for _, v := range agentIds {
+				if v == previousInteraction.AgentId {
+					return v, nil
+				}
+			}
+		}
+	}
+
+	return rs.pickLeastLoadedAgent(agentIds)
+}
+
+func (rs *RoutingService) CreateRoutingPolicy(rpm *presentation.RoutingPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newRoutingPolicy := entity.RoutingPolicy{
+		ChannelAccountId: rpm.ChannelAccountId,
+		Strategy:         rpm.Strategy,
+		AgentIds:         rpm.AgentIds,
+	}
+
+	routingPolicy, err := rs.routingPolicyRepo.CreateRoutingPolicy(&newRoutingPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	result["routing_policy"] = routingPolicy
+
+	return result, nil
+}
+
+func (rs *RoutingService) GetRoutingPolicyList() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	routingPolicyList, err := rs.routingPolicyRepo.GetRoutingPolicyList()
+	if (routingPolicyList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["routing_policy_list"] = routingPolicyList
+
+	return result, nil
+}
+
+func (rs *RoutingService) GetRoutingPolicyById(routingPolicyId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	routingPolicy, err := rs.routingPolicyRepo.GetRoutingPolicyById(routingPolicyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["routing_policy"] = routingPolicy
+
+	return result, nil
+}
+
+func (rs *RoutingService) UpdateRoutingPolicy(urpm *presentation.UpdateRoutingPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newRoutingPolicy := entity.RoutingPolicy{
+		Strategy: urpm.Strategy,
+		AgentIds: urpm.AgentIds,
+	}
+
+	routingPolicy, err := rs.routingPolicyRepo.UpdateRoutingPolicy(urpm.RoutingPolicyId, &newRoutingPolicy)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["routing_policy"] = routingPolicy
+
+	return result, nil
+}
+
+func (rs *RoutingService) DeleteRoutingPolicyById(drpm *presentation.DeleteRoutingPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	err := rs.routingPolicyRepo.DeleteRoutingPolicy(drpm.RoutingPolicyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/config"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const defaultNearBreachPercent = 80
+
+type SlaService struct {
+	slaPolicyRepo   repository.ISlaPolicyRepository
+	interactionRepo repository.IinteractionRepository
+	messageRepo     repository.IMessageRepository
+}
+
+type ISlaService interface {
+	GetInteractionSla([]entity.Interaction) (map[uint]*presentation.InteractionSla, error)
+	CheckSlaBreaches() error
+	StartSlaScheduler()
+
+	CreateSlaPolicy(*presentation.SlaPolicyModel) (map[string]interface{}, error)
+	GetSlaPolicyList() (map[string]interface{}, error)
+	UpdateSlaPolicy(*presentation.UpdateSlaPolicyModel) (map[string]interface{}, error)
+	DeleteSlaPolicyById(*presentation.DeleteSlaPolicyModel) (map[string]interface{}, error)
+	GetSlaEscalationList(map[string]interface{}) (map[string]interface{}, error)
+}
+
+func NewSlaService(slaPolicyRepo repository.ISlaPolicyRepository, interactionRepo repository.IinteractionRepository, messageRepo repository.IMessageRepository) *SlaService {
+	slaService := SlaService{
+		slaPolicyRepo:   slaPolicyRepo,
+		interactionRepo: interactionRepo,
+		messageRepo:     messageRepo,
+	}
+	return &slaService
+}
+
+// findSlaPolicy prefers a policy for the exact platform and interaction type,
+// then the policy of the platform without an interaction type.
+func findSlaPolicy(policies []entity.SlaPolicy, platform string, interactionType string) *entity.SlaPolicy {
+	var platformPolicy *entity.SlaPolicy
+
+	for i, v := range policies {
+		if v.Platform != platform {
+			continue
+		}
+
+		if v.InteractionType == interactionType && interactionType != "" {
+			return &policies[i]
+		}
+		if v.InteractionType == "" {
+			platformPolicy = &policies[i]
+		}
+	}
+
+	return platformPolicy
+}
+
+// evaluateTimer reports whether a timer that started at start is breached or
+// close to breaching. stoppedAt is nil while the timer is still running.
+func evaluateTimer(start time.Time, minutes int64, nearBreachPercent int64, stoppedAt *time.Time, now time.Time) (deadline time.Time, breached bool, nearBreach bool) {
+	window := time.Duration(minutes) * time.Minute
+	deadline = start.Add(window)
+
+	if stoppedAt != nil {
+		return deadline, stoppedAt.After(deadline), false
+	}
+
+	nearBreachAt := start.Add(window * time.Duration(nearBreachPercent) / 100)
+	breached = now.After(deadline)
+	nearBreach = !breached && now.After(nearBreachAt)
+
+	return deadline, breached, nearBreach
+}
+
+func computeInteractionSla(interaction entity.Interaction, policy *entity.SlaPolicy, firstMessage presentation.InteractionFirstMessage, now time.Time) *presentation.InteractionSla {
+	sla := presentation.InteractionSla{
+		SlaPolicyId:     policy.ID,
+		FirstResponseAt: firstMessage.FirstAgentMessageAt,
+	}
+
+	nearBreachPercent := policy.NearBreachPercent
+	if nearBreachPercent == 0 {
+		nearBreachPercent = defaultNearBreachPercent
+	}
+
+	start := interaction.CreatedAt
+	if firstMessage.FirstReporterMessageAt != nil {
+		start = *firstMessage.FirstReporterMessageAt
+	}
+
+	// interactions closed before ClosedAt was recorded fall back to UpdatedAt
+	var closedAt *time.Time
+	if interaction.Status == enum.CLOSED {
+		closedAt = interaction.ClosedAt
+		if closedAt == nil {
+			closedAt = &interaction.UpdatedAt
+		}
+	}
+
+	if policy.FirstResponseMinutes > 0 && firstMessage.FirstReporterMessageAt != nil {
+		stoppedAt := firstMessage.FirstAgentMessageAt
+		if stoppedAt == nil {
+			stoppedAt = closedAt
+		}
+
+		deadline, breached, nearBreach := evaluateTimer(start, policy.FirstResponseMinutes, nearBreachPercent, stoppedAt, now)
+		sla.FirstResponseDeadline = &deadline
+		sla.FirstResponseBreached = breached
+		sla.FirstResponseNearBreach = nearBreach
+	}
+
+	if policy.ResolutionMinutes > 0 {
+		deadline, breached, nearBreach := evaluateTimer(start, policy.ResolutionMinutes, nearBreachPercent, closedAt, now)
+		sla.ResolutionDeadline = &deadline
+		sla.ResolutionBreached = breached
+		sla.ResolutionNearBreach = nearBreach
+	}
+
+	return &sla
+}
+
+// GetInteractionSla computes the SLA state of the given interactions, keyed by
+// interaction id. Interactions without an applicable policy are left out.
+func (ss *SlaService) GetInteractionSla(interactions []entity.Interaction) (map[uint]*presentation.InteractionSla, error) {
+	slaList := make(map[uint]*presentation.InteractionSla)
+	var interactionIds []uint
+
+	if len(interactions) == 0 {
+		return slaList, nil
+	}
+
+	policies, err := ss.slaPolicyRepo.GetSlaPolicyList()
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return slaList, nil
+	}
+
+	for _, v := range interactions {
+		interactionIds = append(interactionIds, v.ID)
+	}
+
+	firstMessages, err := ss.messageRepo.GetFirstMessageTimestamps(interactionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	firstMessageByInteraction := make(map[uint]presentation.InteractionFirstMessage)
+	for _, v := range firstMessages {
+		firstMessageByInteraction[v.InteractionId] = v
+	}
+
+	now := time.Now()
+	for _, v := range interactions {
+		policy := findSlaPolicy(policies, v.Platform, v.InteractionType)
+		if policy == nil {
+			continue
+		}
+
+		slaList[v.ID] = computeInteractionSla(v, policy, firstMessageByInteraction[v.ID], now)
+	}
+
+	return slaList, nil
+}
+
+func slaEscalationTypes(sla *presentation.InteractionSla) []string {
+	var escalationTypes []string
+
+	if sla.FirstResponseNearBreach {
+		escalationTypes = append(escalationTypes, enum.FIRST_RESPONSE_NEAR_BREACH)
+	}
+	if sla.FirstResponseBreached && sla.FirstResponseAt == nil {
+		escalationTypes = append(escalationTypes, enum.FIRST_RESPONSE_BREACH)
+	}
+	if sla.ResolutionNearBreach {
+		escalationTypes = append(escalationTypes, enum.RESOLUTION_NEAR_BREACH)
+	}
+	if sla.ResolutionBreached {
+		escalationTypes = append(escalationTypes, enum.RESOLUTION_BREACH)
+	}
+
+	return escalationTypes
+}
+
+// CheckSlaBreaches evaluates every open interaction and fires an escalation
+// event the first time it reaches a near-breach or breach state.
+func (ss *SlaService) CheckSlaBreaches() error {
+	status := []string{enum.UNCLAIMED, enum.WAITING, enum.IN_PROGRESS, enum.ACTIVE}
+
+	interactionList, err := ss.interactionRepo.GetInteractionListByStatus(status)
+	if len(interactionList) == 0 || err != nil {
+		return err
+	}
+
+	slaList, err := ss.GetInteractionSla(interactionList)
+	if len(slaList) == 0 || err != nil {
+		return err
+	}
+
+	var interactionIds []uint
+	for interactionId := range slaList {
+		interactionIds = append(interactionIds, interactionId)
+	}
+
+	firedEscalations, err := ss.slaPolicyRepo.GetSlaEscalationTypesByInteractionIds(interactionIds)
+	if err != nil {
+		return err
+	}
+
+	fired := make(map[string]bool)
+	for _, v := range firedEscalations {
+		fired[fmt.Sprintf("%d-%s", v.InteractionId, v.EscalationType)] = true
+	}
+
+	for _, interaction := range interactionList {
+		sla, ok := slaList[interaction.ID]
+		if !ok {
+			continue
+		}
+
+		for _, escalationType := range slaEscalationTypes(sla) {
+			if fired[fmt.Sprintf("%d-%s", interaction.ID, escalationType)] {
+				continue
+			}
+
+			deadline := sla.ResolutionDeadline
+			if escalationType == enum.FIRST_RESPONSE_NEAR_BREACH || escalationType == enum.FIRST_RESPONSE_BREACH {
+				deadline = sla.FirstResponseDeadline
+			}
+
+			newEscalation := entity.SlaEscalation{
+				InteractionId:  interaction.ID,
+				SlaPolicyId:    sla.SlaPolicyId,
+				AgentId:        interaction.AgentId,
+				EscalationType: escalationType,
+				Deadline:       *deadline,
+			}
+
+			escalation, err := ss.slaPolicyRepo.CreateSlaEscalation(&newEscalation)
+			if err != nil {
+				return err
+			}
+
+			logger.Info(fmt.Sprintf("[SLA][Escalation] Interaction %d %s, deadline %s", interaction.ID, escalationType, deadline.Format(time.RFC3339)))
+
+			err = ss.WebsocketEscalationService(*escalation)
+			if err != nil {
+				logger.Info(fmt.Sprintf("[FAILED][SLA Escalation] Notify escalation %d: %+v", escalation.ID, err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// StartSlaScheduler runs CheckSlaBreaches every Sla.CheckInterval (default one
+// minute). It blocks, so it is meant to be started in its own goroutine.
+func (ss *SlaService) StartSlaScheduler() {
+	interval := viper.GetDuration("Sla.CheckInterval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := ss.CheckSlaBreaches()
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][SLA Scheduler] Check SLA breaches: %+v", err))
+		}
+	}
+}
+
+type EscalationToSend struct {
+	Action     string                                 `json:"action"`
+	Escalation presentation.SlaEscalationNotification `json:"escalation"`
+}
+
+func (ss *SlaService) WebsocketEscalationService(escalation entity.SlaEscalation) error {
+	config.GetConfig()
+
+	host := viper.GetString("Websocket.Host")
+	channel := "ws"
+
+	connectionId := fmt.Sprintf("sla-%d", escalation.InteractionId)
+	query := fmt.Sprintf("user_id=%s&room_id=%d", connectionId, escalation.InteractionId)
+	client, err := NewWebSocketClient(host, channel, connectionId, query)
+	if err != nil {
+		return err
+	}
+
+	send := EscalationToSend{
+		Action: "sla-escalation",
+		Escalation: presentation.SlaEscalationNotification{
+			EscalationId:   escalation.ID,
+			InteractionId:  escalation.InteractionId,
+			AgentId:        escalation.AgentId,
+			EscalationType: escalation.EscalationType,
+			Deadline:       escalation.Deadline,
+		},
+	}
+	err = client.Write(send)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ss *SlaService) CreateSlaPolicy(spm *presentation.SlaPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newSlaPolicy := entity.SlaPolicy{
+		Platform:             spm.Platform,
+		InteractionType:      spm.InteractionType,
+		FirstResponseMinutes: spm.FirstResponseMinutes,
+		ResolutionMinutes:    spm.ResolutionMinutes,
+		NearBreachPercent:    spm.NearBreachPercent,
+	}
+
+	slaPolicy, err := ss.slaPolicyRepo.CreateSlaPolicy(&newSlaPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	result["sla_policy"] = slaPolicy
+
+	return result, nil
+}
+
+func (ss *SlaService) GetSlaPolicyList() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	slaPolicyList, err := ss.slaPolicyRepo.GetSlaPolicyList()
+	if (slaPolicyList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["sla_policy_list"] = slaPolicyList
+
+	return result, nil
+}
+
+func (ss *SlaService) UpdateSlaPolicy(uspm *presentation.UpdateSlaPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	newSlaPolicy := entity.SlaPolicy{
+		FirstResponseMinutes: uspm.FirstResponseMinutes,
+		ResolutionMinutes:    uspm.ResolutionMinutes,
+		NearBreachPercent:    uspm.NearBreachPercent,
+	}
+
+	slaPolicy, err := ss.slaPolicyRepo.UpdateSlaPolicy(uspm.SlaPolicyId, &newSlaPolicy)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["sla_policy"] = slaPolicy
+
+	return result, nil
+}
+
+func (ss *SlaService) DeleteSlaPolicyById(dspm *presentation.DeleteSlaPolicyModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	err := ss.slaPolicyRepo.DeleteSlaPolicy(dspm.SlaPolicyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+	return result, nil
+}
+
+func (ss *SlaService) GetSlaEscalationList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	slaEscalationList, err := ss.slaPolicyRepo.GetSlaEscalationList(filters)
+	if (slaEscalationList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["sla_escalation_list"] = slaEscalationList
+
+	return result, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/request"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type TelegramAdapter struct {
+	channelAccountRepo repository.IChannelAccountRepository
+	messageRepo        repository.IMessageRepository
+	reporterRepo       repository.IReporterRepository
+}
+
+func NewTelegramAdapter(channelAccountRepo repository.IChannelAccountRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository) *TelegramAdapter {
+	telegramAdapter := TelegramAdapter{
+		channelAccountRepo: channelAccountRepo,
+		messageRepo:        messageRepo,
+		reporterRepo:       reporterRepo,
+	}
+	return &telegramAdapter
+}
+
+// telegramMessageId builds the stored message id, Telegram message ids are
+// only unique within a chat.
+func telegramMessageId(chatId int64, messageId int64) string {
+	return fmt.Sprintf("%d_%d", chatId, messageId)
+}
+
+func (ta *TelegramAdapter) Platform() string {
+	return enum.TELEGRAM
+}
+
+func (ta *TelegramAdapter) Capabilities() ChannelCapabilities {
+	return ChannelCapabilities{
+		Webhook:   true,
+		AutoReply: true,
+		Realtime:  true,
+	}
+}
+
+// VerifyWebhook checks that the bot of the delivery belongs to a channel
+// account, then compares the secret_token set when the webhook was registered
+// with setWebhook. The secret of the bot is used, Telegram.SecretToken when it
+// has none. The bot id is public, so deliveries are rejected while neither is
+// set.
+func (ta *TelegramAdapter) VerifyWebhook(delivery *presentation.WebhookDelivery) error {
+	channelAccount, err := ta.channelAccountRepo.GetChannelAccountByPlatformId(delivery.PlatformId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && channelAccount.TelegramBotId != delivery.PlatformId) {
+		return enum.CHANNEL_ACCOUNT_NOT_MATCH
+
+	} else if err != nil {
+		return err
+	}
+
+	secretToken := channelAccount.TelegramSecretToken
+	if secretToken == "" {
+		secretToken = viper.GetString("Telegram.SecretToken")
+	}
+	if secretToken == "" {
+		return enum.WEBHOOK_SECRET_NOT_SET
+	}
+
+	if delivery.Signature == "" {
+		return enum.WEBHOOK_SIGNATURE_MISSING
+	}
+
+	if subtle.ConstantTimeCompare([]byte(delivery.Signature), []byte(secretToken)) != 1 {
+		return enum.INVALID_WEBHOOK_SIGNATURE
+	}
+
+	return nil
+}
+
+// ParseInbound reads an update of the bot platformId. Only private chat
+// messages are kept, other updates (edited messages, group chats, ...) are
+// ignored.
+func (ta *TelegramAdapter) ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error) {
+	var tu presentation.TelegramUpdate
+
+	err := json.Unmarshal(body, &tu)
+	if err != nil {
+		return nil, err
+	}
+
+	if tu.Message == nil || tu.Message.Chat.Type != "private" || tu.Message.From.IsBot {
+		return nil, nil
+	}
+
+	telegramMessage := tu.Message
+
+	inboundMessage := presentation.InboundMessage{
+		PlatformId:       platformId,
+		InteractionType:  enum.PESAN,
+		MetaReporterId:   fmt.Sprint(telegramMessage.Chat.Id),
+		ReporterName:     strings.TrimSpace(telegramMessage.From.FirstName + " " + telegramMessage.From.LastName),
+		ReporterUsername: telegramMessage.From.Username,
+		MessageId:        telegramMessageId(telegramMessage.Chat.Id, telegramMessage.MessageId),
+		Message:          telegramMessage.Text,
+		MessageTimestamp: time.Unix(telegramMessage.Date, 0),
+	}
+	if inboundMessage.Message == "" {
+		inboundMessage.Message = telegramMessage.Caption
+	}
+
+	if telegramMessage.Location != nil {
+		inboundMessage.AttachmentType = enum.LOCATION
+		location, _ := json.Marshal(telegramMessage.Location)
+		inboundMessage.Message = string(location)
+	}
+
+	return []presentation.InboundMessage{inboundMessage}, nil
+}
+
+func (ta *TelegramAdapter) FetchMedia(inboundMessage *presentation.InboundMessage) error {
+	return nil
+}
+
+// SendMessage sends through the bot of the channel account. The account is
+// loaded again since the bot token is never part of the one in the JWT.
+func (ta *TelegramAdapter) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	result := make(map[string]interface{})
+
+	if channelAccount.ID == 0 {
+		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+	}
+
+	channelAccount, err := ta.channelAccountRepo.GetChannelAccountById(channelAccount.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if channelAccount.TelegramBotId == "" {
+		return nil, nil, enum.PLATFORM_ID_NOT_SET
+	}
+
+	if msmr.PlatformId != channelAccount.TelegramBotId {
+		return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+	}
+
+	if channelAccount.TelegramBotToken == "" {
+		return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	}
+
+	reporter, err := ta.reporterRepo.GetReporterByReporterId(msmr.ReporterId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the bot token is part of the path, Telegram does not use bearer tokens
+	reqUrl := url.URL{
+		Scheme: "https",
+		Host:   "api.telegram.org",
+		Path:   fmt.Sprintf("/bot%s/sendMessage", channelAccount.TelegramBotToken),
+	}
+
+	body := presentation.TelegramSendMessageRequest{
+		ChatId: reporter.MetaReporterId,
+		Text:   msmr.Message,
+	}
+
+	response, err := request.PostRequest(reqUrl, body, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		messageData := &presentation.TelegramSendMessageResponse{}
+		err = json.NewDecoder(response.Body).Decode(messageData)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sendedMessage := entity.Message{
+			InteractionId:    msmr.InteractionId,
+			SenderId:         msmr.PlatformId,
+			RecipientId:      reporter.MetaReporterId,
+			MetaMessageId:    telegramMessageId(messageData.Result.Chat.Id, messageData.Result.MessageId),
+			Message:          msmr.Message,
+			MessageTimestamp: time.Now(),
+			SentBy:           outboundSentBy(msmr),
+			IsRead:           false,
+		}
+
+		message, err := ta.messageRepo.CreateMessage(&sendedMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result["message_id"] = message.MetaMessageId
+
+		return result, message, nil
+	}
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	bodyString := string(bodyBytes)
+	logger.Info(response.Status)
+	logger.Info(bodyString)
+	return nil, nil, errors.New("telegram response not 200")
+}
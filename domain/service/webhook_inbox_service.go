@@ -0,0 +1,293 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// IWebhookInboxProcessor processes the queued deliveries of the platforms it
+// is registered for. Returning an error schedules a retry.
+type IWebhookInboxProcessor interface {
+	ProcessWebhookInbox(*entity.WebhookInbox) error
+}
+
+type WebhookInboxService struct {
+	webhookInboxRepo repository.IWebhookInboxRepository
+	processors       map[string]IWebhookInboxProcessor
+}
+
+type IWebhookInboxService interface {
+	RegisterProcessor(IWebhookInboxProcessor, ...string)
+	EnqueueWebhook(string, string, []byte) (map[string]interface{}, error)
+	ProcessNextWebhookInbox() (bool, error)
+	StartWebhookInboxWorkers()
+
+	GetWebhookInboxList(map[string]interface{}) (map[string]interface{}, error)
+	GetWebhookInboxById(uint) (map[string]interface{}, error)
+	ReplayWebhookInbox(*presentation.ReplayWebhookInboxModel) (map[string]interface{}, error)
+}
+
+func NewWebhookInboxService(webhookInboxRepo repository.IWebhookInboxRepository) *WebhookInboxService {
+	webhookInboxService := WebhookInboxService{
+		webhookInboxRepo: webhookInboxRepo,
+		processors:       make(map[string]IWebhookInboxProcessor),
+	}
+	return &webhookInboxService
+}
+
+// RegisterProcessor sets the processor of the given platforms. Processors are
+// registered before the workers are started.
+func (wis *WebhookInboxService) RegisterProcessor(processor IWebhookInboxProcessor, platforms ...string) {
+	for _, platform := range platforms {
+		wis.processors[platform] = processor
+	}
+}
+
+func webhookPayloadHash(platform string, platformId string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(platform + "\n" + platformId + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EnqueueWebhook stores a verified delivery for the workers. A delivery the
+// platform sends again with the same payload is not queued twice.
+func (wis *WebhookInboxService) EnqueueWebhook(platform string, platformId string, body []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	payloadHash := webhookPayloadHash(platform, platformId, body)
+
+	existingWebhookInbox, err := wis.webhookInboxRepo.GetWebhookInboxByPayloadHash(payloadHash)
+	if err == nil {
+		result["webhook_inbox_id"] = existingWebhookInbox.ID
+		result["duplicate"] = true
+		return result, nil
+
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	newWebhookInbox := entity.WebhookInbox{
+		Platform:      platform,
+		PlatformId:    platformId,
+		PayloadHash:   payloadHash,
+		Payload:       string(body),
+		Status:        enum.PENDING,
+		NextAttemptAt: time.Now(),
+	}
+
+	webhookInbox, err := wis.webhookInboxRepo.CreateWebhookInbox(&newWebhookInbox)
+	if err != nil {
+		// the same payload may have been queued by a concurrent delivery
+		existingWebhookInbox, findErr := wis.webhookInboxRepo.GetWebhookInboxByPayloadHash(payloadHash)
+		if findErr != nil {
+			return nil, err
+		}
+
+		result["webhook_inbox_id"] = existingWebhookInbox.ID
+		result["duplicate"] = true
+		return result, nil
+	}
+
+	result["webhook_inbox_id"] = webhookInbox.ID
+	result["duplicate"] = false
+
+	return result, nil
+}
+
+// webhookRetryDelay doubles WebhookInbox.RetryDelay (default 30 seconds) on
+// every attempt, up to one hour.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := viper.GetDuration("WebhookInbox.RetryDelay")
+	if delay <= 0 {
+		delay = 30 * time.Second
+	}
+
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+
+	return delay
+}
+
+func webhookMaxAttempts() int {
+	maxAttempts := viper.GetInt("WebhookInbox.MaxAttempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	return maxAttempts
+}
+
+// ProcessNextWebhookInbox claims one due delivery and hands it to the
+// processor of its platform. Failed deliveries are retried with a growing
+// delay and moved to DEAD_LETTER after WebhookInbox.MaxAttempts (default 5)
+// attempts. It reports whether a delivery was claimed.
+func (wis *WebhookInboxService) ProcessNextWebhookInbox() (bool, error) {
+	webhookInbox, err := wis.webhookInboxRepo.ClaimPendingWebhookInbox(time.Now())
+	if err != nil {
+		return false, err
+	}
+	if webhookInbox == nil {
+		return false, nil
+	}
+
+	processor, ok := wis.processors[webhookInbox.Platform]
+	if !ok {
+		err = enum.WEBHOOK_PROCESSOR_NOT_SET
+	} else {
+		err = processor.ProcessWebhookInbox(webhookInbox)
+	}
+
+	now := time.Now()
+	if err == nil {
+		webhookInbox.Status = enum.PROCESSED
+		webhookInbox.LastError = ""
+		webhookInbox.ProcessedAt = &now
+
+	} else if webhookInbox.Attempts >= webhookMaxAttempts() || errors.Is(err, enum.WEBHOOK_PROCESSOR_NOT_SET) {
+		logger.Info(fmt.Sprintf("[FAILED][Webhook Inbox] %s delivery %d moved to dead letter: %+v", webhookInbox.Platform, webhookInbox.ID, err))
+		webhookInbox.Status = enum.DEAD_LETTER
+		webhookInbox.LastError = err.Error()
+
+	} else {
+		logger.Info(fmt.Sprintf("[FAILED][Webhook Inbox] %s delivery %d attempt %d: %+v", webhookInbox.Platform, webhookInbox.ID, webhookInbox.Attempts, err))
+		webhookInbox.Status = enum.PENDING
+		webhookInbox.LastError = err.Error()
+		webhookInbox.NextAttemptAt = now.Add(webhookRetryDelay(webhookInbox.Attempts))
+	}
+
+	err = wis.webhookInboxRepo.UpdateWebhookInbox(webhookInbox)
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (wis *WebhookInboxService) runWebhookInboxWorker(pollInterval time.Duration) {
+	for {
+		claimed, err := wis.ProcessNextWebhookInbox()
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Webhook Inbox] Process delivery: %+v", err))
+		}
+
+		if !claimed || err != nil {
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+// StartWebhookInboxWorkers starts WebhookInbox.Workers (default 4) workers
+// polling the inbox every WebhookInbox.PollInterval (default two seconds) when
+// it is empty. Deliveries left PROCESSING for WebhookInbox.ProcessingTimeout
+// (default ten minutes) are put back in the queue. It blocks, so it is meant
+// to be started in its own goroutine.
+func (wis *WebhookInboxService) StartWebhookInboxWorkers() {
+	workers := viper.GetInt("WebhookInbox.Workers")
+	if workers <= 0 {
+		workers = 4
+	}
+
+	pollInterval := viper.GetDuration("WebhookInbox.PollInterval")
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+
+	processingTimeout := viper.GetDuration("WebhookInbox.ProcessingTimeout")
+	if processingTimeout <= 0 {
+		processingTimeout = 10 * time.Minute
+	}
+
+	for i := 0; i < workers; i++ {
+		go wis.runWebhookInboxWorker(pollInterval)
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		requeued, err := wis.webhookInboxRepo.RequeueStaleWebhookInbox(time.Now().Add(-processingTimeout))
+		if err != nil {
+			logger.Info(fmt.Sprintf("[FAILED][Webhook Inbox] Requeue stale deliveries: %+v", err))
+		} else if requeued > 0 {
+			logger.Info(fmt.Sprintf("[Webhook Inbox] Requeued %d stale deliveries", requeued))
+		}
+	}
+}
+
+func (wis *WebhookInboxService) GetWebhookInboxList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	webhookInboxList, err := wis.webhookInboxRepo.GetWebhookInboxList(filters)
+	if (webhookInboxList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["webhook_inbox_list"] = webhookInboxList
+
+	return result, nil
+}
+
+func (wis *WebhookInboxService) GetWebhookInboxById(webhookInboxId uint) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	webhookInbox, err := wis.webhookInboxRepo.GetWebhookInboxById(webhookInboxId)
+	if (webhookInbox == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["webhook_inbox"] = webhookInbox
+
+	return result, nil
+}
+
+// ReplayWebhookInbox queues a delivery again with a fresh attempt count,
+// typically one moved to DEAD_LETTER once the cause has been fixed.
+func (wis *WebhookInboxService) ReplayWebhookInbox(rwim *presentation.ReplayWebhookInboxModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	webhookInbox, err := wis.webhookInboxRepo.GetWebhookInboxById(rwim.WebhookInboxId)
+	if (webhookInbox == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if webhookInbox.Status == enum.PROCESSING {
+		return nil, enum.WEBHOOK_INBOX_PROCESSING
+	}
+
+	webhookInbox.Status = enum.PENDING
+	webhookInbox.Attempts = 0
+	webhookInbox.NextAttemptAt = time.Now()
+	webhookInbox.ProcessedAt = nil
+
+	err = wis.webhookInboxRepo.UpdateWebhookInbox(webhookInbox)
+	if err != nil {
+		return nil, err
+	}
+
+	result["status"] = "SUCCESS"
+
+	return result, nil
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type WhatsappAdapter struct {
+	channelAccountRepo repository.IChannelAccountRepository
+	messageRepo        repository.IMessageRepository
+	reporterRepo       repository.IReporterRepository
+	mediaClient        WhatsappMediaClient
+	attachmentStore    AttachmentStore
+}
+
+func NewWhatsappAdapter(channelAccountRepo repository.IChannelAccountRepository, messageRepo repository.IMessageRepository, reporterRepo repository.IReporterRepository, mediaClient WhatsappMediaClient, attachmentStore AttachmentStore) *WhatsappAdapter {
+	whatsappAdapter := WhatsappAdapter{
+		channelAccountRepo: channelAccountRepo,
+		messageRepo:        messageRepo,
+		reporterRepo:       reporterRepo,
+		mediaClient:        mediaClient,
+		attachmentStore:    attachmentStore,
+	}
+	return &whatsappAdapter
+}
+
+func (wa *WhatsappAdapter) Platform() string {
+	return enum.WA
+}
+
+func (wa *WhatsappAdapter) Capabilities() ChannelCapabilities {
+	return ChannelCapabilities{
+		Webhook:   true,
+		Media:     true,
+		AutoReply: true,
+		Realtime:  true,
+
+		Attachments: true,
+	}
+}
+
+func (wa *WhatsappAdapter) VerifyWebhook(delivery *presentation.WebhookDelivery) error {
+	return verifyMetaSignature(wa.channelAccountRepo, delivery)
+}
+
+func (wa *WhatsappAdapter) ParseInbound(platformId string, body []byte) ([]presentation.InboundMessage, error) {
+	var wir presentation.WhatsappInteractionRequest
+	var inboundMessages []presentation.InboundMessage
+
+	err := json.Unmarshal(body, &wir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range wir.Entry {
+		for _, change := range entry.Changes {
+			// status updates of sent messages have no contacts nor messages
+			if len(change.Value.Contacts) == 0 {
+				continue
+			}
+			contact := change.Value.Contacts[0]
+
+			for _, waMessage := range change.Value.Messages {
+				timestampInt, _ := strconv.ParseInt(waMessage.Timestamp, 10, 64)
+
+				inboundMessage := presentation.InboundMessage{
+					PlatformId:       entry.PlatformId,
+					InteractionType:  enum.PESAN,
+					MetaReporterId:   contact.WaId,
+					ReporterName:     contact.Profile["name"],
+					MessageId:        waMessage.ID,
+					Message:          waMessage.Text.Body,
+					MessageTimestamp: time.Unix(timestampInt, 0),
+				}
+
+				switch waMessage.Type {
+				case "location":
+					inboundMessage.AttachmentType = enum.LOCATION
+					location, _ := json.Marshal(waMessage.Location)
+					inboundMessage.Message = string(location)
+				case "image":
+					inboundMessage.AttachmentType = enum.IMAGE
+					inboundMessage.MediaId = waMessage.Image.Id
+					if waMessage.Image.Caption != "" {
+						inboundMessage.Message = waMessage.Image.Caption
+					}
+				case "video":
+					inboundMessage.AttachmentType = enum.VIDEO
+					inboundMessage.MediaId = waMessage.Video.Id
+					if waMessage.Video.Caption != "" {
+						inboundMessage.Message = waMessage.Video.Caption
+					}
+				}
+
+				inboundMessages = append(inboundMessages, inboundMessage)
+			}
+		}
+	}
+
+	return inboundMessages, nil
+}
+
+// ParseStatuses reads the statuses of the messages sent from the business
+// number. Statuses other than sent, delivered, read and failed are ignored.
+func (wa *WhatsappAdapter) ParseStatuses(platformId string, body []byte) ([]presentation.MessageStatusUpdate, error) {
+	var wir presentation.WhatsappInteractionRequest
+	var statusUpdates []presentation.MessageStatusUpdate
+
+	err := json.Unmarshal(body, &wir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range wir.Entry {
+		for _, change := range entry.Changes {
+			for _, waStatus := range change.Value.Statuses {
+				status := strings.ToUpper(waStatus.Status)
+				if status != enum.SENT && status != enum.DELIVERED && status != enum.READ && status != enum.FAILED {
+					continue
+				}
+
+				timestampInt, _ := strconv.ParseInt(waStatus.Timestamp, 10, 64)
+
+				statusUpdate := presentation.MessageStatusUpdate{
+					MessageId:       waStatus.ID,
+					RecipientId:     waStatus.RecipientId,
+					Status:          status,
+					StatusTimestamp: time.Unix(timestampInt, 0),
+				}
+				if len(waStatus.Errors) > 0 {
+					statusUpdate.ErrorCode = waStatus.Errors[0].Code
+					statusUpdate.ErrorTitle = waStatus.Errors[0].Title
+					statusUpdate.ErrorMessage = waStatus.Errors[0].ErrorData.Details
+					if statusUpdate.ErrorMessage == "" {
+						statusUpdate.ErrorMessage = waStatus.Errors[0].Message
+					}
+				}
+
+				statusUpdates = append(statusUpdates, statusUpdate)
+			}
+		}
+	}
+
+	return statusUpdates, nil
+}
+
+// FetchMedia downloads the media with the access token of the channel account
+// of the business account (Meta.WA_ACCESS_TOKEN when it has none) and keeps it
+// in the attachment store.
+func (wa *WhatsappAdapter) FetchMedia(inboundMessage *presentation.InboundMessage) error {
+	accessToken := viper.GetString("Meta.WA_ACCESS_TOKEN")
+
+	channelAccount, err := wa.channelAccountRepo.GetChannelAccountByPlatformId(inboundMessage.PlatformId)
+	if err == nil && channelAccount.WhatsappAccessToken != "" {
+		accessToken = channelAccount.WhatsappAccessToken
+
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	media, err := wa.mediaClient.DownloadMedia(inboundMessage.MediaId, accessToken)
+	if err != nil {
+		return err
+	}
+	defer media.Content.Close()
+
+	attachmentUrl, err := wa.attachmentStore.SaveAttachment(enum.WA, inboundMessage.MediaId, media.MimeType, media.Content)
+	if err != nil {
+		return err
+	}
+
+	inboundMessage.AttachmentUrl = attachmentUrl
+
+	return nil
+}
+
+// whatsappCustomerServiceWindow is how long free text can be sent after the
+// last message of the reporter, templates are required afterwards.
+const whatsappCustomerServiceWindow = 24 * time.Hour
+
+func (wa *WhatsappAdapter) checkCustomerServiceWindow(waId string, whatsappBusinessId string) error {
+	lastMessage, err := wa.messageRepo.GetLatestReporterMessage(waId, whatsappBusinessId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return enum.WHATSAPP_WINDOW_EXPIRED
+
+	} else if err != nil {
+		return err
+	}
+
+	if time.Since(lastMessage.MessageTimestamp) > whatsappCustomerServiceWindow {
+		return enum.WHATSAPP_WINDOW_EXPIRED
+	}
+
+	return nil
+}
+
+func (wa *WhatsappAdapter) SendMessage(msmr *presentation.MetaSendMessageRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	result := make(map[string]interface{})
+
+	if channelAccount.ID == 0 {
+		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+	}
+
+	if channelAccount.WhatsappNumId == "" {
+		return nil, nil, enum.PLATFORM_ID_NOT_SET
+	}
+
+	if channelAccount.WhatsappBusinessId == "" {
+		return nil, nil, enum.PLATFORM_ID_NOT_SET
+	}
+
+	if msmr.PlatformId != channelAccount.WhatsappBusinessId {
+		return nil, nil, enum.CHANNEL_ACCOUNT_NOT_MATCH
+	}
+
+	if channelAccount.WhatsappAccessToken == "" {
+		return nil, nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	}
+	access_token := channelAccount.WhatsappAccessToken
+
+	reporter, err := wa.reporterRepo.GetReporterByReporterId(msmr.ReporterId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("/%s/%s/messages", viper.GetString("Meta.WA_API_VERSION"), channelAccount.WhatsappNumId)
+
+	reqUrl := url.URL{
+		Scheme: "https",
+		Host:   "graph.facebook.com",
+		Path:   path,
+	}
+
+	body := presentation.WhatsappSendMessageMetaRequest{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		Recipient:        reporter.MetaReporterId,
+	}
+
+	if msmr.Template != nil {
+		body.MessageType = "template"
+		body.Template = msmr.Template
+
+	} else {
+		err = wa.checkCustomerServiceWindow(reporter.MetaReporterId, channelAccount.WhatsappBusinessId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case msmr.Location != nil:
+			body.MessageType = "location"
+			body.Location = msmr.Location
+
+		case msmr.Attachment != nil:
+			mediaId, err := wa.mediaClient.UploadMedia(channelAccount.WhatsappNumId, msmr.Attachment, access_token)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			media := &presentation.WhatsappMediaMetaField{Id: mediaId}
+			switch msmr.AttachmentType {
+			case enum.IMAGE:
+				media.Caption = msmr.Message
+				body.MessageType = "image"
+				body.Image = media
+			case enum.VIDEO:
+				media.Caption = msmr.Message
+				body.MessageType = "video"
+				body.Video = media
+			case enum.AUDIO:
+				body.MessageType = "audio"
+				body.Audio = media
+			default:
+				media.Caption = msmr.Message
+				media.Filename = msmr.Attachment.Name
+				body.MessageType = "document"
+				body.Document = media
+			}
+
+		default:
+			body.MessageType = "text"
+			body.Text = &presentation.WhatsappMessageMetaField{
+				PreviewUrl: false,
+				Body:       msmr.Message,
+			}
+		}
+	}
+
+	response, err := request.PostRequest(reqUrl, body, access_token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		messageData := &presentation.WhatsappSendMessageMetaResponse{}
+		err = json.NewDecoder(response.Body).Decode(messageData)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sendedMessage := entity.Message{
+			InteractionId:    msmr.InteractionId,
+			SenderId:         msmr.PlatformId,
+			RecipientId:      messageData.Contacts[0].WaId,
+			MetaMessageId:    messageData.Messages[0].Id,
+			Message:          msmr.Message,
+			MessageTimestamp: time.Now(),
+			SentBy:           outboundSentBy(msmr),
+			IsRead:           false,
+			AttachmentType:   msmr.AttachmentType,
+			AttachmentUrl:    msmr.AttachmentUrl,
+		}
+
+		message, err := wa.messageRepo.CreateMessage(&sendedMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result["message_id"] = message.MetaMessageId
+
+		return result, message, nil
+	}
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	bodyString := string(bodyBytes)
+	logger.Info(response.Status)
+	logger.Info(bodyString)
+	return nil, nil, errors.New("meta response not 200")
+}
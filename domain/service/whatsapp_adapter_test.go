@@ -0,0 +1,72 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// channelAccountRepoWithoutAccounts knows no channel account, FetchMedia then
+// uses Meta.WA_ACCESS_TOKEN.
+type channelAccountRepoWithoutAccounts struct {
+	repository.IChannelAccountRepository
+}
+
+func (r channelAccountRepoWithoutAccounts) GetChannelAccountByPlatformId(platformId string) (*entity.ChannelAccount, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestWhatsappAdapterFetchMediaOfInboundImage(t *testing.T) {
+	mediaDir := t.TempDir()
+	storeDir := t.TempDir()
+
+	image := []byte("\x89PNG\r\n\x1a\nimage")
+	err := os.WriteFile(filepath.Join(mediaDir, "1234.png"), image, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adapter := NewWhatsappAdapter(channelAccountRepoWithoutAccounts{}, nil, nil, NewLocalWhatsappMediaClient(mediaDir), NewLocalAttachmentStore(storeDir, "/files"))
+
+	body := `{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{
+		"contacts":[{"wa_id":"6281234","profile":{"name":"Reporter"}}],
+		"messages":[{"id":"wamid.1","timestamp":"1700000000","type":"image","image":{"id":"1234","mime_type":"image/png","caption":"a photo"}}]}}]}]}`
+
+	inboundMessages, err := adapter.ParseInbound("waba-1", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inboundMessages) != 1 {
+		t.Fatalf("got %d inbound messages, want 1", len(inboundMessages))
+	}
+
+	inboundMessage := inboundMessages[0]
+	err = adapter.FetchMedia(&inboundMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if inboundMessage.AttachmentType != enum.IMAGE {
+		t.Errorf("AttachmentType = %q, want %q", inboundMessage.AttachmentType, enum.IMAGE)
+	}
+	if inboundMessage.Message != "a photo" {
+		t.Errorf("Message = %q, want the caption", inboundMessage.Message)
+	}
+	if !strings.HasPrefix(inboundMessage.AttachmentUrl, "/files/attachments/whatsapp/") || !strings.HasSuffix(inboundMessage.AttachmentUrl, "/1234.png") {
+		t.Fatalf("AttachmentUrl = %q, want /files/attachments/whatsapp/<month>/1234.png", inboundMessage.AttachmentUrl)
+	}
+
+	stored, err := os.ReadFile(filepath.Join(storeDir, filepath.FromSlash(strings.TrimPrefix(inboundMessage.AttachmentUrl, "/files/"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stored) != string(image) {
+		t.Errorf("stored attachment differs from the media")
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"Omnichannel-CRM/package/logger"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// maxWhatsappMediaSize is the largest media WhatsApp accepts (documents).
+const maxWhatsappMediaSize = 100 << 20
+
+// WhatsappMedia is a downloaded media, Content has to be closed by the caller.
+type WhatsappMedia struct {
+	MimeType string
+	Content  io.ReadCloser
+}
+
+// WhatsappMediaClient downloads the media received by a WhatsApp business
+// number from their media id, and uploads the media sent from it.
+type WhatsappMediaClient interface {
+	DownloadMedia(mediaId string, accessToken string) (*WhatsappMedia, error)
+	UploadMedia(phoneNumberId string, attachment *presentation.OutboundAttachment, accessToken string) (string, error)
+}
+
+// NewWhatsappMediaClient returns the Graph API client, or the local fake
+// reading Whatsapp.MediaDir when it is set (development and tests).
+func NewWhatsappMediaClient() WhatsappMediaClient {
+	mediaDir := viper.GetString("Whatsapp.MediaDir")
+	if mediaDir != "" {
+		return NewLocalWhatsappMediaClient(mediaDir)
+	}
+
+	return NewGraphWhatsappMediaClient()
+}
+
+type GraphWhatsappMediaClient struct{}
+
+func NewGraphWhatsappMediaClient() *GraphWhatsappMediaClient {
+	return &GraphWhatsappMediaClient{}
+}
+
+// DownloadMedia reads the media url from the Graph media endpoint, then
+// downloads the media from that url with the same access token.
+func (gwmc *GraphWhatsappMediaClient) DownloadMedia(mediaId string, accessToken string) (*WhatsappMedia, error) {
+	path := fmt.Sprintf("/%s/%s", viper.GetString("Meta.API_VERSION"), mediaId)
+
+	reqUrl := url.URL{
+		Scheme: "https",
+		Host:   "graph.facebook.com",
+		Path:   path,
+	}
+
+	response, err := request.GetRequest(reqUrl, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, metaResponseError(response)
+	}
+
+	attachDetailData := &presentation.WhatsappAttachmentDetailResp{}
+	err = json.NewDecoder(response.Body).Decode(attachDetailData)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaUrl, err := url.Parse(attachDetailData.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaResponse, err := request.GetRequest(*mediaUrl, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaResponse.StatusCode != http.StatusOK {
+		defer mediaResponse.Body.Close()
+		return nil, metaResponseError(mediaResponse)
+	}
+
+	whatsappMedia := WhatsappMedia{
+		MimeType: attachDetailData.MimeType,
+		Content: struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(mediaResponse.Body, maxWhatsappMediaSize), mediaResponse.Body},
+	}
+
+	return &whatsappMedia, nil
+}
+
+// UploadMedia uploads the attachment to the media endpoint of the business
+// number and returns the media id to send it with.
+func (gwmc *GraphWhatsappMediaClient) UploadMedia(phoneNumberId string, attachment *presentation.OutboundAttachment, accessToken string) (string, error) {
+	path := fmt.Sprintf("/%s/%s/media", viper.GetString("Meta.WA_API_VERSION"), phoneNumberId)
+
+	reqUrl := url.URL{
+		Scheme: "https",
+		Host:   "graph.facebook.com",
+		Path:   path,
+	}
+
+	fields := map[string]string{
+		"messaging_product": "whatsapp",
+		"type":              attachment.ContentType,
+	}
+
+	response, err := request.PostMultipartRequest(reqUrl, fields, "file", attachment.Name, attachment.ContentType, attachment.Content, accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", metaResponseError(response)
+	}
+
+	uploadData := &presentation.WhatsappMediaUploadMetaResponse{}
+	err = json.NewDecoder(response.Body).Decode(uploadData)
+	if err != nil {
+		return "", err
+	}
+
+	return uploadData.Id, nil
+}
+
+func metaResponseError(response *http.Response) error {
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	bodyString := string(bodyBytes)
+	logger.Info(response.Status)
+	logger.Info(bodyString)
+	return errors.New("Meta response not 200")
+}
+
+// LocalWhatsappMediaClient is a fake of the Graph media endpoint serving the
+// files of dir, a media id is the name of a file without its extension.
+type LocalWhatsappMediaClient struct {
+	dir string
+}
+
+func NewLocalWhatsappMediaClient(dir string) *LocalWhatsappMediaClient {
+	localWhatsappMediaClient := LocalWhatsappMediaClient{
+		dir: dir,
+	}
+	return &localWhatsappMediaClient
+}
+
+func (lwmc *LocalWhatsappMediaClient) DownloadMedia(mediaId string, accessToken string) (*WhatsappMedia, error) {
+	matches, err := filepath.Glob(filepath.Join(lwmc.dir, filepath.Base(mediaId)+".*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("media %s not found in %s", mediaId, lwmc.dir)
+	}
+
+	file, err := os.Open(matches[0])
+	if err != nil {
+		return nil, err
+	}
+
+	whatsappMedia := WhatsappMedia{
+		MimeType: mime.TypeByExtension(filepath.Ext(matches[0])),
+		Content:  file,
+	}
+
+	return &whatsappMedia, nil
+}
+
+// UploadMedia writes the attachment to dir under a new media id, so it can be
+// downloaded back like a received media.
+func (lwmc *LocalWhatsappMediaClient) UploadMedia(phoneNumberId string, attachment *presentation.OutboundAttachment, accessToken string) (string, error) {
+	mediaId := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	err := os.WriteFile(filepath.Join(lwmc.dir, attachmentFileName(mediaId+filepath.Ext(attachment.Name), attachment.ContentType)), attachment.Content, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return mediaId, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"Omnichannel-CRM/domain/entity"
+	"Omnichannel-CRM/domain/repository"
+	"Omnichannel-CRM/package/enum"
+	"Omnichannel-CRM/package/presentation"
+	"Omnichannel-CRM/package/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var templateParameterPattern = regexp.MustCompile(`\{\{(\d+)\}\}`)
+
+type WhatsappTemplateService struct {
+	whatsappTemplateRepo repository.IWhatsappTemplateRepository
+	channelAccountRepo   repository.IChannelAccountRepository
+	interactionService   IInteractionService
+}
+
+type IWhatsappTemplateService interface {
+	SyncWhatsappTemplates(*presentation.SyncWhatsappTemplateModel) (map[string]interface{}, error)
+	GetWhatsappTemplateList(map[string]interface{}) (map[string]interface{}, error)
+	SendWhatsappTemplate(*presentation.SendWhatsappTemplateRequest, *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error)
+}
+
+func NewWhatsappTemplateService(whatsappTemplateRepo repository.IWhatsappTemplateRepository, channelAccountRepo repository.IChannelAccountRepository, interactionService IInteractionService) *WhatsappTemplateService {
+	whatsappTemplateService := WhatsappTemplateService{
+		whatsappTemplateRepo: whatsappTemplateRepo,
+		channelAccountRepo:   channelAccountRepo,
+		interactionService:   interactionService,
+	}
+	return &whatsappTemplateService
+}
+
+// templateParameterCount returns the highest {{n}} placeholder of the text,
+// Meta numbers them from 1 without gaps.
+func templateParameterCount(text string) int {
+	count := 0
+	for _, match := range templateParameterPattern.FindAllStringSubmatch(text, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err == nil && n > count {
+			count = n
+		}
+	}
+	return count
+}
+
+// renderTemplate substitutes the {{n}} placeholders of the text with the
+// parameters, {{1}} being the first one.
+func renderTemplate(text string, parameters []string) string {
+	return templateParameterPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		n, _ := strconv.Atoi(strings.Trim(placeholder, "{}"))
+		if n < 1 || n > len(parameters) {
+			return placeholder
+		}
+		return parameters[n-1]
+	})
+}
+
+func (wts *WhatsappTemplateService) fetchWhatsappTemplates(channelAccount *entity.ChannelAccount) ([]presentation.WhatsappTemplateData, error) {
+	var templates []presentation.WhatsappTemplateData
+
+	path := fmt.Sprintf("/%s/%s/message_templates", viper.GetString("Meta.WA_API_VERSION"), channelAccount.WhatsappBusinessId)
+	reqUrl := url.URL{
+		Scheme:   "https",
+		Host:     "graph.facebook.com",
+		Path:     path,
+		RawQuery: "fields=id,name,language,status,category,components&limit=100",
+	}
+
+	for {
+		response, err := request.GetRequest(reqUrl, channelAccount.WhatsappAccessToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			err = metaResponseError(response)
+			response.Body.Close()
+			return nil, err
+		}
+
+		var templateList presentation.WhatsappTemplateListResp
+		err = json.NewDecoder(response.Body).Decode(&templateList)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, templateList.Data...)
+
+		if templateList.Paging.Next == "" {
+			return templates, nil
+		}
+
+		nextUrl, err := url.Parse(templateList.Paging.Next)
+		if err != nil {
+			return nil, err
+		}
+		reqUrl = *nextUrl
+	}
+}
+
+// SyncWhatsappTemplates stores the templates of the WhatsApp business account
+// of the channel account as they currently are on Meta.
+func (wts *WhatsappTemplateService) SyncWhatsappTemplates(swtm *presentation.SyncWhatsappTemplateModel) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	channelAccount, err := wts.channelAccountRepo.GetChannelAccountById(swtm.ChannelAccountId)
+	if (channelAccount == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if channelAccount.WhatsappBusinessId == "" {
+		return nil, enum.PLATFORM_ID_NOT_SET
+	}
+
+	if channelAccount.WhatsappAccessToken == "" {
+		return nil, enum.PLATFORM_ACCESS_TOKEN_NOT_SET
+	}
+
+	templates, err := wts.fetchWhatsappTemplates(channelAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	var whatsappTemplateList []entity.WhatsappTemplate
+	for _, template := range templates {
+		var bodyText string
+		for _, component := range template.Components {
+			if component.Type == "BODY" {
+				bodyText = component.Text
+			}
+		}
+
+		components, err := json.Marshal(template.Components)
+		if err != nil {
+			return nil, err
+		}
+
+		whatsappTemplate, err := wts.whatsappTemplateRepo.SaveWhatsappTemplate(&entity.WhatsappTemplate{
+			WhatsappBusinessId: channelAccount.WhatsappBusinessId,
+			Name:               template.Name,
+			Language:           template.Language,
+			MetaTemplateId:     template.Id,
+			Category:           template.Category,
+			Status:             template.Status,
+			BodyText:           bodyText,
+			Components:         string(components),
+			ParameterCount:     templateParameterCount(bodyText),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		whatsappTemplateList = append(whatsappTemplateList, *whatsappTemplate)
+	}
+
+	result["whatsapp_template_list"] = whatsappTemplateList
+
+	return result, nil
+}
+
+func (wts *WhatsappTemplateService) GetWhatsappTemplateList(filters map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	whatsappTemplateList, err := wts.whatsappTemplateRepo.GetWhatsappTemplateList(filters)
+	if (whatsappTemplateList == nil && err == nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	result["whatsapp_template_list"] = whatsappTemplateList
+
+	return result, nil
+}
+
+// SendWhatsappTemplate sends an approved template of the business account of
+// the channel account, the parameters fill the {{n}} placeholders of the body.
+// Templates can be sent outside of the 24 hours customer service window.
+func (wts *WhatsappTemplateService) SendWhatsappTemplate(swtr *presentation.SendWhatsappTemplateRequest, channelAccount *entity.ChannelAccount) (map[string]interface{}, *entity.Message, error) {
+	if channelAccount.ID == 0 {
+		return nil, nil, enum.USER_DO_NOT_HAVE_CHANNEL_ACCOUNT
+	}
+
+	whatsappTemplate, err := wts.whatsappTemplateRepo.GetWhatsappTemplate(channelAccount.WhatsappBusinessId, swtr.TemplateName, swtr.Language)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, enum.ERROR_DATA_NOT_FOUND
+
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if whatsappTemplate.Status != enum.APPROVED {
+		return nil, nil, enum.WHATSAPP_TEMPLATE_NOT_APPROVED
+	}
+
+	if len(swtr.Parameters) != whatsappTemplate.ParameterCount {
+		return nil, nil, enum.INVALID_TEMPLATE_PARAMETERS
+	}
+
+	template := presentation.WhatsappTemplateMetaField{
+		Name:     whatsappTemplate.Name,
+		Language: presentation.WhatsappTemplateLanguageField{Code: whatsappTemplate.Language},
+	}
+	if len(swtr.Parameters) > 0 {
+		component := presentation.WhatsappTemplateComponentField{Type: "body"}
+		for _, parameter := range swtr.Parameters {
+			component.Parameters = append(component.Parameters, presentation.WhatsappTemplateParameterField{
+				Type: "text",
+				Text: parameter,
+			})
+		}
+		template.Components = append(template.Components, component)
+	}
+
+	msmr := presentation.MetaSendMessageRequest{
+		InteractionId: swtr.InteractionId,
+		PlatformId:    swtr.PlatformId,
+		ReporterId:    swtr.ReporterId,
+		Message:       renderTemplate(whatsappTemplate.BodyText, swtr.Parameters),
+		Platform:      enum.WA,
+		SentBy:        enum.AGENT,
+		Template:      &template,
+	}
+
+	return wts.interactionService.SendMessage(&msmr, channelAccount)
+}
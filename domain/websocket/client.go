@@ -166,7 +166,73 @@ func (client *Client) handleNewMessage(jsonMessage []byte) {
 
 	case LeaveRoomAction:
 		client.handleLeaveRoomMessage(message)
+
+	case TransferInteractionAction:
+		client.handleTransferInteractionMessage(message)
+
+	case InteractionAssignedAction:
+		client.handleInteractionAssignedMessage(message)
+
+	case SlaEscalationAction:
+		client.handleSlaEscalationMessage(message)
+
+	case MessageStatusAction:
+		if message.Status != nil {
+			client.room.broadcast <- &message
+		}
+
+	case EmailSubscriptionAlertAction:
+		if message.Mailbox != nil {
+			client.wsServer.escalation <- message.encode()
+		}
+	}
+}
+
+// handleTransferInteractionMessage forwards a transfer to the receiving agent
+// only, whichever room that agent is currently connected to.
+func (client *Client) handleTransferInteractionMessage(message Message) {
+	if message.Transfer == nil {
+		return
+	}
+
+	target := client.wsServer.findUserByID(message.Transfer.ToAgentId)
+	if target == nil {
+		return
+	}
+
+	target.send <- message.encode()
+}
+
+// handleInteractionAssignedMessage forwards a routed interaction to the agent
+// it was assigned to.
+func (client *Client) handleInteractionAssignedMessage(message Message) {
+	if message.Assignment == nil {
+		return
+	}
+
+	target := client.wsServer.findUserByID(message.Assignment.AgentId)
+	if target == nil {
+		return
+	}
+
+	target.send <- message.encode()
+}
+
+// handleSlaEscalationMessage sends an escalation to the supervisor dashboards
+// listening on /ws/listen and to the agent handling the interaction.
+func (client *Client) handleSlaEscalationMessage(message Message) {
+	if message.Escalation == nil {
+		return
+	}
+
+	client.wsServer.escalation <- message.encode()
+
+	target := client.wsServer.findUserByID(message.Escalation.AgentId)
+	if target == nil {
+		return
 	}
+
+	target.send <- message.encode()
 }
 
 func (client *Client) handleLeaveRoomMessage(message Message) {
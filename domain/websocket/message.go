@@ -13,14 +13,24 @@ const UserJoinedAction = "user-join"
 const UserLeftAction = "user-left"
 const RoomJoinedAction = "room-joined"
 const ListOnlineUserAction = "online-users"
+const TransferInteractionAction = "transfer-interaction"
+const InteractionAssignedAction = "interaction-assigned"
+const SlaEscalationAction = "sla-escalation"
+const MessageStatusAction = "message-status"
+const EmailSubscriptionAlertAction = "email-subscription-alert"
 
 type Message struct {
-	Action     string               `json:"action"`
-	Message    presentation.Message `json:"message"`
-	Sender     *Client              `json:"sender"`
-	Room       *Room                `json:"room"`
-	Online     []*Room              `json:"online"`
-	OnlineUser []*Client            `json:"online_user"`
+	Action     string                                          `json:"action"`
+	Message    presentation.Message                            `json:"message"`
+	Transfer   *presentation.InteractionTransferNotification   `json:"transfer,omitempty"`
+	Assignment *presentation.InteractionAssignmentNotification `json:"assignment,omitempty"`
+	Escalation *presentation.SlaEscalationNotification         `json:"escalation,omitempty"`
+	Status     *presentation.MessageStatusNotification         `json:"status,omitempty"`
+	Mailbox    *presentation.EmailSubscriptionAlert            `json:"mailbox,omitempty"`
+	Sender     *Client                                         `json:"sender"`
+	Room       *Room                                           `json:"room"`
+	Online     []*Room                                         `json:"online"`
+	OnlineUser []*Client                                       `json:"online_user"`
 }
 
 func (message *Message) encode() []byte {
@@ -20,6 +20,7 @@ type WsServer struct {
 	registerClient     chan *Client
 	unregisterClient   chan *Client
 	notification       chan []byte
+	escalation         chan []byte
 	rooms              map[string]*Room
 }
 
@@ -40,6 +41,7 @@ func NewWebsocketServer() *WsServer {
 			registerClient:     make(chan *Client),
 			unregisterClient:   make(chan *Client),
 			notification:       make(chan []byte),
+			escalation:         make(chan []byte),
 			rooms:              make(map[string]*Room),
 		}
 	}
@@ -65,6 +67,9 @@ func (server *WsServer) Run() {
 
 		case message := <-server.notification:
 			server.broadcastToClients(message)
+
+		case message := <-server.escalation:
+			server.broadcastToListeners(message)
 		}
 	}
 }
@@ -75,6 +80,12 @@ func (server *WsServer) broadcastToClients(message []byte) {
 	}
 }
 
+func (server *WsServer) broadcastToListeners(message []byte) {
+	for listener := range server.listeners {
+		listener.send <- message
+	}
+}
+
 func (server *WsServer) registerListenerToServer(listener *Listener) {
 	server.listeners[listener] = true
 	server.listOnlineRooms(JoinRoomAction)
@@ -170,12 +181,26 @@ func SetupWebsocketRouter(dbCRM *gorm.DB, dbOmnichannel *gorm.DB, wsServer *WsSe
 	userRepo := repository.NewUserRepository(dbCRM)
 	reporterRepo := repository.NewReporterRepository(dbOmnichannel)
 
-	gmailService := service.NewGmailService()
-	emailRepo := repository.NewEmailRepository(dbOmnichannel, gmailService)
-	threadRepo := repository.NewThreadRepository(dbOmnichannel)
-	emailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo)
+	channelAccountRepo := repository.NewChannelAccountRepository(dbCRM)
+	agentCapacityRepo := repository.NewAgentCapacityRepository(dbOmnichannel)
+	capacityService := service.NewCapacityService(agentCapacityRepo, interactionRepo, userRepo)
+	routingPolicyRepo := repository.NewRoutingPolicyRepository(dbOmnichannel)
+	chatbotFlowRepo := repository.NewChatbotFlowRepository(dbOmnichannel)
+	routingService := service.NewRoutingService(routingPolicyRepo, channelAccountRepo, interactionRepo, userRepo, chatbotFlowRepo, capacityService)
 
-	interactionService := service.NewInteractionService(interactionRepo, messageRepo, userRepo, reporterRepo, emailService, threadRepo)
+	threadRepo := repository.NewThreadRepository(dbOmnichannel)
+	csatSurveyRepo := repository.NewCsatSurveyRepository(dbOmnichannel)
+
+	emailRepo := repository.NewEmailRepository(dbOmnichannel, service.NewGmailService())
+	gmailEmailService := service.NewEmailService(interactionRepo, messageRepo, reporterRepo, emailRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, service.NewAttachmentStore())
+	imapEmailService := service.NewImapEmailService(interactionRepo, messageRepo, reporterRepo, *threadRepo, routingService, csatSurveyRepo, channelAccountRepo, repository.NewImapMailboxRepository(dbOmnichannel), repository.NewWebhookInboxRepository(dbOmnichannel))
+	emailService := service.NewMailboxEmailService(gmailEmailService, imapEmailService, interactionRepo, channelAccountRepo)
+
+	interactionTransferRepo := repository.NewInteractionTransferRepository(dbOmnichannel)
+	slaPolicyRepo := repository.NewSlaPolicyRepository(dbOmnichannel)
+	slaService := service.NewSlaService(slaPolicyRepo, interactionRepo, messageRepo)
+	channelAdapterRegistry := service.NewDefaultChannelAdapterRegistry(channelAccountRepo, interactionRepo, messageRepo, reporterRepo, emailService)
+	interactionService := service.NewInteractionService(interactionRepo, messageRepo, userRepo, reporterRepo, channelAccountRepo, emailService, threadRepo, routingService, capacityService, interactionTransferRepo, slaService, channelAdapterRegistry, service.NewAttachmentStore())
 	websocket := NewWebsocket(interactionService)
 
 	router.GET("/ws/listen", websocket.WesocketListener(wsServer))
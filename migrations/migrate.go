@@ -49,4 +49,130 @@ func main() {
 		logger.Error(fmt.Sprintf("Error when migrating Thread: trace: %+v", err))
 		return
 	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.RoutingPolicy{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating RoutingPolicy: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.AgentCapacity{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating AgentCapacity: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.InteractionTransfer{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating InteractionTransfer: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.SlaPolicy{}, &entity.SlaEscalation{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating SlaPolicy: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.BusinessCalendar{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating BusinessCalendar: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.CannedResponse{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating CannedResponse: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.ChatbotFlow{}, &entity.ChatbotSession{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating ChatbotFlow: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.CsatSurvey{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating CsatSurvey: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.RejectedWebhook{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating RejectedWebhook: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.WebhookInbox{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating WebhookInbox: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.MessageStatus{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating MessageStatus: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.WhatsappTemplate{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating WhatsappTemplate: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.GmailMailbox{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating GmailMailbox: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.ImapMailbox{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating ImapMailbox: trace: %+v", err))
+		return
+	}
+
+	err = dbOmnichannel.AutoMigrate(&entity.MessageAttachment{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error when migrating MessageAttachment: trace: %+v", err))
+		return
+	}
+
+	dbCRM, err := database.InitDB(viper.GetString("Database.CRMDBName"), viper.GetString("Database.CRMDBHost"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("[Migrations] Error when calling InitDB of the CRM database, trace: %+v", err))
+		return
+	}
+
+	// channel_accounts belongs to the CRM database, only the columns read by
+	// the omnichannel service are added there, the rest of the table is left
+	// as the CRM made it
+	logger.Info("[InitDB] Migrating CRM Database")
+	channelAccountColumns := []string{
+		"MetaAppSecret",
+		"TelegramBotId",
+		"TelegramBotToken",
+		"TelegramSecretToken",
+		"EmailAddress",
+		"GmailRefreshToken",
+		"ImapAddress",
+		"ImapUsername",
+		"ImapPassword",
+		"SmtpAddress",
+		"SmtpUsername",
+		"SmtpPassword",
+	}
+	for _, column := range channelAccountColumns {
+		if dbCRM.Migrator().HasColumn(&entity.ChannelAccount{}, column) {
+			continue
+		}
+
+		err = dbCRM.Migrator().AddColumn(&entity.ChannelAccount{}, column)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error when migrating ChannelAccount.%s: trace: %+v", column, err))
+			return
+		}
+	}
 }
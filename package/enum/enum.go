@@ -10,6 +10,7 @@ const (
 	MISSED      = "MISSED"
 	UNPROCESSED = "UNPROCESSED"
 	PROCESSED   = "PROCESSED"
+	BOT         = "BOT"
 )
 
 const (
@@ -18,6 +19,7 @@ const (
 	WA        = "WHATSAPP"
 	EMAIL     = "EMAIL"
 	LIVE_CHAT = "LIVE_CHAT"
+	TELEGRAM  = "TELEGRAM"
 )
 
 // websocket platform
@@ -29,6 +31,7 @@ const (
 const (
 	AGENT    = "AGENT"
 	REPORTER = "REPORTER"
+	SYSTEM   = "SYSTEM"
 )
 
 const (
@@ -36,12 +39,78 @@ const (
 	MENTION = "MENTION"
 )
 
+// webhook inbox platform of the emails an IMAP mailbox could not read, Gmail
+// notifications are queued under EMAIL
+const (
+	IMAP = "IMAP"
+)
+
+// routing strategy
+const (
+	ROUND_ROBIN  = "ROUND_ROBIN"
+	LEAST_LOADED = "LEAST_LOADED"
+	STICKY       = "STICKY"
+)
+
+// canned response scope, responses of a single agent use the AGENT scope
+const (
+	GLOBAL          = "GLOBAL"
+	CHANNEL_ACCOUNT = "CHANNEL_ACCOUNT"
+)
+
+// sla escalation type
+const (
+	FIRST_RESPONSE_NEAR_BREACH = "FIRST_RESPONSE_NEAR_BREACH"
+	FIRST_RESPONSE_BREACH      = "FIRST_RESPONSE_BREACH"
+	RESOLUTION_NEAR_BREACH     = "RESOLUTION_NEAR_BREACH"
+	RESOLUTION_BREACH          = "RESOLUTION_BREACH"
+)
+
 const (
 	IMAGE    = "IMAGE"
 	VIDEO    = "VIDEO"
+	AUDIO    = "AUDIO"
+	DOCUMENT = "DOCUMENT"
 	LOCATION = "LOCATION"
 )
 
+// csat survey status
+const (
+	SENT     = "SENT"
+	ANSWERED = "ANSWERED"
+)
+
+// webhook inbox status, besides PROCESSED
+const (
+	PENDING     = "PENDING"
+	PROCESSING  = "PROCESSING"
+	DEAD_LETTER = "DEAD_LETTER"
+)
+
+// message delivery status, besides SENT
+const (
+	DELIVERED = "DELIVERED"
+	READ      = "READ"
+	FAILED    = "FAILED"
+)
+
+// whatsapp template status, as returned by Meta
+const (
+	APPROVED = "APPROVED"
+)
+
+// gmail watch status, besides ACTIVE and FAILED
+const (
+	EXPIRED     = "EXPIRED"
+	NOT_WATCHED = "NOT_WATCHED"
+)
+
+// reporter fields collected by the chatbot, besides LOCATION
+const (
+	NAME    = "NAME"
+	ADDRESS = "ADDRESS"
+)
+
 const (
 	ROLE_ADMIN_PUSAT         int = 1
 	ROLE_AGENT_PUSAT         int = 2
@@ -148,4 +217,89 @@ const (
 	CHANNEL_ACCOUNT_NOT_MATCH_MSG        = "CHANNEL_ACCOUNT_NOT_MATCH"
 
 	INVALID_PLATFORM_MSG = "INVALID PLATFORM"
+
+	// Routing Policy Response Enum
+	INVALID_ROUTING_STRATEGY_STATUS  = "INVALID_ROUTING_STRATEGY"
+	INVALID_ROUTING_STRATEGY_MESSAGE = "Routing strategy must be one of ROUND_ROBIN, LEAST_LOADED or STICKY"
+
+	// Agent Capacity Response Enum
+	AGENT_CAPACITY_EXCEEDED_STATUS   = "AGENT_CAPACITY_EXCEEDED"
+	AGENT_CAPACITY_EXCEEDED_MESSAGE  = "The agent has reached the maximum number of concurrent interactions"
+	CAPACITY_TARGET_REQUIRED_STATUS  = "CAPACITY_TARGET_REQUIRED"
+	CAPACITY_TARGET_REQUIRED_MESSAGE = "Either agent_id or role field must be filled"
+
+	// Interaction Transfer Response Enum
+	INTERACTION_NOT_IN_PROGRESS_STATUS  = "INTERACTION_NOT_IN_PROGRESS"
+	INTERACTION_NOT_IN_PROGRESS_MESSAGE = "Only an interaction that is in progress can be transferred"
+	INTERACTION_NOT_OWNED_STATUS        = "INTERACTION_NOT_OWNED"
+	INTERACTION_NOT_OWNED_MESSAGE       = "The interaction is handled by another agent"
+	INVALID_TRANSFER_TARGET_STATUS      = "INVALID_TRANSFER_TARGET"
+	INVALID_TRANSFER_TARGET_MESSAGE     = "The interaction can not be transferred to the requested agent"
+
+	// SLA Policy Response Enum
+	INVALID_SLA_TIMER_STATUS            = "INVALID_SLA_TIMER"
+	INVALID_SLA_TIMER_MESSAGE           = "Set a positive first_response_minutes or resolution_minutes"
+	INVALID_NEAR_BREACH_PERCENT_STATUS  = "INVALID_NEAR_BREACH_PERCENT"
+	INVALID_NEAR_BREACH_PERCENT_MESSAGE = "near_breach_percent must be between 0 and 99"
+
+	// Business Calendar Response Enum
+	INVALID_TIMEZONE_STATUS        = "INVALID_TIMEZONE"
+	INVALID_TIMEZONE_MESSAGE       = "Timezone must be a valid IANA timezone, e.g. Asia/Jakarta"
+	INVALID_BUSINESS_HOURS_STATUS  = "INVALID_BUSINESS_HOURS"
+	INVALID_BUSINESS_HOURS_MESSAGE = "Weekly schedule must use weekday keys and HH:MM ranges with open before close"
+	INVALID_HOLIDAY_STATUS         = "INVALID_HOLIDAY"
+	INVALID_HOLIDAY_MESSAGE        = "Holidays must use the YYYY-MM-DD format"
+
+	// Canned Response Response Enum
+	INVALID_CANNED_RESPONSE_SCOPE_STATUS   = "INVALID_CANNED_RESPONSE_SCOPE"
+	INVALID_CANNED_RESPONSE_SCOPE_MESSAGE  = "Scope must be GLOBAL, CHANNEL_ACCOUNT with channel_account_id or AGENT"
+	CANNED_RESPONSE_NOT_ACCESSIBLE_STATUS  = "CANNED_RESPONSE_NOT_ACCESSIBLE"
+	CANNED_RESPONSE_NOT_ACCESSIBLE_MESSAGE = "The canned response is not available for this agent"
+	MESSAGE_REQUIRED_STATUS                = "MESSAGE_REQUIRED"
+	MESSAGE_REQUIRED_MESSAGE               = "One of message, canned_response_id, attachment or location field must be filled"
+
+	// Chatbot Flow Response Enum
+	INVALID_CHATBOT_FLOW_STATUS  = "INVALID_CHATBOT_FLOW"
+	INVALID_CHATBOT_FLOW_MESSAGE = "Steps need unique keys and messages, fields NAME, ADDRESS or LOCATION and next steps that exist"
+
+	// Webhook Response Enum
+	INVALID_WEBHOOK_SIGNATURE_STATUS  = "INVALID_WEBHOOK_SIGNATURE"
+	INVALID_WEBHOOK_SIGNATURE_MESSAGE = "The webhook delivery could not be verified"
+
+	WEBHOOK_INBOX_PROCESSING_STATUS  = "WEBHOOK_INBOX_PROCESSING"
+	WEBHOOK_INBOX_PROCESSING_MESSAGE = "The webhook delivery is being processed and cannot be replayed"
+
+	// Whatsapp Template Response Enum
+	WHATSAPP_TEMPLATE_REQUIRED_STATUS      = "WHATSAPP_TEMPLATE_REQUIRED"
+	WHATSAPP_TEMPLATE_REQUIRED_MESSAGE     = "The reporter has not sent a message in the last 24 hours, send an approved template instead"
+	INVALID_TEMPLATE_PARAMETERS_STATUS     = "INVALID_TEMPLATE_PARAMETERS"
+	INVALID_TEMPLATE_PARAMETERS_MESSAGE    = "The number of parameters does not match the template"
+	WHATSAPP_TEMPLATE_NOT_APPROVED_STATUS  = "WHATSAPP_TEMPLATE_NOT_APPROVED"
+	WHATSAPP_TEMPLATE_NOT_APPROVED_MESSAGE = "Only approved templates can be sent"
+	TEMPLATE_NAME_REQUIRED_STATUS          = "TEMPLATE_NAME_REQUIRED"
+	TEMPLATE_NAME_REQUIRED_MESSAGE         = "template_name and language fields must be filled"
+
+	// Outbound Attachment Response Enum
+	ATTACHMENT_TOO_LARGE_STATUS         = "ATTACHMENT_TOO_LARGE"
+	ATTACHMENT_TOO_LARGE_MESSAGE        = "The attachment can not be larger than 25MB"
+	ATTACHMENT_NOT_SUPPORTED_STATUS     = "ATTACHMENT_NOT_SUPPORTED"
+	ATTACHMENT_NOT_SUPPORTED_MESSAGE    = "Attachments and locations can not be sent on this platform"
+	ATTACHMENT_TYPE_NOT_ALLOWED_STATUS  = "ATTACHMENT_TYPE_NOT_ALLOWED"
+	ATTACHMENT_TYPE_NOT_ALLOWED_MESSAGE = "Only images, videos, audios and documents can be sent"
+	LIVE_CHAT_SESSION_INVALID_STATUS    = "LIVE_CHAT_SESSION_INVALID"
+	LIVE_CHAT_SESSION_INVALID_MESSAGE   = "The live chat session token is invalid or not provided"
+
+	// Outbound Conversation Response Enum
+	SUBJECT_REQUIRED_STATUS             = "SUBJECT_REQUIRED"
+	SUBJECT_REQUIRED_MESSAGE            = "Subject field must be filled to start an email conversation"
+	REPORTER_NOT_REACHABLE_STATUS       = "REPORTER_NOT_REACHABLE"
+	REPORTER_NOT_REACHABLE_MESSAGE      = "The reporter has not contacted us on this platform yet"
+	INTERACTION_ALREADY_ONGOING_STATUS  = "INTERACTION_ALREADY_ONGOING"
+	INTERACTION_ALREADY_ONGOING_MESSAGE = "The reporter already has an ongoing interaction"
+	MESSAGING_WINDOW_EXPIRED_STATUS     = "MESSAGING_WINDOW_EXPIRED"
+	MESSAGING_WINDOW_EXPIRED_MESSAGE    = "The reporter has not sent a message in the last 7 days, the platform does not allow messaging them"
+
+	// Email Subscription Response Enum
+	EMAIL_SUBSCRIPTION_UNHEALTHY_STATUS  = "EMAIL_SUBSCRIPTION_UNHEALTHY"
+	EMAIL_SUBSCRIPTION_UNHEALTHY_MESSAGE = "The push subscription of at least one mailbox is not active, new emails may not arrive"
 )
@@ -9,4 +9,29 @@ var (
 	PLATFORM_ID_NOT_SET              = errors.New("PLATFORM_ID_NOT_SET")
 	PLATFORM_ACCESS_TOKEN_NOT_SET    = errors.New("PLATFORM_ACCESS_TOKEN_NOT_SET")
 	CHANNEL_ACCOUNT_NOT_MATCH        = errors.New("CHANNEL_ACCOUNT_NOT_MATCH")
+	AGENT_CAPACITY_EXCEEDED          = errors.New("AGENT_CAPACITY_EXCEEDED")
+	INTERACTION_NOT_IN_PROGRESS      = errors.New("INTERACTION_NOT_IN_PROGRESS")
+	INTERACTION_NOT_OWNED            = errors.New("INTERACTION_NOT_OWNED")
+	INVALID_TRANSFER_TARGET          = errors.New("INVALID_TRANSFER_TARGET")
+	CANNED_RESPONSE_NOT_ACCESSIBLE   = errors.New("CANNED_RESPONSE_NOT_ACCESSIBLE")
+	INVALID_PLATFORM                 = errors.New("INVALID_PLATFORM")
+	WEBHOOK_SIGNATURE_MISSING        = errors.New("WEBHOOK_SIGNATURE_MISSING")
+	INVALID_WEBHOOK_SIGNATURE        = errors.New("INVALID_WEBHOOK_SIGNATURE")
+	WEBHOOK_SECRET_NOT_SET           = errors.New("WEBHOOK_SECRET_NOT_SET")
+	WEBHOOK_ENTRIES_NOT_MATCH        = errors.New("WEBHOOK_ENTRIES_NOT_MATCH")
+	WEBHOOK_INBOX_PROCESSING         = errors.New("WEBHOOK_INBOX_PROCESSING")
+	WEBHOOK_PROCESSOR_NOT_SET        = errors.New("WEBHOOK_PROCESSOR_NOT_SET")
+	WHATSAPP_WINDOW_EXPIRED          = errors.New("WHATSAPP_WINDOW_EXPIRED")
+	INVALID_TEMPLATE_PARAMETERS      = errors.New("INVALID_TEMPLATE_PARAMETERS")
+	WHATSAPP_TEMPLATE_NOT_APPROVED   = errors.New("WHATSAPP_TEMPLATE_NOT_APPROVED")
+	REPORTER_NOT_REACHABLE           = errors.New("REPORTER_NOT_REACHABLE")
+	INTERACTION_ALREADY_ONGOING      = errors.New("INTERACTION_ALREADY_ONGOING")
+	MESSAGING_WINDOW_EXPIRED         = errors.New("MESSAGING_WINDOW_EXPIRED")
+	ATTACHMENT_TOO_LARGE             = errors.New("ATTACHMENT_TOO_LARGE")
+	ATTACHMENT_NOT_SUPPORTED         = errors.New("ATTACHMENT_NOT_SUPPORTED")
+	ATTACHMENT_TYPE_NOT_ALLOWED      = errors.New("ATTACHMENT_TYPE_NOT_ALLOWED")
+	LIVE_CHAT_SESSION_INVALID        = errors.New("LIVE_CHAT_SESSION_INVALID")
+	GMAIL_HISTORY_EXPIRED            = errors.New("GMAIL_HISTORY_EXPIRED")
+	EMAIL_SUBSCRIPTION_UNHEALTHY     = errors.New("EMAIL_SUBSCRIPTION_UNHEALTHY")
+	MESSAGE_NOT_FOUND                = errors.New("MESSAGE_NOT_FOUND")
 )
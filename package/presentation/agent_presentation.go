@@ -1,6 +1,7 @@
 package presentation
 
 import (
+	"Omnichannel-CRM/package/enum"
 	"strconv"
 	"strings"
 
@@ -8,11 +9,63 @@ import (
 )
 
 type AgentDashboardData struct {
-	AgentId            string `json:"agent_id"`
-	AgentName          string `json:"agent_name"`
-	ActiveInteraction  int64  `json:"active_interaction"`
-	InteractionHandled int64  `json:"interaction_handled"`
-	Status             string `json:"status"`
+	AgentId                string  `json:"agent_id"`
+	AgentName              string  `json:"agent_name"`
+	ActiveInteraction      int64   `json:"active_interaction"`
+	RemainingChatCapacity  *int64  `json:"remaining_chat_capacity"`
+	RemainingEmailCapacity *int64  `json:"remaining_email_capacity"`
+	InteractionHandled     int64   `json:"interaction_handled"`
+	CsatCount              int64   `json:"csat_count"`
+	CsatAverage            float64 `json:"csat_average"`
+	Status                 string  `json:"status"`
+}
+
+type AgentRemainingCapacity struct {
+	RemainingChatCapacity  *int64 `json:"remaining_chat_capacity"`
+	RemainingEmailCapacity *int64 `json:"remaining_email_capacity"`
+}
+
+type AgentCapacityModel struct {
+	AgentId    string `json:"agent_id"`
+	Role       int    `json:"role"`
+	ChatLimit  int64  `json:"chat_limit"`
+	EmailLimit int64  `json:"email_limit"`
+}
+
+// UpdateAgentCapacityModel leaves a limit unchanged when it is omitted, 0 sets
+// it back to unlimited.
+type UpdateAgentCapacityModel struct {
+	AgentCapacityId uint   `json:"agent_capacity_id"`
+	ChatLimit       *int64 `json:"chat_limit"`
+	EmailLimit      *int64 `json:"email_limit"`
+}
+
+type DeleteAgentCapacityModel struct {
+	AgentCapacityId uint `json:"agent_capacity_id"`
+}
+
+func (acm *AgentCapacityModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if acm.AgentId == "" && acm.Role == 0 {
+		errorMessage["errorStatus"] = enum.CAPACITY_TARGET_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.CAPACITY_TARGET_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func (uacm *UpdateAgentCapacityModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if uacm.AgentCapacityId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
 }
 
 func ParseGetListAgentFilters(c *gin.Context) (map[string]interface{}, error) {
@@ -0,0 +1,66 @@
+package presentation
+
+import (
+	"Omnichannel-CRM/package/enum"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxOutboundAttachmentSize is the largest file agents can upload, the limit
+// of the Messenger attachment upload.
+const MaxOutboundAttachmentSize = 25 << 20
+
+// OutboundAttachment is a file uploaded by an agent. The content is kept for
+// the platforms that need the file itself, Url is set once it is stored.
+type OutboundAttachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+	Url         string
+}
+
+// ParseOutboundAttachment reads the "attachment" file of a multipart request,
+// it returns nil when the request has none.
+func ParseOutboundAttachment(c *gin.Context) (*OutboundAttachment, error) {
+	fileHeader, err := c.FormFile("attachment")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if fileHeader.Size > MaxOutboundAttachmentSize {
+		return nil, enum.ATTACHMENT_TOO_LARGE
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, MaxOutboundAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > MaxOutboundAttachmentSize {
+		return nil, enum.ATTACHMENT_TOO_LARGE
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(content)
+	}
+
+	outboundAttachment := OutboundAttachment{
+		Name:        fileHeader.Filename,
+		ContentType: contentType,
+		Content:     content,
+	}
+
+	return &outboundAttachment, nil
+}
@@ -0,0 +1,120 @@
+package presentation
+
+import (
+	"Omnichannel-CRM/package/enum"
+	"strings"
+	"time"
+)
+
+type BusinessHoursRange struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+type BusinessCalendarModel struct {
+	ChannelAccountId uint                            `json:"channel_account_id"`
+	Timezone         string                          `json:"timezone"`
+	WeeklySchedule   map[string][]BusinessHoursRange `json:"weekly_schedule"`
+	Holidays         []string                        `json:"holidays"`
+	AutoReplyMessage string                          `json:"auto_reply_message"`
+	IsActive         bool                            `json:"is_active"`
+}
+
+type UpdateBusinessCalendarModel struct {
+	BusinessCalendarId uint                            `json:"business_calendar_id"`
+	Timezone           string                          `json:"timezone"`
+	WeeklySchedule     map[string][]BusinessHoursRange `json:"weekly_schedule"`
+	Holidays           []string                        `json:"holidays"`
+	AutoReplyMessage   string                          `json:"auto_reply_message"`
+	IsActive           *bool                           `json:"is_active"`
+}
+
+type DeleteBusinessCalendarModel struct {
+	BusinessCalendarId uint `json:"business_calendar_id"`
+}
+
+var weekdays = []string{"SUNDAY", "MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY", "SATURDAY"}
+
+func isWeekday(day string) bool {
+	for _, v := range weekdays {
+		if v == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateBusinessCalendar checks the timezone, the weekly schedule keyed by
+// upper case weekday with "15:04" ranges, and the "2006-01-02" holidays.
+func ValidateBusinessCalendar(timezone string, weeklySchedule map[string][]BusinessHoursRange, holidays []string) map[string]string {
+	errorMessage := make(map[string]string)
+
+	if timezone != "" {
+		_, err := time.LoadLocation(timezone)
+		if err != nil {
+			errorMessage["errorStatus"] = enum.INVALID_TIMEZONE_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_TIMEZONE_MESSAGE
+			return errorMessage
+		}
+	}
+
+	for day, ranges := range weeklySchedule {
+		if !isWeekday(strings.ToUpper(day)) {
+			errorMessage["errorStatus"] = enum.INVALID_BUSINESS_HOURS_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_BUSINESS_HOURS_MESSAGE
+			return errorMessage
+		}
+
+		for _, v := range ranges {
+			openTime, err := time.Parse("15:04", v.Open)
+			if err != nil {
+				errorMessage["errorStatus"] = enum.INVALID_BUSINESS_HOURS_STATUS
+				errorMessage["errorMessage"] = enum.INVALID_BUSINESS_HOURS_MESSAGE
+				return errorMessage
+			}
+
+			closeTime, err := time.Parse("15:04", v.Close)
+			if err != nil || !closeTime.After(openTime) {
+				errorMessage["errorStatus"] = enum.INVALID_BUSINESS_HOURS_STATUS
+				errorMessage["errorMessage"] = enum.INVALID_BUSINESS_HOURS_MESSAGE
+				return errorMessage
+			}
+		}
+	}
+
+	for _, v := range holidays {
+		_, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			errorMessage["errorStatus"] = enum.INVALID_HOLIDAY_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_HOLIDAY_MESSAGE
+			return errorMessage
+		}
+	}
+
+	return errorMessage
+}
+
+func (bcm *BusinessCalendarModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if bcm.Timezone == "" || len(bcm.WeeklySchedule) == 0 || bcm.AutoReplyMessage == "" {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateBusinessCalendar(bcm.Timezone, bcm.WeeklySchedule, bcm.Holidays)
+}
+
+func (ubcm *UpdateBusinessCalendarModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if ubcm.BusinessCalendarId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateBusinessCalendar(ubcm.Timezone, ubcm.WeeklySchedule, ubcm.Holidays)
+}
@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"Omnichannel-CRM/package/enum"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CannedResponseModel struct {
+	Title            string `json:"title"`
+	Shortcut         string `json:"shortcut"`
+	Content          string `json:"content"`
+	Scope            string `json:"scope"`
+	ChannelAccountId uint   `json:"channel_account_id"`
+	AgentId          string `json:"agent_id"`
+}
+
+type UpdateCannedResponseModel struct {
+	CannedResponseId uint   `json:"canned_response_id"`
+	Title            string `json:"title"`
+	Shortcut         string `json:"shortcut"`
+	Content          string `json:"content"`
+}
+
+type DeleteCannedResponseModel struct {
+	CannedResponseId uint `json:"canned_response_id"`
+}
+
+func ValidateCannedResponseScope(scope string, channelAccountId uint) map[string]string {
+	errorMessage := make(map[string]string)
+
+	if scope != enum.GLOBAL && scope != enum.CHANNEL_ACCOUNT && scope != enum.AGENT {
+		errorMessage["errorStatus"] = enum.INVALID_CANNED_RESPONSE_SCOPE_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_CANNED_RESPONSE_SCOPE_MESSAGE
+		return errorMessage
+	}
+
+	if scope == enum.CHANNEL_ACCOUNT && channelAccountId == 0 {
+		errorMessage["errorStatus"] = enum.INVALID_CANNED_RESPONSE_SCOPE_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_CANNED_RESPONSE_SCOPE_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func (crm *CannedResponseModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if crm.Title == "" || crm.Content == "" {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateCannedResponseScope(crm.Scope, crm.ChannelAccountId)
+}
+
+func (ucrm *UpdateCannedResponseModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if ucrm.CannedResponseId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func ParseGetListCannedResponseFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	scopeQuery := c.Query("scope")
+	channelAccountIdQuery := c.Query("channel_account_id")
+	agentIdQuery := c.Query("agent_id")
+
+	if scopeQuery != "" {
+		filters["scope"] = scopeQuery
+	}
+
+	if channelAccountIdQuery != "" {
+		channelAccountId, err := strconv.ParseUint(channelAccountIdQuery, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		filters["channel_account_id"] = uint(channelAccountId)
+	}
+
+	if agentIdQuery != "" {
+		filters["agent_id"] = agentIdQuery
+	}
+
+	return filters, nil
+}
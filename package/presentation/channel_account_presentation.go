@@ -9,6 +9,17 @@ type ChannelAccountModel struct {
 	FacebookAccessToken  string `json:"facebook_access_token"`
 	InstagramAccessToken string `json:"instagram_access_token"`
 	WhatsappAccessToken  string `json:"whatsapp_access_token"`
+	MetaAppSecret        string `json:"meta_app_secret"`
+	TelegramBotToken     string `json:"telegram_bot_token"`
+	TelegramSecretToken  string `json:"telegram_secret_token"`
+	EmailAddress         string `json:"email_address"`
+	GmailRefreshToken    string `json:"gmail_refresh_token"`
+	ImapAddress          string `json:"imap_address"`
+	ImapUsername         string `json:"imap_username"`
+	ImapPassword         string `json:"imap_password"`
+	SmtpAddress          string `json:"smtp_address"`
+	SmtpUsername         string `json:"smtp_username"`
+	SmtpPassword         string `json:"smtp_password"`
 }
 
 type UpdateChannelAccountModel struct {
@@ -21,6 +32,17 @@ type UpdateChannelAccountModel struct {
 	FacebookAccessToken  string `json:"facebook_access_token"`
 	InstagramAccessToken string `json:"instagram_access_token"`
 	WhatsappAccessToken  string `json:"whatsapp_access_token"`
+	MetaAppSecret        string `json:"meta_app_secret"`
+	TelegramBotToken     string `json:"telegram_bot_token"`
+	TelegramSecretToken  string `json:"telegram_secret_token"`
+	EmailAddress         string `json:"email_address"`
+	GmailRefreshToken    string `json:"gmail_refresh_token"`
+	ImapAddress          string `json:"imap_address"`
+	ImapUsername         string `json:"imap_username"`
+	ImapPassword         string `json:"imap_password"`
+	SmtpAddress          string `json:"smtp_address"`
+	SmtpUsername         string `json:"smtp_username"`
+	SmtpPassword         string `json:"smtp_password"`
 }
 
 type DeleteChannelAccountModel struct {
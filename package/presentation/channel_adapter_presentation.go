@@ -0,0 +1,42 @@
+package presentation
+
+import "time"
+
+// InboundMessage is a received message normalized by a channel adapter.
+type InboundMessage struct {
+	PlatformId       string
+	InteractionType  string
+	MetaReporterId   string
+	ReporterName     string
+	ReporterUsername string
+	MentionMediaId   string
+	MentionMediaUrl  string
+	MessageId        string
+	Message          string
+	MessageTimestamp time.Time
+	AttachmentType   string
+	AttachmentUrl    string
+
+	// MediaId is resolved by the adapter's FetchMedia before the message is stored
+	MediaId string
+}
+
+// MessageStatusUpdate is a delivery status reported by a channel for a message
+// sent to a reporter, MessageId is the id returned when the message was sent.
+type MessageStatusUpdate struct {
+	MessageId       string
+	RecipientId     string
+	Status          string
+	StatusTimestamp time.Time
+	ErrorCode       int
+	ErrorTitle      string
+	ErrorMessage    string
+}
+
+// WebhookDelivery is a raw webhook request as received by the handler.
+type WebhookDelivery struct {
+	PlatformId string
+	Signature  string
+	RemoteAddr string
+	Body       []byte
+}
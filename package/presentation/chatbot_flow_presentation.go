@@ -0,0 +1,109 @@
+package presentation
+
+import "Omnichannel-CRM/package/enum"
+
+type ChatbotOption struct {
+	Keyword string `json:"keyword"`
+	Next    string `json:"next"`
+}
+
+// ChatbotStep is a node of the flow. The reply to Message is stored into
+// Field when set, then the flow moves to the Next of the matching option, or
+// to Next when the step has no options. An empty Next ends the flow.
+type ChatbotStep struct {
+	Key     string          `json:"key"`
+	Message string          `json:"message"`
+	Field   string          `json:"field,omitempty"`
+	Options []ChatbotOption `json:"options,omitempty"`
+	Next    string          `json:"next,omitempty"`
+}
+
+type ChatbotFlowModel struct {
+	Name             string        `json:"name"`
+	ChannelAccountId uint          `json:"channel_account_id"`
+	Platform         string        `json:"platform"`
+	Steps            []ChatbotStep `json:"steps"`
+	HandoverKeywords []string      `json:"handover_keywords"`
+	HandoverMessage  string        `json:"handover_message"`
+	IsActive         bool          `json:"is_active"`
+}
+
+type UpdateChatbotFlowModel struct {
+	ChatbotFlowId    uint          `json:"chatbot_flow_id"`
+	Name             string        `json:"name"`
+	Platform         string        `json:"platform"`
+	Steps            []ChatbotStep `json:"steps"`
+	HandoverKeywords []string      `json:"handover_keywords"`
+	HandoverMessage  string        `json:"handover_message"`
+	IsActive         *bool         `json:"is_active"`
+}
+
+type DeleteChatbotFlowModel struct {
+	ChatbotFlowId uint `json:"chatbot_flow_id"`
+}
+
+// ValidateChatbotFlow checks the platform, the collected fields and that every
+// next step refers to an existing step key.
+func ValidateChatbotFlow(platform string, steps []ChatbotStep) map[string]string {
+	errorMessage := make(map[string]string)
+
+	if platform != "" && platform != enum.WA && platform != enum.LIVE_CHAT {
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_PLATFORM_MSG
+		return errorMessage
+	}
+
+	stepKeys := make(map[string]bool)
+	for _, v := range steps {
+		if v.Key == "" || v.Message == "" || stepKeys[v.Key] {
+			errorMessage["errorStatus"] = enum.INVALID_CHATBOT_FLOW_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_CHATBOT_FLOW_MESSAGE
+			return errorMessage
+		}
+		stepKeys[v.Key] = true
+	}
+
+	for _, v := range steps {
+		isValidStep := v.Field == "" || v.Field == enum.NAME || v.Field == enum.ADDRESS || v.Field == enum.LOCATION
+		if v.Next != "" && !stepKeys[v.Next] {
+			isValidStep = false
+		}
+		for _, option := range v.Options {
+			if option.Keyword == "" || (option.Next != "" && !stepKeys[option.Next]) {
+				isValidStep = false
+			}
+		}
+
+		if !isValidStep {
+			errorMessage["errorStatus"] = enum.INVALID_CHATBOT_FLOW_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_CHATBOT_FLOW_MESSAGE
+			return errorMessage
+		}
+	}
+
+	return errorMessage
+}
+
+func (cfm *ChatbotFlowModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if cfm.Name == "" || len(cfm.Steps) == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateChatbotFlow(cfm.Platform, cfm.Steps)
+}
+
+func (ucfm *UpdateChatbotFlowModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if ucfm.ChatbotFlowId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateChatbotFlow(ucfm.Platform, ucfm.Steps)
+}
@@ -0,0 +1,69 @@
+package presentation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AgentCsatSummary struct {
+	AgentId     string  `json:"agent_id"`
+	CsatCount   int64   `json:"csat_count"`
+	CsatAverage float64 `json:"csat_average"`
+}
+
+// csatKeywords maps the keyword replies to a score on the 1-5 scale.
+var csatKeywords = map[string]int64{
+	"sangat puas":       5,
+	"excellent":         5,
+	"puas":              4,
+	"good":              4,
+	"cukup":             3,
+	"ok":                3,
+	"tidak puas":        2,
+	"bad":               2,
+	"sangat tidak puas": 1,
+	"terrible":          1,
+}
+
+// ParseCsatScore reads a 1-5 score from the first line of the reply, either as
+// a number or as one of the rating keywords.
+func ParseCsatScore(reply string) (int64, bool) {
+	firstLine := strings.TrimSpace(strings.SplitN(strings.TrimSpace(reply), "\n", 2)[0])
+	firstLine = strings.ToLower(strings.Trim(firstLine, ".!"))
+
+	score, err := strconv.ParseInt(firstLine, 10, 64)
+	if err == nil {
+		return score, score >= 1 && score <= 5
+	}
+
+	score, ok := csatKeywords[firstLine]
+	return score, ok
+}
+
+func ParseGetListCsatSurveyFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	interactionIdQuery := c.Query("interaction_id")
+	agentIdQuery := c.Query("agent_id")
+	statusQuery := c.Query("status")
+
+	if interactionIdQuery != "" {
+		interactionId, err := strconv.ParseUint(interactionIdQuery, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		filters["interaction_id"] = uint(interactionId)
+	}
+
+	if agentIdQuery != "" {
+		filters["agent_id"] = agentIdQuery
+	}
+
+	if statusQuery != "" {
+		filters["status"] = statusQuery
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,21 @@
+package presentation
+
+import "time"
+
+// EmailSubscriptionAlert is sent to the supervisor dashboards when the watch of
+// a Gmail mailbox could not be renewed.
+type EmailSubscriptionAlert struct {
+	EmailAddress    string     `json:"email_address"`
+	Error           string     `json:"error"`
+	WatchExpiration *time.Time `json:"watch_expiration"`
+}
+
+type EmailSubscriptionHealth struct {
+	EmailAddress    string     `json:"email_address"`
+	Status          string     `json:"status"`
+	HistoryId       uint64     `json:"history_id"`
+	LastSyncedAt    *time.Time `json:"last_synced_at"`
+	WatchExpiration *time.Time `json:"watch_expiration"`
+	WatchRenewedAt  *time.Time `json:"watch_renewed_at"`
+	WatchError      string     `json:"watch_error"`
+}
@@ -21,11 +21,23 @@ type Message struct {
 	MetaMessageId    string     `json:"mid"`
 	Message          string     `json:"message"`
 	MessageTimestamp time.Time  `json:"message_timestamp"`
+	AttachmentType   string     `json:"attachment_type"`
+	AttachmentUrl    string     `json:"attachment_url"`
 	SentBy           string     `json:"sent_by"`
 	IsRead           bool       `json:"is_read"`
 	IsDeleted        bool       `json:"is_deleted"`
 }
 
+type MessageStatusNotification struct {
+	MessageId       uint      `json:"message_id"`
+	MetaMessageId   string    `json:"mid"`
+	InteractionId   uint      `json:"interaction_id"`
+	Status          string    `json:"status"`
+	ErrorCode       int       `json:"error_code"`
+	ErrorMessage    string    `json:"error_message"`
+	StatusTimestamp time.Time `json:"status_timestamp"`
+}
+
 type MetaInteractionRequest struct {
 	Object string       `json:"object"`
 	Entry  []EntryField `json:"entry"`
@@ -112,27 +124,94 @@ type ClaimInteractionRequest struct {
 	InteractionId uint `json:"interaction_id"`
 }
 
-type DashboardInteractionList struct {
+type TransferInteractionRequest struct {
+	InteractionId uint   `json:"interaction_id"`
+	ToAgentId     string `json:"to_agent_id"`
+	Note          string `json:"note"`
+}
+
+// StartOutboundConversationRequest opens an interaction with a known reporter.
+// Subject is only used for EMAIL, TemplateName, Language and Parameters send a
+// WhatsApp template instead of Message.
+type StartOutboundConversationRequest struct {
+	ReporterId   uint     `json:"reporter_id"`
+	Platform     string   `json:"platform"`
+	Message      string   `json:"message"`
+	Subject      string   `json:"subject"`
+	TemplateName string   `json:"template_name"`
+	Language     string   `json:"language"`
+	Parameters   []string `json:"parameters"`
+}
+
+type InteractionTransferNotification struct {
+	TransferId    uint      `json:"transfer_id"`
+	InteractionId uint      `json:"interaction_id"`
+	FromAgentId   string    `json:"from_agent_id"`
+	ToAgentId     string    `json:"to_agent_id"`
+	TransferredBy string    `json:"transferred_by"`
+	Note          string    `json:"note"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
+
+type InteractionAssignmentNotification struct {
 	InteractionId   uint      `json:"interaction_id"`
-	PlatformId      string    `json:"platform_id"`
-	ReporterId      uint      `json:"reporter_id"`
-	ConversationId  string    `json:"conversation_id"`
-	MentionMediaId  string    `json:"media_id"`
 	AgentId         string    `json:"agent_id"`
-	AgentName       string    `json:"agent_name"`
-	Status          string    `json:"status"`
 	Platform        string    `json:"platform"`
-	InteractionType string    `json:"interaction_type"`
-	Duration        time.Time `json:"duration"`
+	RoutingStrategy string    `json:"routing_strategy"`
+	AssignedAt      time.Time `json:"assigned_at"`
+}
+
+type DashboardInteractionList struct {
+	InteractionId   uint            `json:"interaction_id"`
+	PlatformId      string          `json:"platform_id"`
+	ReporterId      uint            `json:"reporter_id"`
+	ConversationId  string          `json:"conversation_id"`
+	MentionMediaId  string          `json:"media_id"`
+	AgentId         string          `json:"agent_id"`
+	AgentName       string          `json:"agent_name"`
+	Status          string          `json:"status"`
+	Platform        string          `json:"platform"`
+	InteractionType string          `json:"interaction_type"`
+	RoutingStrategy string          `json:"routing_strategy"`
+	Sla             *InteractionSla `json:"sla"`
+
+	InteractionDurations
 }
 
 type MetaSendMessageRequest struct {
-	InteractionId uint   `json:"interaction_id"`
-	PlatformId    string `json:"platform_id,omitempty"`
-	ReporterId    uint   `json:"reporter_id,omitempty"`
-	Message       string `json:"message"`
-	Platform      string `json:"platform"`
-	SentBy        string `json:"sent_by"`
+	InteractionId    uint   `json:"interaction_id" form:"interaction_id"`
+	PlatformId       string `json:"platform_id,omitempty" form:"platform_id"`
+	ReporterId       uint   `json:"reporter_id,omitempty" form:"reporter_id"`
+	Message          string `json:"message" form:"message"`
+	CannedResponseId uint   `json:"canned_response_id,omitempty" form:"canned_response_id"`
+	Platform         string `json:"platform" form:"platform"`
+	SentBy           string `json:"sent_by" form:"sent_by"`
+
+	// Location sends a location pin, Message then holds its JSON like the one
+	// of an inbound location
+	Location *WhatsappLocation `json:"location,omitempty" form:"-"`
+
+	// Attachment is the file of a multipart request, AttachmentType and
+	// AttachmentUrl are set once it is kept in the attachment store
+	Attachment     *OutboundAttachment `json:"-" form:"-"`
+	AttachmentType string              `json:"-" form:"-"`
+	AttachmentUrl  string              `json:"-" form:"-"`
+
+	// Template is set by the send-template API only, Message then holds the
+	// rendered template text
+	Template *WhatsappTemplateMetaField `json:"-" form:"-"`
+}
+
+func (msmr *MetaSendMessageRequest) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if msmr.Message == "" && msmr.CannedResponseId == 0 && msmr.Attachment == nil && msmr.Location == nil {
+		errorMessage["errorStatus"] = enum.MESSAGE_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.MESSAGE_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
 }
 
 type MessengerSendMessageMetaRequest struct {
@@ -142,6 +221,21 @@ type MessengerSendMessageMetaRequest struct {
 	Message       MessageSendMetaField `json:"message"`
 }
 
+// MessengerAttachmentMetaField is the message of an attachment upload, the
+// file itself is the filedata part of the multipart request.
+type MessengerAttachmentMetaField struct {
+	Attachment MessengerAttachmentTypeField `json:"attachment"`
+}
+
+type MessengerAttachmentTypeField struct {
+	Type    string                          `json:"type"`
+	Payload MessengerAttachmentPayloadField `json:"payload"`
+}
+
+type MessengerAttachmentPayloadField struct {
+	IsReusable bool `json:"is_reusable"`
+}
+
 type MessengerSendEmailRequest struct {
 	InteractionId uint   `json:"interaction_id"`
 	Message       string `json:"message"`
@@ -162,11 +256,17 @@ type InteractionLastMessage struct {
 }
 
 type WhatsappSendMessageMetaRequest struct {
-	MessagingProduct string                   `json:"messaging_product"`
-	RecipientType    string                   `json:"recipient_type"`
-	Recipient        string                   `json:"to"`
-	MessageType      string                   `json:"type"`
-	Text             WhatsappMessageMetaField `json:"text"`
+	MessagingProduct string                     `json:"messaging_product"`
+	RecipientType    string                     `json:"recipient_type"`
+	Recipient        string                     `json:"to"`
+	MessageType      string                     `json:"type"`
+	Text             *WhatsappMessageMetaField  `json:"text,omitempty"`
+	Template         *WhatsappTemplateMetaField `json:"template,omitempty"`
+	Image            *WhatsappMediaMetaField    `json:"image,omitempty"`
+	Video            *WhatsappMediaMetaField    `json:"video,omitempty"`
+	Audio            *WhatsappMediaMetaField    `json:"audio,omitempty"`
+	Document         *WhatsappMediaMetaField    `json:"document,omitempty"`
+	Location         *WhatsappLocation          `json:"location,omitempty"`
 }
 
 type WhatsappMessageMetaField struct {
@@ -174,6 +274,18 @@ type WhatsappMessageMetaField struct {
 	Body       string `json:"body"`
 }
 
+// WhatsappMediaMetaField is a media uploaded to the media endpoint, audio has
+// no caption and only documents have a file name.
+type WhatsappMediaMetaField struct {
+	Id       string `json:"id"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+type WhatsappMediaUploadMetaResponse struct {
+	Id string `json:"id"`
+}
+
 type WhatsappSendMessageMetaResponse struct {
 	MessagingProduct string                       `json:"messaging_product"`
 	Contacts         []WhatsappContactMetaField   `json:"contacts"`
@@ -189,33 +301,70 @@ type WhatsappMessageIdMetaField struct {
 	Id string `json:"id"`
 }
 
+type InteractionDurations struct {
+	HandleTimeSeconds    int64 `json:"handle_time_seconds"`
+	WaitTimeSeconds      int64 `json:"wait_time_seconds"`
+	TotalOpenTimeSeconds int64 `json:"total_open_time_seconds"`
+}
+
+// GetInteractionDurations measures an interaction up to its close, or up to now
+// while it is still open. Handle time runs from the first claim, wait time from
+// creation to the first agent response.
+func GetInteractionDurations(interaction entity.Interaction, now time.Time) InteractionDurations {
+	var durations InteractionDurations
+
+	end := now
+	if interaction.ClosedAt != nil {
+		end = *interaction.ClosedAt
+	}
+
+	durations.TotalOpenTimeSeconds = int64(end.Sub(interaction.CreatedAt).Seconds())
+
+	if interaction.ClaimedAt != nil {
+		durations.HandleTimeSeconds = int64(end.Sub(*interaction.ClaimedAt).Seconds())
+	}
+
+	if interaction.FirstResponseAt != nil {
+		durations.WaitTimeSeconds = int64(interaction.FirstResponseAt.Sub(interaction.CreatedAt).Seconds())
+	} else {
+		durations.WaitTimeSeconds = durations.TotalOpenTimeSeconds
+	}
+
+	return durations
+}
+
 type InteractionReporterData struct {
 	Interaction entity.Interaction `json:"interaction"`
 	Reporter    entity.Reporter    `json:"reporter"`
 }
 
 type SendInteractionDataCRMRequest struct {
-	InteractionId       uint      `json:"id"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
-	DeletedAt           time.Time `json:"deleted_at"`
-	ReporterName        string    `json:"name"`
-	ReporterGender      string    `json:"gender"`
-	ReporterPhoneNumber string    `json:"phoneNumber"`
-	ReporterEmail       string    `json:"email"`
-	ReporterAddress     string    `json:"address"`
-	PlatformId          string    `json:"platformId"`
-	ConversationId      string    `json:"conversationId"`
-	ReporterId          uint      `json:"reporterId"`
-	MentionMediaId      string    `json:"mentionMediaId"`
-	MentionMediaUrl     string    `json:"mentionMediaUrl"`
-	AgentId             string    `json:"agentId"`
-	Status              string    `json:"status"`
-	Platform            string    `json:"platform"`
-	InteractionType     string    `json:"interactionType"`
-	Latitude            string    `json:"latitude"`
-	Longitude           string    `json:"longitude"`
-	Duration            time.Time `json:"duration"`
+	InteractionId       uint       `json:"id"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	DeletedAt           time.Time  `json:"deleted_at"`
+	ReporterName        string     `json:"name"`
+	ReporterGender      string     `json:"gender"`
+	ReporterPhoneNumber string     `json:"phoneNumber"`
+	ReporterEmail       string     `json:"email"`
+	ReporterAddress     string     `json:"address"`
+	PlatformId          string     `json:"platformId"`
+	ConversationId      string     `json:"conversationId"`
+	ReporterId          uint       `json:"reporterId"`
+	MentionMediaId      string     `json:"mentionMediaId"`
+	MentionMediaUrl     string     `json:"mentionMediaUrl"`
+	AgentId             string     `json:"agentId"`
+	Status              string     `json:"status"`
+	Platform            string     `json:"platform"`
+	InteractionType     string     `json:"interactionType"`
+	Latitude            string     `json:"latitude"`
+	Longitude           string     `json:"longitude"`
+	ClaimedAt           *time.Time `json:"claimedAt"`
+	FirstResponseAt     *time.Time `json:"firstResponseAt"`
+	ClosedAt            *time.Time `json:"closedAt"`
+	Duration            int64      `json:"duration"`
+	HandleTime          int64      `json:"handleTime"`
+	WaitTime            int64      `json:"waitTime"`
 }
 
 type GmailInteractionRequest struct {
@@ -224,6 +373,38 @@ type GmailInteractionRequest struct {
 	} `json:"message"`
 }
 
+// GmailNotificationData is the base64 data of a Gmail push notification.
+type GmailNotificationData struct {
+	EmailAddress string `json:"emailAddress"`
+	HistoryId    uint64 `json:"historyId"`
+}
+
+// InboundEmail is a received email whatever the mail provider. MessageId is
+// the provider id used to skip stored emails, Mailbox is our address. Message
+// is the plain-text body, HtmlMessage the sanitized HTML one when the email
+// has it.
+type InboundEmail struct {
+	MessageId   string
+	ThreadId    string
+	Subject     string
+	From        string
+	Date        string
+	Message     string
+	HtmlMessage string
+	Mailbox     string
+	Attachments []InboundEmailAttachment
+}
+
+// InboundEmailAttachment is an attachment of a received email that is already
+// kept in the attachment store.
+type InboundEmailAttachment struct {
+	Name        string
+	ContentType string
+	ContentId   string
+	Size        int64
+	Url         string
+}
+
 type GetMentionCommentDetailResp struct {
 	MentionedComment MentionedCommentDetail `json:"mentioned_comment"`
 	PlatformId       string                 `json:"id"`
@@ -265,30 +446,34 @@ type GetGeotagInformation struct {
 }
 
 type InteractionWithLatestMessage struct {
-	InteractionId          uint      `json:"interaction_id"`
-	InteractionCreatedAt   time.Time `json:"interaction_created_at"`
-	PlatformId             string    `json:"platform_id"`
-	ReporterId             uint      `json:"reporter_id"`
-	ReporterName           string    `json:"reporter_name"`
-	ConversationId         string    `json:"conversation_id"`
-	MentionMediaId         string    `json:"media_id"`
-	MentionMediaUrl        string    `json:"media_url"`
-	AgentId                string    `json:"agent_id"`
-	Status                 string    `json:"status"`
-	Platform               string    `json:"platform"`
-	InteractionType        string    `json:"interaction_type"`
-	Latitude               string    `json:"Latitude"`
-	Longitude              string    `json:"Longitude"`
-	LatestMessageId        uint      `json:"latest_message_id"`
-	LatestMessageCreatedAt time.Time `json:"latest_message_created_at"`
-	SenderId               string    `json:"sender_id"`
-	RecipientId            string    `json:"recipient_id"`
-	MetaMessageId          string    `json:"mid"`
-	Message                string    `json:"message"`
-	AttachmentType         string    `json:"attachment_type"`
-	AttachmentUrl          string    `json:"attachment_url"`
-	SentBy                 string    `json:"sent_by"`
-	IsRead                 bool      `json:"is_read"`
+	InteractionId          uint       `json:"interaction_id"`
+	InteractionCreatedAt   time.Time  `json:"interaction_created_at"`
+	InteractionUpdatedAt   time.Time  `json:"interaction_updated_at"`
+	InteractionClosedAt    *time.Time `json:"interaction_closed_at"`
+	PlatformId             string     `json:"platform_id"`
+	ReporterId             uint       `json:"reporter_id"`
+	ReporterName           string     `json:"reporter_name"`
+	ConversationId         string     `json:"conversation_id"`
+	MentionMediaId         string     `json:"media_id"`
+	MentionMediaUrl        string     `json:"media_url"`
+	AgentId                string     `json:"agent_id"`
+	Status                 string     `json:"status"`
+	Platform               string     `json:"platform"`
+	InteractionType        string     `json:"interaction_type"`
+	Latitude               string     `json:"Latitude"`
+	Longitude              string     `json:"Longitude"`
+	LatestMessageId        uint       `json:"latest_message_id"`
+	LatestMessageCreatedAt time.Time  `json:"latest_message_created_at"`
+	SenderId               string     `json:"sender_id"`
+	RecipientId            string     `json:"recipient_id"`
+	MetaMessageId          string     `json:"mid"`
+	Message                string     `json:"message"`
+	AttachmentType         string     `json:"attachment_type"`
+	AttachmentUrl          string     `json:"attachment_url"`
+	SentBy                 string     `json:"sent_by"`
+	IsRead                 bool       `json:"is_read"`
+
+	Sla *InteractionSla `json:"sla" gorm:"-"`
 }
 
 func (cir *ClaimInteractionRequest) ValidatePayload() map[string]string {
@@ -303,6 +488,55 @@ func (cir *ClaimInteractionRequest) ValidatePayload() map[string]string {
 	return errorMessage
 }
 
+func (tir *TransferInteractionRequest) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if tir.InteractionId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func (socr *StartOutboundConversationRequest) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if socr.ReporterId == 0 || socr.Platform == "" {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	if socr.TemplateName != "" {
+		if socr.Platform != enum.WA {
+			errorMessage["errorStatus"] = enum.INVALID_PARAMETER_STATUS
+			errorMessage["errorMessage"] = enum.INVALID_PARAMETER_MESSAGE
+			return errorMessage
+		}
+
+		if socr.Language == "" {
+			errorMessage["errorStatus"] = enum.TEMPLATE_NAME_REQUIRED_STATUS
+			errorMessage["errorMessage"] = enum.TEMPLATE_NAME_REQUIRED_MESSAGE
+			return errorMessage
+		}
+
+	} else if socr.Message == "" {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	if socr.Platform == enum.EMAIL && socr.Subject == "" {
+		errorMessage["errorStatus"] = enum.SUBJECT_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.SUBJECT_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
 func ParseGetListInteractionFilters(c *gin.Context) (map[string]interface{}, error) {
 	filters := make(map[string]interface{})
 
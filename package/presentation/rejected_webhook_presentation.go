@@ -0,0 +1,33 @@
+package presentation
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ParseGetListRejectedWebhookFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	platformQuery := c.Query("platform")
+	reasonQuery := c.Query("reason")
+	sinceQuery := c.Query("since")
+
+	if platformQuery != "" {
+		filters["platform"] = platformQuery
+	}
+
+	if reasonQuery != "" {
+		filters["reason"] = reasonQuery
+	}
+
+	if sinceQuery != "" {
+		since, err := time.Parse("2006-01-02", sinceQuery)
+		if err != nil {
+			return nil, err
+		}
+		filters["since"] = since
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,51 @@
+package presentation
+
+import "Omnichannel-CRM/package/enum"
+
+type RoutingPolicyModel struct {
+	ChannelAccountId uint   `json:"channel_account_id"`
+	Strategy         string `json:"strategy"`
+	AgentIds         string `json:"agent_ids"`
+}
+
+type UpdateRoutingPolicyModel struct {
+	RoutingPolicyId uint   `json:"routing_policy_id"`
+	Strategy        string `json:"strategy"`
+	AgentIds        string `json:"agent_ids"`
+}
+
+type DeleteRoutingPolicyModel struct {
+	RoutingPolicyId uint `json:"routing_policy_id"`
+}
+
+func ValidateRoutingStrategy(strategy string) map[string]string {
+	errorMessage := make(map[string]string)
+
+	if strategy != enum.ROUND_ROBIN && strategy != enum.LEAST_LOADED && strategy != enum.STICKY {
+		errorMessage["errorStatus"] = enum.INVALID_ROUTING_STRATEGY_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_ROUTING_STRATEGY_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func (rpm *RoutingPolicyModel) ValidatePayload() map[string]string {
+	return ValidateRoutingStrategy(rpm.Strategy)
+}
+
+func (urpm *UpdateRoutingPolicyModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if urpm.RoutingPolicyId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	if urpm.Strategy != "" {
+		return ValidateRoutingStrategy(urpm.Strategy)
+	}
+
+	return errorMessage
+}
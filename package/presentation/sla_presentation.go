@@ -0,0 +1,126 @@
+package presentation
+
+import (
+	"Omnichannel-CRM/package/enum"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SlaPolicyModel struct {
+	Platform             string `json:"platform"`
+	InteractionType      string `json:"interaction_type"`
+	FirstResponseMinutes int64  `json:"first_response_minutes"`
+	ResolutionMinutes    int64  `json:"resolution_minutes"`
+	NearBreachPercent    int64  `json:"near_breach_percent"`
+}
+
+type UpdateSlaPolicyModel struct {
+	SlaPolicyId          uint  `json:"sla_policy_id"`
+	FirstResponseMinutes int64 `json:"first_response_minutes"`
+	ResolutionMinutes    int64 `json:"resolution_minutes"`
+	NearBreachPercent    int64 `json:"near_breach_percent"`
+}
+
+type DeleteSlaPolicyModel struct {
+	SlaPolicyId uint `json:"sla_policy_id"`
+}
+
+type InteractionFirstMessage struct {
+	InteractionId          uint       `json:"interaction_id"`
+	FirstReporterMessageAt *time.Time `json:"first_reporter_message_at"`
+	FirstAgentMessageAt    *time.Time `json:"first_agent_message_at"`
+}
+
+type InteractionSla struct {
+	SlaPolicyId             uint       `json:"sla_policy_id"`
+	FirstResponseDeadline   *time.Time `json:"first_response_deadline"`
+	FirstResponseAt         *time.Time `json:"first_response_at"`
+	FirstResponseBreached   bool       `json:"first_response_breached"`
+	FirstResponseNearBreach bool       `json:"first_response_near_breach"`
+	ResolutionDeadline      *time.Time `json:"resolution_deadline"`
+	ResolutionBreached      bool       `json:"resolution_breached"`
+	ResolutionNearBreach    bool       `json:"resolution_near_breach"`
+}
+
+type SlaEscalationNotification struct {
+	EscalationId   uint      `json:"escalation_id"`
+	InteractionId  uint      `json:"interaction_id"`
+	AgentId        string    `json:"agent_id"`
+	EscalationType string    `json:"escalation_type"`
+	Deadline       time.Time `json:"deadline"`
+}
+
+func ValidatePlatform(platform string) bool {
+	return platform == enum.FACEBOOK || platform == enum.IG || platform == enum.WA || platform == enum.EMAIL || platform == enum.LIVE_CHAT
+}
+
+func ValidateSlaTimers(firstResponseMinutes, resolutionMinutes, nearBreachPercent int64) map[string]string {
+	errorMessage := make(map[string]string)
+
+	if firstResponseMinutes < 0 || resolutionMinutes < 0 {
+		errorMessage["errorStatus"] = enum.INVALID_SLA_TIMER_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_SLA_TIMER_MESSAGE
+		return errorMessage
+	}
+
+	if nearBreachPercent < 0 || nearBreachPercent >= 100 {
+		errorMessage["errorStatus"] = enum.INVALID_NEAR_BREACH_PERCENT_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_NEAR_BREACH_PERCENT_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func (spm *SlaPolicyModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if !ValidatePlatform(spm.Platform) {
+		errorMessage["errorStatus"] = enum.FAILED_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_PLATFORM_MSG
+		return errorMessage
+	}
+
+	if spm.FirstResponseMinutes == 0 && spm.ResolutionMinutes == 0 {
+		errorMessage["errorStatus"] = enum.INVALID_SLA_TIMER_STATUS
+		errorMessage["errorMessage"] = enum.INVALID_SLA_TIMER_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateSlaTimers(spm.FirstResponseMinutes, spm.ResolutionMinutes, spm.NearBreachPercent)
+}
+
+func (uspm *UpdateSlaPolicyModel) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if uspm.SlaPolicyId == 0 {
+		errorMessage["errorStatus"] = enum.FIELD_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.FIELD_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return ValidateSlaTimers(uspm.FirstResponseMinutes, uspm.ResolutionMinutes, uspm.NearBreachPercent)
+}
+
+func ParseGetListSlaEscalationFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	interactionIdQuery := c.Query("interaction_id")
+	agentIdQuery := c.Query("agent_id")
+
+	if interactionIdQuery != "" {
+		interactionId, err := strconv.ParseUint(interactionIdQuery, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		filters["interaction_id"] = uint(interactionId)
+	}
+
+	if agentIdQuery != "" {
+		filters["agent_id"] = agentIdQuery
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,45 @@
+package presentation
+
+type TelegramUpdate struct {
+	UpdateId int64            `json:"update_id"`
+	Message  *TelegramMessage `json:"message"`
+}
+
+type TelegramMessage struct {
+	MessageId int64             `json:"message_id"`
+	From      TelegramUser      `json:"from"`
+	Chat      TelegramChat      `json:"chat"`
+	Date      int64             `json:"date"`
+	Text      string            `json:"text"`
+	Caption   string            `json:"caption"`
+	Location  *TelegramLocation `json:"location"`
+}
+
+type TelegramUser struct {
+	Id        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+}
+
+type TelegramChat struct {
+	Id   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type TelegramLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type TelegramSendMessageRequest struct {
+	ChatId string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type TelegramSendMessageResponse struct {
+	Ok          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      TelegramMessage `json:"result"`
+}
@@ -0,0 +1,37 @@
+package presentation
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReplayWebhookInboxModel struct {
+	WebhookInboxId uint `json:"webhook_inbox_id"`
+}
+
+func ParseGetListWebhookInboxFilters(c *gin.Context) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	platformQuery := c.Query("platform")
+	statusQuery := c.Query("status")
+	sinceQuery := c.Query("since")
+
+	if platformQuery != "" {
+		filters["platform"] = platformQuery
+	}
+
+	if statusQuery != "" {
+		filters["status"] = statusQuery
+	}
+
+	if sinceQuery != "" {
+		since, err := time.Parse("2006-01-02", sinceQuery)
+		if err != nil {
+			return nil, err
+		}
+		filters["since"] = since
+	}
+
+	return filters, nil
+}
@@ -1,7 +1,5 @@
 package presentation
 
-import "time"
-
 type WhatsappInteractionRequest struct {
 	Object string               `json:"object"`
 	Entry  []WhatsappEntryField `json:"entry"`
@@ -23,7 +21,7 @@ type WhatsappValue struct {
 	Contacts         []WhatsappContacts `json:"contacts"`
 	Errors           []WhatsappErrors   `json:"errors"`
 	Messages         []WhatsappMessages `json:"messages"`
-	Statuses         interface{}        `json:"statuses"`
+	Statuses         []WhatsappStatuses `json:"statuses"`
 }
 
 type WhatsappMessages struct {
@@ -31,6 +29,8 @@ type WhatsappMessages struct {
 	Text      WhatsappText     `json:"text"`
 	Image     WhatsappImage    `json:"image"`
 	Video     WhatsappVideo    `json:"video"`
+	Audio     WhatsappAudio    `json:"audio"`
+	Document  WhatsappDocument `json:"document"`
 	Location  WhatsappLocation `json:"location"`
 	Timestamp string           `json:"timestamp"`
 	Type      string           `json:"type"`
@@ -41,8 +41,10 @@ type WhatsappText struct {
 }
 
 type WhatsappLocation struct {
-	Latitude float64 `json:"latitude"`
+	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
 }
 
 type WhatsappImage struct {
@@ -59,12 +61,30 @@ type WhatsappVideo struct {
 	Id       string `json:"id"`
 	MimeType string `json:"mime_type"`
 }
+
+type WhatsappAudio struct {
+	Sha256   string `json:"sha256"`
+	Id       string `json:"id"`
+	MimeType string `json:"mime_type"`
+}
+
+type WhatsappDocument struct {
+	Caption  string `json:"caption"`
+	Filename string `json:"filename"`
+	Sha256   string `json:"sha256"`
+	Id       string `json:"id"`
+	MimeType string `json:"mime_type"`
+}
+
+// WhatsappStatuses is the sent, delivered, read or failed status of a message
+// sent by the business, ID is the wamid returned when sending it.
 type WhatsappStatuses struct {
-	ID          string         `json:"id"`
-	RecipientId string         `json:"recipient_id"`
-	Status      string         `json:"status"`
-	Errors      WhatsappErrors `json:"errors"`
-	Timestamp   time.Time      `json:"timestamp"`
+	ID           string                `json:"id"`
+	RecipientId  string                `json:"recipient_id"`
+	Status       string                `json:"status"`
+	Timestamp    string                `json:"timestamp"`
+	Conversation *WhatsappConversation `json:"conversation"`
+	Errors       []WhatsappErrors      `json:"errors"`
 }
 
 type WhatsappConversation struct {
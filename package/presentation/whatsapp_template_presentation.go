@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"Omnichannel-CRM/package/enum"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WhatsappTemplateMetaField struct {
+	Name       string                           `json:"name"`
+	Language   WhatsappTemplateLanguageField    `json:"language"`
+	Components []WhatsappTemplateComponentField `json:"components,omitempty"`
+}
+
+type WhatsappTemplateLanguageField struct {
+	Code string `json:"code"`
+}
+
+type WhatsappTemplateComponentField struct {
+	Type       string                           `json:"type"`
+	Parameters []WhatsappTemplateParameterField `json:"parameters"`
+}
+
+type WhatsappTemplateParameterField struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type WhatsappTemplateListResp struct {
+	Data   []WhatsappTemplateData `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+type WhatsappTemplateData struct {
+	Id         string                          `json:"id"`
+	Name       string                          `json:"name"`
+	Language   string                          `json:"language"`
+	Status     string                          `json:"status"`
+	Category   string                          `json:"category"`
+	Components []WhatsappTemplateDataComponent `json:"components"`
+}
+
+type WhatsappTemplateDataComponent struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+type SyncWhatsappTemplateModel struct {
+	ChannelAccountId uint `json:"channel_account_id"`
+}
+
+type SendWhatsappTemplateRequest struct {
+	InteractionId uint     `json:"interaction_id"`
+	PlatformId    string   `json:"platform_id"`
+	ReporterId    uint     `json:"reporter_id"`
+	TemplateName  string   `json:"template_name"`
+	Language      string   `json:"language"`
+	Parameters    []string `json:"parameters"`
+}
+
+func (swtr *SendWhatsappTemplateRequest) ValidatePayload() map[string]string {
+	errorMessage := make(map[string]string)
+
+	if swtr.TemplateName == "" || swtr.Language == "" {
+		errorMessage["errorStatus"] = enum.TEMPLATE_NAME_REQUIRED_STATUS
+		errorMessage["errorMessage"] = enum.TEMPLATE_NAME_REQUIRED_MESSAGE
+		return errorMessage
+	}
+
+	return errorMessage
+}
+
+func ParseGetListWhatsappTemplateFilters(c *gin.Context) map[string]interface{} {
+	filters := make(map[string]interface{})
+
+	whatsappBusinessIdQuery := c.Query("whatsapp_business_id")
+	statusQuery := c.Query("status")
+	nameQuery := c.Query("name")
+
+	if whatsappBusinessIdQuery != "" {
+		filters["whatsapp_business_id"] = whatsappBusinessIdQuery
+	}
+
+	if statusQuery != "" {
+		filters["status"] = statusQuery
+	}
+
+	if nameQuery != "" {
+		filters["name"] = nameQuery
+	}
+
+	return filters
+}
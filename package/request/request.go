@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"strings"
 )
 
 func PostRequest(url url.URL, requestBody interface{}, authToken string) (*http.Response, error) {
@@ -39,6 +42,60 @@ func PostRequest(url url.URL, requestBody interface{}, authToken string) (*http.
 	return resp, nil
 }
 
+// PostMultipartRequest posts the fields and a file as multipart/form-data, the
+// file part keeps its content type since some APIs read the media type from it.
+func PostMultipartRequest(url url.URL, fields map[string]string, fileField string, fileName string, contentType string, content []byte, authToken string) (*http.Response, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key, value := range fields {
+		err := writer.WriteField(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, fileField, strings.ReplaceAll(fileName, `"`, "")))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = part.Write(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if authToken != "" {
+		bearerToken := fmt.Sprintf("Bearer %s", authToken)
+		request.Header.Set("Authorization", bearerToken)
+	}
+
+	httpClient := &http.Client{}
+
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func GetRequest(url url.URL, authToken string) (*http.Response, error) {
 	request, err := http.NewRequest(http.MethodGet, url.String(), nil)
 	if err != nil {
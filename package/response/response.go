@@ -131,3 +131,20 @@ func ResponseInternalServerError(c *gin.Context, data interface{}, errorMessage
 
 	c.IndentedJSON(http.StatusInternalServerError, response)
 }
+
+func ResponseServiceUnavailable(c *gin.Context, data interface{}, errorMessage map[string]string) {
+	isError := false
+
+	if errorMessage["errorStatus"] != "" {
+		isError = true
+	}
+
+	response := ResponseBase{
+		IsError:      isError,
+		Data:         data,
+		ErrorStatus:  errorMessage["errorStatus"],
+		ErrorMessage: errorMessage["errorMessage"],
+	}
+
+	c.IndentedJSON(http.StatusServiceUnavailable, response)
+}
@@ -29,7 +29,12 @@ type ResponseData struct {
 }
 
 func RefreshToken() (accessToken string, err error) {
-	refreshToken := viper.GetString("OAuth.Refresh_Token")
+	return RefreshAccessToken(viper.GetString("OAuth.Refresh_Token"))
+}
+
+// RefreshAccessToken exchanges the refresh token of a mailbox for an access
+// token of the OAuth client.
+func RefreshAccessToken(refreshToken string) (accessToken string, err error) {
 	clientSecret := viper.GetString("OAuth.Client_Secret")
 	clientId := viper.GetString("OAuth.Client_Id")
 
@@ -81,6 +86,25 @@ func NewGmailService() *gmail.Service {
 	if err != nil {
 		accessToken = viper.GetString("OAuth.Access_Token")
 	}
+
+	return newGmailServiceWithAccessToken(accessToken)
+}
+
+// NewGmailServiceByRefreshToken creates the Gmail service of the mailbox that
+// granted the refresh token.
+func NewGmailServiceByRefreshToken(refreshToken string) (*gmail.Service, error) {
+	accessToken, err := RefreshAccessToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken == "" {
+		return nil, fmt.Errorf("refresh token was not accepted by the OAuth client")
+	}
+
+	return newGmailServiceWithAccessToken(accessToken), nil
+}
+
+func newGmailServiceWithAccessToken(accessToken string) *gmail.Service {
 	// Create an OAuth2 token source using the access token
 	tokenSource := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: accessToken},
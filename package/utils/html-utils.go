@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedHtmlTags are the formatting tags kept by SanitizeHtml. Other tags are
+// dropped but their text is kept.
+var allowedHtmlTags = map[atom.Atom]bool{
+	atom.A: true, atom.B: true, atom.Blockquote: true, atom.Br: true, atom.Center: true,
+	atom.Code: true, atom.Div: true, atom.Em: true, atom.Font: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Hr: true, atom.I: true, atom.Img: true, atom.Li: true, atom.Ol: true,
+	atom.P: true, atom.Pre: true, atom.S: true, atom.Small: true, atom.Span: true,
+	atom.Strong: true, atom.Sub: true, atom.Sup: true, atom.Table: true, atom.Tbody: true,
+	atom.Td: true, atom.Tfoot: true, atom.Th: true, atom.Thead: true, atom.Tr: true,
+	atom.U: true, atom.Ul: true,
+}
+
+// droppedHtmlTags are removed together with their content, void tags are left
+// out since they would never be closed.
+var droppedHtmlTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Title: true, atom.Iframe: true,
+	atom.Frameset: true, atom.Object: true, atom.Applet: true, atom.Noscript: true,
+	atom.Template: true, atom.Svg: true, atom.Math: true, atom.Form: true,
+	atom.Textarea: true, atom.Select: true, atom.Button: true,
+}
+
+var allowedHtmlAttributes = map[string]bool{
+	"href": true, "src": true, "alt": true, "title": true, "width": true,
+	"height": true, "align": true, "valign": true, "colspan": true, "rowspan": true,
+	"color": true, "border": true, "cellpadding": true, "cellspacing": true,
+}
+
+// blockHtmlTags start a new line in the text of HtmlToText.
+var blockHtmlTags = map[atom.Atom]bool{
+	atom.Blockquote: true, atom.Br: true, atom.Div: true, atom.H1: true, atom.H2: true,
+	atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Hr: true,
+	atom.Li: true, atom.P: true, atom.Pre: true, atom.Table: true, atom.Tr: true,
+}
+
+// safeHtmlUrl tells if the url of a link or an image can be kept. Relative urls
+// are dropped since they point to nowhere outside of the email.
+func safeHtmlUrl(rawUrl string) bool {
+	parsedUrl, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(parsedUrl.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
+
+// SanitizeHtml keeps the formatting of an HTML email and removes what could
+// run in the dashboard: scripts, styles, frames, forms, event handlers and
+// javascript urls. Images referring to an inline part (cid:) are pointed to the
+// url of the part in inlineUrls, keyed by Content-ID.
+func SanitizeHtml(rawHtml string, inlineUrls map[string]string) string {
+	var sanitized strings.Builder
+	droppedDepth := 0
+
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(rawHtml))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedHtmlTags[token.DataAtom] {
+				if tokenType == nethtml.StartTagToken {
+					droppedDepth++
+				}
+				continue
+			}
+			if droppedDepth > 0 || !allowedHtmlTags[token.DataAtom] {
+				continue
+			}
+
+			sanitized.WriteString("<" + token.Data)
+			for _, attribute := range token.Attr {
+				key := strings.ToLower(attribute.Key)
+				if !allowedHtmlAttributes[key] {
+					continue
+				}
+
+				value := attribute.Val
+				if key == "href" || key == "src" {
+					if contentId := strings.TrimPrefix(value, "cid:"); key == "src" && contentId != value {
+						value = inlineUrls[strings.Trim(contentId, "<>")]
+						if value == "" {
+							continue
+						}
+
+					} else if !safeHtmlUrl(value) {
+						continue
+					}
+				}
+
+				sanitized.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+			}
+			if token.DataAtom == atom.A {
+				sanitized.WriteString(` target="_blank" rel="noopener noreferrer"`)
+			}
+			sanitized.WriteString(">")
+
+		case nethtml.EndTagToken:
+			if droppedHtmlTags[token.DataAtom] {
+				if droppedDepth > 0 {
+					droppedDepth--
+				}
+				continue
+			}
+			if droppedDepth > 0 || !allowedHtmlTags[token.DataAtom] || token.DataAtom == atom.Br || token.DataAtom == atom.Hr || token.DataAtom == atom.Img {
+				continue
+			}
+
+			sanitized.WriteString("</" + token.Data + ">")
+
+		case nethtml.TextToken:
+			if droppedDepth > 0 {
+				continue
+			}
+
+			sanitized.WriteString(html.EscapeString(token.Data))
+		}
+	}
+
+	return strings.TrimSpace(sanitized.String())
+}
+
+var (
+	repeatedSpaces   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	repeatedNewlines = regexp.MustCompile(`\n{3,}`)
+)
+
+// HtmlToText returns the text of an HTML email, with a line for each block
+// and the urls of the links after their text.
+func HtmlToText(rawHtml string) string {
+	var text strings.Builder
+	droppedDepth := 0
+	var href string
+
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(rawHtml))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedHtmlTags[token.DataAtom] {
+				if tokenType == nethtml.StartTagToken {
+					droppedDepth++
+				}
+				continue
+			}
+			if blockHtmlTags[token.DataAtom] {
+				text.WriteString("\n")
+			}
+			if token.DataAtom == atom.A {
+				href = ""
+				for _, attribute := range token.Attr {
+					if attribute.Key == "href" && safeHtmlUrl(attribute.Val) {
+						href = attribute.Val
+					}
+				}
+			}
+
+		case nethtml.EndTagToken:
+			if droppedHtmlTags[token.DataAtom] {
+				if droppedDepth > 0 {
+					droppedDepth--
+				}
+				continue
+			}
+			if blockHtmlTags[token.DataAtom] {
+				text.WriteString("\n")
+			}
+			if token.DataAtom == atom.A && href != "" {
+				text.WriteString(" (" + href + ")")
+				href = ""
+			}
+
+		case nethtml.TextToken:
+			if droppedDepth > 0 {
+				continue
+			}
+
+			text.WriteString(strings.ReplaceAll(token.Data, "\n", " "))
+		}
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(repeatedSpaces.ReplaceAllString(line, " "))
+	}
+
+	return strings.TrimSpace(repeatedNewlines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,88 @@
+package utils
+
+import "testing"
+
+func TestSanitizeHtml(t *testing.T) {
+	inlineUrls := map[string]string{"logo@mail": "/files/attachments/email/logo.png"}
+
+	tests := []struct {
+		name    string
+		rawHtml string
+		want    string
+	}{
+		{
+			name:    "script removed with its content",
+			rawHtml: `<p>hello</p><script>alert(1)</script><p>bye</p>`,
+			want:    `<p>hello</p><p>bye</p>`,
+		},
+		{
+			name:    "style removed with its content",
+			rawHtml: `<style>p { color: red }</style><p>hello</p>`,
+			want:    `<p>hello</p>`,
+		},
+		{
+			name:    "iframe removed with its content",
+			rawHtml: `<div><iframe src="https://example.com">frame</iframe>text</div>`,
+			want:    `<div>text</div>`,
+		},
+		{
+			name:    "event handlers removed",
+			rawHtml: `<img src="https://example.com/a.png" onerror="alert(1)" onload="alert(2)"><b onclick="alert(3)">bold</b>`,
+			want:    `<img src="https://example.com/a.png"><b>bold</b>`,
+		},
+		{
+			name:    "javascript href removed",
+			rawHtml: `<a href="javascript:alert(1)">link</a>`,
+			want:    `<a target="_blank" rel="noopener noreferrer">link</a>`,
+		},
+		{
+			name:    "javascript href with uppercase scheme removed",
+			rawHtml: `<a href="JavaScript:alert(1)">link</a>`,
+			want:    `<a target="_blank" rel="noopener noreferrer">link</a>`,
+		},
+		{
+			name:    "href with control characters removed",
+			rawHtml: "<a href=\"java\tscript:alert(1)\">tab</a><a href=\"\x01javascript:alert(1)\">control</a>",
+			want:    `<a target="_blank" rel="noopener noreferrer">tab</a><a target="_blank" rel="noopener noreferrer">control</a>`,
+		},
+		{
+			name:    "http link kept",
+			rawHtml: `<a href="https://example.com/?a=1&b=2">link</a>`,
+			want:    `<a href="https://example.com/?a=1&amp;b=2" target="_blank" rel="noopener noreferrer">link</a>`,
+		},
+		{
+			name:    "cid image pointed to the inline part",
+			rawHtml: `<img src="cid:logo@mail" alt="logo">`,
+			want:    `<img src="/files/attachments/email/logo.png" alt="logo">`,
+		},
+		{
+			name:    "unknown cid image loses its src",
+			rawHtml: `<img src="cid:missing@mail" alt="missing">`,
+			want:    `<img alt="missing">`,
+		},
+		{
+			name:    "unclosed script drops the rest",
+			rawHtml: `<p>hello</p><script>alert(1)<p>hidden</p>`,
+			want:    `<p>hello</p>`,
+		},
+		{
+			name:    "unclosed style drops the rest",
+			rawHtml: `<p>hello</p><style><p>hidden</p>`,
+			want:    `<p>hello</p>`,
+		},
+		{
+			name:    "unknown tags dropped but their text kept",
+			rawHtml: `<custom>text</custom><span>kept</span>`,
+			want:    `text<span>kept</span>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeHtml(tt.rawHtml, inlineUrls)
+			if got != tt.want {
+				t.Errorf("SanitizeHtml() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
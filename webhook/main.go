@@ -19,6 +19,12 @@ func init() {
 func main() {
 	logger.InitLogger()
 
+	dbCRM, err := database.InitDB(viper.GetString("Database.CRMDBName"), viper.GetString("Database.CRMDBHost"))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	dbOmnichannel, err := database.InitDB(viper.GetString("Database.OmnichannelDBName"), viper.GetString("Database.Host"))
 	if err != nil {
 		log.Fatal(err)
@@ -29,7 +35,7 @@ func main() {
 	flag.IntVar(&port, "port", viper.GetInt("Webhook.Port"), "Port to run the server on")
 	flag.Parse()
 
-	app := api.SetupWebhookRouter(dbOmnichannel)
+	app := api.SetupWebhookRouter(dbCRM, dbOmnichannel)
 
 	app.Run(fmt.Sprintf(":%d", port))
 }